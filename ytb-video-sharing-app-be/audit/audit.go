@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"os"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// NewAuditLogger returns the audit logger selected by AUDIT_LOG_DRIVER, "log" or "database" (default).
+// Audit endpoints read the database one, events of the log one only end up in the standard output.
+func NewAuditLogger(auditEventRepository repository.AuditEventRepository) pkg.AuditLogger {
+	switch os.Getenv("AUDIT_LOG_DRIVER") {
+	case "log":
+		return NewLogAuditLogger()
+	default:
+		return NewDatabaseAuditLogger(auditEventRepository)
+	}
+}
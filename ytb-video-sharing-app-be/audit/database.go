@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// databaseAuditLogger appends audit events to audit_events table.
+type databaseAuditLogger struct {
+	auditEventRepository repository.AuditEventRepository
+}
+
+func NewDatabaseAuditLogger(auditEventRepository repository.AuditEventRepository) pkg.AuditLogger {
+	return &databaseAuditLogger{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+// Log implements pkg.AuditLogger.
+func (d *databaseAuditLogger) Log(ctx context.Context, event *pkg.AuditEvent) error {
+	return d.auditEventRepository.Create(ctx, &entities.AuditEvent{
+		Action:     truncate(event.Action, 64),
+		ActorID:    sql.NullInt64{Int64: event.ActorID, Valid: event.ActorID != 0},
+		TargetType: truncate(event.TargetType, 32),
+		TargetID:   sql.NullInt64{Int64: event.TargetID, Valid: event.TargetID != 0},
+		Detail:     truncate(event.Detail, 1000),
+		IPAddress:  truncate(event.IPAddress, 45),
+		UserAgent:  truncate(event.UserAgent, 512),
+		RequestID:  truncate(event.RequestID, 64),
+	})
+}
+
+// truncate cuts s to fit a column of maxChars characters.
+func truncate(s string, maxChars int) string {
+	if runes := []rune(s); len(runes) > maxChars {
+		return string(runes[:maxChars])
+	}
+
+	return s
+}
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"os"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// logAuditLogger writes audit events as lines of the standard logger.
+type logAuditLogger struct {
+	logger *log.Logger
+}
+
+func NewLogAuditLogger() pkg.AuditLogger {
+	return &logAuditLogger{
+		logger: log.New(os.Stdout, "[AUDIT] ", log.LstdFlags|log.LUTC),
+	}
+}
+
+// Log implements pkg.AuditLogger.
+func (l *logAuditLogger) Log(ctx context.Context, event *pkg.AuditEvent) error {
+	l.logger.Printf("action=%s actor_id=%d target=%s:%d detail=%q ip=%s request_id=%s",
+		event.Action, event.ActorID, event.TargetType, event.TargetID, event.Detail, event.IPAddress, event.RequestID)
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"time"
+	"ytb-video-sharing-app-be/audit"
 	"ytb-video-sharing-app-be/db"
 	_ "ytb-video-sharing-app-be/docs"
 	"ytb-video-sharing-app-be/internal/handler"
@@ -16,6 +17,11 @@ import (
 	"ytb-video-sharing-app-be/internal/routes"
 	"ytb-video-sharing-app-be/internal/service"
 	"ytb-video-sharing-app-be/internal/websock"
+	"ytb-video-sharing-app-be/mail"
+	"ytb-video-sharing-app-be/oidc"
+	"ytb-video-sharing-app-be/revocation"
+	"ytb-video-sharing-app-be/storage"
+	"ytb-video-sharing-app-be/throttle"
 	"ytb-video-sharing-app-be/utils"
 
 	"github.com/gin-contrib/cors"
@@ -56,13 +62,19 @@ func NewGinEngine() *gin.Engine {
 	router.Use(cors.New(cors.Config{
 		AllowMethods:     []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace},
 		AllowHeaders:     []string{"*"},
+		ExposeHeaders:    []string{"Retry-After", "X-Request-ID"},
 		AllowCredentials: true,
 		AllowOrigins:     []string{"*"},
 		MaxAge:           12 * time.Hour,
 	}))
 
+	router.Use(middleware.RequestMetadata())
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// serve blobs stored by local blob store, e.g. avatars
+	router.Static("/static", os.Getenv("BLOB_STORAGE_DIR"))
+
 	return router
 }
 
@@ -89,6 +101,69 @@ func StartServer(lifecycle fx.Lifecycle, r *routes.Router) {
 	})
 }
 
+// WatchKeys reloads jwt keys from JWT_KEYS_DIR while the app is running.
+func WatchKeys(lifecycle fx.Lifecycle, keyManager *utils.KeyManager) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			interval, err := time.ParseDuration(os.Getenv("JWT_KEYS_RELOAD_INTERVAL"))
+			if err != nil || interval <= 0 {
+				interval = time.Minute // default to 1 minute if not set or invalid
+			}
+
+			go keyManager.Watch(ctx, interval)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// StartPurgeJob purges soft deleted accounts and videos past their retention period while the app is running.
+func StartPurgeJob(lifecycle fx.Lifecycle, purgeService service.PurgeService) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			interval, err := time.ParseDuration(os.Getenv("SOFT_DELETE_PURGE_INTERVAL"))
+			if err != nil || interval <= 0 {
+				interval = time.Hour // default to 1 hour if not set or invalid
+			}
+
+			go purgeService.Run(ctx, interval)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+// StartTrendingJob recomputes trending scores of videos while the app is running.
+func StartTrendingJob(lifecycle fx.Lifecycle, trendingService service.TrendingService) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			interval, err := time.ParseDuration(os.Getenv("TRENDING_RECOMPUTE_INTERVAL"))
+			if err != nil || interval <= 0 {
+				interval = 10 * time.Minute // default to 10 minutes if not set or invalid
+			}
+
+			go trendingService.Run(ctx, interval)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
 func NewRetention() websock.RetentionMap {
 	return websock.NewRetentionMap(context.Background(), 1*time.Minute)
 }
@@ -133,14 +208,61 @@ func main() {
 	app := fx.New(
 		fx.Provide(
 			db.NewMySQL,
+			storage.NewLocalBlobStore,
+			mail.NewMailer,
+			audit.NewAuditLogger,
 			repository.NewAccountRepository,
 			repository.NewAccountPasswordRepository,
 			repository.NewRefreshTokenRepository,
 			repository.NewVideoRepository,
+			repository.NewFollowRepository,
+			repository.NewPasswordResetTokenRepository,
+			repository.NewRevokedTokenRepository,
+			repository.NewMFARepository,
+			repository.NewLoginAttemptRepository,
+			repository.NewAccountIdentityRepository,
+			repository.NewOIDCLoginStateRepository,
+			repository.NewPersonalAccessTokenRepository,
+			repository.NewVideoReportRepository,
+			repository.NewAuditEventRepository,
+			repository.NewTagRepository,
+			repository.NewPlaylistRepository,
+			repository.NewBookmarkRepository,
+			repository.NewVideoViewRepository,
+			repository.NewVideoCommentRepository,
+			repository.NewVideoScoreRepository,
+			revocation.NewRevocationList,
+			throttle.NewLoginAttemptStore,
+			throttle.NewLoginThrottleFromEnv,
+			oidc.NewIdentityProvider,
 			service.NewAccountService,
 			service.NewVideoService,
+			service.NewFollowService,
+			service.NewPasswordResetService,
+			service.NewMFAService,
+			service.NewPersonalAccessTokenService,
+			service.NewAdminService,
+			service.NewVideoReportService,
+			service.NewAuditService,
+			service.NewPurgeService,
+			service.NewPlaylistService,
+			service.NewBookmarkService,
+			service.NewTrendingService,
+			service.NewVideoCommentService,
 			handler.NewAccountHandler,
 			handler.NewVideoHandler,
+			handler.NewFollowHandler,
+			handler.NewPasswordResetHandler,
+			handler.NewJWKSHandler,
+			handler.NewMFAHandler,
+			handler.NewPersonalAccessTokenHandler,
+			handler.NewAdminHandler,
+			handler.NewVideoReportHandler,
+			handler.NewAuditHandler,
+			handler.NewPlaylistHandler,
+			handler.NewBookmarkHandler,
+			handler.NewTrendingHandler,
+			handler.NewVideoCommentHandler,
 			NewGinEngine,
 			routes.NewRouter,
 			utils.LoadKeys,
@@ -149,8 +271,8 @@ func main() {
 			NewRetention,
 			// third_party.NewQueue,
 		),
-		fx.Invoke(LoadEnv, MigrateDB, utils.LoadKeys),
-		fx.Invoke(StartServer, StartWebSocketServer),
+		fx.Invoke(LoadEnv, MigrateDB, utils.LoadKeys, service.BootstrapAdmin),
+		fx.Invoke(StartServer, StartWebSocketServer, WatchKeys, StartPurgeJob, StartTrendingJob),
 	)
 
 	app.Run()
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id  INT NOT NULL,
+    following_id INT NOT NULL,
+    created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, following_id),
+    INDEX idx_follows_following_id (following_id),
+    FOREIGN KEY (follower_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    FOREIGN KEY (following_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
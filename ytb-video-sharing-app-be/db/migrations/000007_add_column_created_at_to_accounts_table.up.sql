@@ -0,0 +1,2 @@
+ALTER TABLE accounts
+    ADD COLUMN created_at DATETIME DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE accounts
+    ADD COLUMN bio VARCHAR(500) NOT NULL DEFAULT '' AFTER avatarURL;
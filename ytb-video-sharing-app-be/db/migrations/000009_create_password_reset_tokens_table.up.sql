@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    account_id  INT NOT NULL,
+    token_hash  CHAR(64) NOT NULL UNIQUE,
+    expires_at  DATETIME NOT NULL,
+    used_at     DATETIME NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
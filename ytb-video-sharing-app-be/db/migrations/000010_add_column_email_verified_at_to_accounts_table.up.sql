@@ -0,0 +1,5 @@
+ALTER TABLE accounts
+    ADD COLUMN email_verified_at DATETIME NULL;
+
+-- accounts created before verification existed are trusted
+UPDATE accounts SET email_verified_at = created_at;
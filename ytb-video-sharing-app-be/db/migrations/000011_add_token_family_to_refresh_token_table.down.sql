@@ -0,0 +1,8 @@
+-- rotated tokens were kept only for reuse detection
+DELETE FROM refresh_token WHERE used_at IS NOT NULL;
+
+DROP INDEX idx_refresh_token_family_id ON refresh_token;
+
+ALTER TABLE refresh_token
+    DROP COLUMN used_at,
+    DROP COLUMN family_id;
@@ -0,0 +1,8 @@
+ALTER TABLE refresh_token
+    ADD COLUMN family_id CHAR(36) NOT NULL DEFAULT '' AFTER token,
+    ADD COLUMN used_at DATETIME NULL AFTER expires_at;
+
+-- every existing session becomes its own family
+UPDATE refresh_token SET family_id = UUID();
+
+CREATE INDEX idx_refresh_token_family_id ON refresh_token(family_id);
@@ -0,0 +1,7 @@
+-- raw tokens can not be recovered from their digest, every session has to log in again
+DELETE FROM refresh_token;
+
+ALTER TABLE refresh_token
+    ADD COLUMN token VARCHAR(1024) NOT NULL AFTER account_id,
+    DROP INDEX idx_refresh_token_token_hash,
+    DROP COLUMN token_hash;
@@ -0,0 +1,9 @@
+ALTER TABLE refresh_token ADD COLUMN token_hash CHAR(64) NULL AFTER token;
+
+-- hex encoded sha256, same as utils.HashToken
+UPDATE refresh_token SET token_hash = SHA2(token, 256);
+
+ALTER TABLE refresh_token
+    MODIFY token_hash CHAR(64) NOT NULL,
+    ADD UNIQUE INDEX idx_refresh_token_token_hash (token_hash),
+    DROP COLUMN token;
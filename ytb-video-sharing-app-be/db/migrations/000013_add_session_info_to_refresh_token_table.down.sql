@@ -0,0 +1,7 @@
+DROP INDEX idx_refresh_token_account_id_family_id ON refresh_token;
+
+ALTER TABLE refresh_token
+    DROP COLUMN last_used_at,
+    DROP COLUMN ip_address,
+    DROP COLUMN user_agent,
+    DROP COLUMN device_label;
@@ -0,0 +1,9 @@
+ALTER TABLE refresh_token
+    ADD COLUMN device_label VARCHAR(255) NOT NULL DEFAULT '' AFTER family_id,
+    ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '' AFTER device_label,
+    ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '' AFTER user_agent,
+    ADD COLUMN last_used_at DATETIME DEFAULT CURRENT_TIMESTAMP AFTER used_at;
+
+UPDATE refresh_token SET last_used_at = updated_at;
+
+CREATE INDEX idx_refresh_token_account_id_family_id ON refresh_token(account_id, family_id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id            VARCHAR(64) PRIMARY KEY,
+    expires_at    DATETIME NOT NULL,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_revoked_tokens_expires_at (expires_at)
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS account_mfa (
+    account_id      INT PRIMARY KEY,
+    secret          VARCHAR(64) NOT NULL,
+    enabled_at      DATETIME NULL,
+    last_used_step  BIGINT NOT NULL DEFAULT 0,
+    created_at      DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at      DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    account_id  INT NOT NULL,
+    code_hash   CHAR(64) NOT NULL,
+    used_at     DATETIME NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_mfa_recovery_codes_account_code (account_id, code_hash),
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
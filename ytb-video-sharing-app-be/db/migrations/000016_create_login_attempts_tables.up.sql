@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    throttle_key  VARCHAR(320) NOT NULL,
+    attempted_at  DATETIME(3) NOT NULL,
+    INDEX idx_login_attempts_key_attempted_at (throttle_key, attempted_at),
+    INDEX idx_login_attempts_attempted_at (attempted_at)
+);
+
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    throttle_key  VARCHAR(320) PRIMARY KEY,
+    locked_until  DATETIME(3) NOT NULL,
+    INDEX idx_login_lockouts_locked_until (locked_until)
+);
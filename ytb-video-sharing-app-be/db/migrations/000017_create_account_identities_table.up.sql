@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS account_identities (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    account_id  INT NOT NULL,
+    provider    VARCHAR(64) NOT NULL,
+    subject     VARCHAR(255) NOT NULL,
+    email       VARCHAR(255) NOT NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_account_identities_provider_subject (provider, subject),
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state_hash     CHAR(64) PRIMARY KEY,
+    code_verifier  VARCHAR(128) NOT NULL,
+    nonce          VARCHAR(128) NOT NULL,
+    expires_at     DATETIME NOT NULL,
+    created_at     DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_oidc_login_states_expires_at (expires_at)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id            INT AUTO_INCREMENT PRIMARY KEY,
+    account_id    INT NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    token_prefix  VARCHAR(16) NOT NULL,
+    token_hash    CHAR(64) NOT NULL,
+    scopes        VARCHAR(255) NOT NULL,
+    expires_at    DATETIME NULL,
+    last_used_at  DATETIME NULL,
+    revoked_at    DATETIME NULL,
+    created_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_personal_access_tokens_token_hash (token_hash),
+    INDEX idx_personal_access_tokens_account_id (account_id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
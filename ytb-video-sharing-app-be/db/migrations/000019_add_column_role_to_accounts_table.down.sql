@@ -0,0 +1,2 @@
+DROP INDEX idx_accounts_role ON accounts;
+ALTER TABLE accounts DROP COLUMN role;
@@ -0,0 +1,2 @@
+ALTER TABLE accounts ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
+CREATE INDEX idx_accounts_role ON accounts (role);
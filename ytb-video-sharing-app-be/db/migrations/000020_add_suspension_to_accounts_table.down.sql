@@ -0,0 +1,4 @@
+ALTER TABLE accounts
+    DROP COLUMN suspension_reason,
+    DROP COLUMN suspended_until,
+    DROP COLUMN suspended_at;
@@ -0,0 +1,4 @@
+ALTER TABLE accounts
+    ADD COLUMN suspended_at DATETIME NULL,
+    ADD COLUMN suspended_until DATETIME NULL,
+    ADD COLUMN suspension_reason VARCHAR(500) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS video_reports;
+
+ALTER TABLE videos
+    DROP INDEX idx_videos_hidden_at,
+    DROP COLUMN hidden_at;
@@ -0,0 +1,21 @@
+ALTER TABLE videos
+    ADD COLUMN hidden_at DATETIME NULL,
+    ADD INDEX idx_videos_hidden_at (hidden_at);
+
+CREATE TABLE IF NOT EXISTS video_reports (
+    id           INT AUTO_INCREMENT PRIMARY KEY,
+    video_id     INT NOT NULL,
+    reporter_id  INT NOT NULL,
+    reason       VARCHAR(32) NOT NULL,
+    note         VARCHAR(500) NOT NULL DEFAULT '',
+    status       VARCHAR(16) NOT NULL DEFAULT 'open',
+    created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    resolved_at  DATETIME NULL,
+    resolved_by  INT NULL,
+    UNIQUE KEY uq_video_reports_video_reporter (video_id, reporter_id),
+    INDEX idx_video_reports_status_video_id (status, video_id),
+    INDEX idx_video_reports_reporter_created_at (reporter_id, created_at),
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+    FOREIGN KEY (reporter_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    FOREIGN KEY (resolved_by) REFERENCES accounts(id) ON DELETE SET NULL
+);
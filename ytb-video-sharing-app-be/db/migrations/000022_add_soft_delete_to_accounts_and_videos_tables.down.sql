@@ -0,0 +1,11 @@
+ALTER TABLE videos
+    DROP FOREIGN KEY fk_videos_deleted_by,
+    DROP INDEX idx_videos_deleted_at,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE accounts
+    DROP FOREIGN KEY fk_accounts_deleted_by,
+    DROP INDEX idx_accounts_deleted_at,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
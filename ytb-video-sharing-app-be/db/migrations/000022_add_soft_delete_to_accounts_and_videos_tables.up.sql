@@ -0,0 +1,11 @@
+ALTER TABLE accounts
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD COLUMN deleted_by INT NULL,
+    ADD INDEX idx_accounts_deleted_at (deleted_at),
+    ADD CONSTRAINT fk_accounts_deleted_by FOREIGN KEY (deleted_by) REFERENCES accounts(id) ON DELETE SET NULL;
+
+ALTER TABLE videos
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD COLUMN deleted_by INT NULL,
+    ADD INDEX idx_videos_deleted_at (deleted_at),
+    ADD CONSTRAINT fk_videos_deleted_by FOREIGN KEY (deleted_by) REFERENCES accounts(id) ON DELETE SET NULL;
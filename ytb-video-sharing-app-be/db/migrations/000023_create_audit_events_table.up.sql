@@ -0,0 +1,17 @@
+-- append-only, no foreign keys so events outlive the accounts and videos they are about
+CREATE TABLE IF NOT EXISTS audit_events (
+    id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    action       VARCHAR(64) NOT NULL,
+    actor_id     INT NULL,
+    target_type  VARCHAR(32) NOT NULL DEFAULT '',
+    target_id    BIGINT NULL,
+    detail       VARCHAR(1000) NOT NULL DEFAULT '',
+    ip_address   VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent   VARCHAR(512) NOT NULL DEFAULT '',
+    request_id   VARCHAR(64) NOT NULL DEFAULT '',
+    created_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_events_created_at (created_at),
+    INDEX idx_audit_events_actor_id (actor_id, created_at),
+    INDEX idx_audit_events_target (target_type, target_id, created_at),
+    INDEX idx_audit_events_action (action, created_at)
+);
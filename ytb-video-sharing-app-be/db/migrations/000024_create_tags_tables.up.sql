@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    name        VARCHAR(32) NOT NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_tags_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS video_tags (
+    video_id  INT NOT NULL,
+    tag_id    INT NOT NULL,
+    PRIMARY KEY (video_id, tag_id),
+    INDEX idx_video_tags_tag_id (tag_id),
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS playlists (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    account_id  INT NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    visibility  VARCHAR(16) NOT NULL DEFAULT 'private',
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at  DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_playlists_account_id (account_id, visibility),
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+-- positions of a playlist run from 1 without gaps
+CREATE TABLE IF NOT EXISTS playlist_videos (
+    playlist_id  INT NOT NULL,
+    video_id     INT NOT NULL,
+    position     INT NOT NULL,
+    added_at     DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (playlist_id, video_id),
+    INDEX idx_playlist_videos_position (playlist_id, position),
+    INDEX idx_playlist_videos_video_id (video_id),
+    FOREIGN KEY (playlist_id) REFERENCES playlists(id) ON DELETE CASCADE,
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+);
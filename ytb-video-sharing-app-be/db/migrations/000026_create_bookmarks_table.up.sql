@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+    account_id  INT NOT NULL,
+    video_id    INT NOT NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, video_id),
+    INDEX idx_bookmarks_account_id_created_at (account_id, created_at),
+    INDEX idx_bookmarks_video_id (video_id),
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE,
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+);
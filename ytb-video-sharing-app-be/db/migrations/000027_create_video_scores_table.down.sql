@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS video_scores;
+
+ALTER TABLE videos
+    DROP COLUMN created_at;
@@ -0,0 +1,16 @@
+ALTER TABLE videos
+    ADD COLUMN created_at DATETIME NULL;
+
+-- videos shared before keep an unknown share time, only new videos take the current time
+ALTER TABLE videos
+    MODIFY COLUMN created_at DATETIME NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS video_scores (
+    time_window  VARCHAR(8) NOT NULL,
+    video_id     INT NOT NULL,
+    score        DOUBLE NOT NULL,
+    computed_at  DATETIME NOT NULL,
+    PRIMARY KEY (time_window, video_id),
+    INDEX idx_video_scores_time_window_score (time_window, score),
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE
+);
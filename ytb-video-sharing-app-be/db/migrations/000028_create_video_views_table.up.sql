@@ -0,0 +1,11 @@
+-- a viewer counts once per video every half hour, views are kept for the all time window
+CREATE TABLE IF NOT EXISTS video_views (
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    video_id    INT NOT NULL,
+    account_id  INT NOT NULL,
+    viewed_at   DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_video_views_video_id_viewed_at (video_id, viewed_at),
+    INDEX idx_video_views_account_id_video_id_viewed_at (account_id, video_id, viewed_at),
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS video_comments (
+    id          INT AUTO_INCREMENT PRIMARY KEY,
+    video_id    INT NOT NULL,
+    account_id  INT NOT NULL,
+    content     VARCHAR(1000) NOT NULL,
+    created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_video_comments_video_id_created_at (video_id, created_at),
+    FOREIGN KEY (video_id) REFERENCES videos(id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
@@ -147,6 +147,11 @@ func (r *rows) Close() {
 	r.rows.Close()
 }
 
+// Err implements pkg.Rows.
+func (r *rows) Err() error {
+	return r.rows.Err()
+}
+
 // Next implements pkg.Rows.
 func (r *rows) Next() bool {
 	return r.rows.Next()
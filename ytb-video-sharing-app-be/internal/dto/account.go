@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type CreateAccountRequest struct {
 	Email     string `json:"email" binding:"required,email"`
 	Password  string `json:"password" binding:"required,min=6"`
@@ -10,6 +12,7 @@ type CreateAccountRequest struct {
 type CreateAccountResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	SessionID    string `json:"session_id"`
 	AccountResponse
 }
 
@@ -26,6 +29,7 @@ type LoginRequest struct {
 type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	SessionID    string `json:"session_id"`
 	AccountResponse
 }
 
@@ -47,8 +51,73 @@ type CheckTokenResponse struct {
 }
 
 type AccountResponse struct {
-	ID        int64  `json:"id"`
-	Email     string `json:"email"`
-	FullName  string `json:"fullname"`
-	AvatarURL string `json:"avatar_url"`
+	ID            int64  `json:"id"`
+	Email         string `json:"email"`
+	FullName      string `json:"fullname"`
+	AvatarURL     string `json:"avatar_url"`
+	Bio           string `json:"bio"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+}
+
+type UpdateProfileRequest struct {
+	FullName *string `json:"fullname" binding:"omitempty,min=1,max=255"`
+	Bio      *string `json:"bio" binding:"omitempty,max=500"`
+}
+
+type ProfileResponse struct {
+	AccountResponse
+	VideosShared    int64     `json:"videos_shared"`
+	UpvotesReceived int64     `json:"upvotes_received"`
+	FollowerCount   int64     `json:"follower_count"`
+	JoinedAt        time.Time `json:"joined_at"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required,min=6"`
+	NewPassword     string `json:"new_password" binding:"required,min=6,nefield=CurrentPassword"`
+}
+
+type ChangePasswordResponse struct {
+	// RevokedSessions lists sessions logged out on other devices
+	RevokedSessions []string `json:"revoked_sessions"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ForgotPasswordResponse struct {
+	Message string `json:"message"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+type ResetPasswordResponse struct {
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type VerifyEmailResponse struct {
+	AccountResponse
+}
+
+type ResendVerificationEmailResponse struct {
+}
+
+type DeleteMyAccountRequest struct {
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type RestoreAccountRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type RestoreAccountResponse struct {
 }
@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin"`
+}
+
+// AdminAccountResponse is an account as seen by admins, with its moderation state.
+type AdminAccountResponse struct {
+	AccountResponse
+	CreatedAt        time.Time  `json:"created_at"`
+	Suspended        bool       `json:"suspended"`
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspendedUntil   *time.Time `json:"suspended_until"`
+	SuspensionReason string     `json:"suspension_reason"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+}
+
+type SuspendAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+	// DurationHours is optional, the account stays suspended until lifted when omitted
+	DurationHours int `json:"duration_hours" binding:"omitempty,min=1,max=87600"`
+}
+
+type ForceLogoutResponse struct {
+	// RevokedSessions is the number of sessions logged out
+	RevokedSessions int `json:"revoked_sessions"`
+}
+
+type DeleteAccountResponse struct {
+	Permanent bool `json:"permanent"`
+	// RestorableUntil is when a soft deleted account is purged, null when it is deleted for good
+	RestorableUntil *time.Time `json:"restorable_until"`
+}
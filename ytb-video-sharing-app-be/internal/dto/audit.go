@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type AuditEventResponse struct {
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+	// ActorID is null for anonymous callers and the system
+	ActorID    *int64    `json:"actor_id"`
+	TargetType string    `json:"target_type"`
+	TargetID   *int64    `json:"target_id"`
+	Detail     string    `json:"detail"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	RequestID  string    `json:"request_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AuditEventFilter is the query of admin audit log, zero fields do not filter.
+type AuditEventFilter struct {
+	ActorID    int64
+	TargetType string
+	TargetID   int64
+	Action     string
+	From       time.Time
+	To         time.Time
+}
@@ -0,0 +1,9 @@
+package dto
+
+type BookmarkResponse struct {
+	AccountID int64 `json:"account_id"`
+	VideoID   int64 `json:"video_id"`
+}
+
+type DeleteBookmarkResponse struct {
+}
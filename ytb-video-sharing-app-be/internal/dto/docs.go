@@ -6,4 +6,49 @@ type LogoutResponseDocs = ResponseSuccess[LogoutResponse]
 type RefreshTokenResponseDocs = ResponseSuccess[RefreshTokenResponse]
 type ShareVideoResponseDocs = ResponseSuccess[ShareVideoResponse]
 type ListVideosResponseDocs = ResponseSuccessPagingation[[]VideoResponse]
+type ListTagsResponseDocs = ResponseSuccess[[]TagResponse]
 type CheckTokenResponseDocs = ResponseSuccess[CheckTokenResponse]
+type FollowResponseDocs = ResponseSuccess[FollowResponse]
+type UnfollowResponseDocs = ResponseSuccess[UnfollowResponse]
+type ListAccountsResponseDocs = ResponseSuccessPagingation[[]AccountResponse]
+type FeedResponseDocs = ResponseSuccessCursor[[]VideoResponse]
+type ProfileResponseDocs = ResponseSuccess[ProfileResponse]
+type AccountResponseDocs = ResponseSuccess[AccountResponse]
+type ChangePasswordResponseDocs = ResponseSuccess[ChangePasswordResponse]
+type ForgotPasswordResponseDocs = ResponseSuccess[ForgotPasswordResponse]
+type ResetPasswordResponseDocs = ResponseSuccess[ResetPasswordResponse]
+type VerifyEmailResponseDocs = ResponseSuccess[VerifyEmailResponse]
+type ResendVerificationEmailResponseDocs = ResponseSuccess[ResendVerificationEmailResponse]
+type ListSessionsResponseDocs = ResponseSuccess[[]SessionResponse]
+type RevokeSessionResponseDocs = ResponseSuccess[RevokeSessionResponse]
+type MFAChallengeResponseDocs = ResponseSuccess[MFAChallengeResponse]
+type EnrollMFAResponseDocs = ResponseSuccess[EnrollMFAResponse]
+type VerifyMFAResponseDocs = ResponseSuccess[VerifyMFAResponse]
+type DisableMFAResponseDocs = ResponseSuccess[DisableMFAResponse]
+type OIDCAuthorizeResponseDocs = ResponseSuccess[OIDCAuthorizeResponse]
+type CreatePersonalAccessTokenResponseDocs = ResponseSuccess[CreatePersonalAccessTokenResponse]
+type ListPersonalAccessTokensResponseDocs = ResponseSuccess[[]PersonalAccessTokenResponse]
+type RevokePersonalAccessTokenResponseDocs = ResponseSuccess[RevokePersonalAccessTokenResponse]
+type ListAdminAccountsResponseDocs = ResponseSuccessPagingation[[]AdminAccountResponse]
+type AdminAccountResponseDocs = ResponseSuccess[AdminAccountResponse]
+type ForceLogoutResponseDocs = ResponseSuccess[ForceLogoutResponse]
+type DeleteAccountResponseDocs = ResponseSuccess[DeleteAccountResponse]
+type VideoReportResponseDocs = ResponseSuccess[VideoReportResponse]
+type ListReportedVideosResponseDocs = ResponseSuccessPagingation[[]ReportedVideoResponse]
+type HideVideoResponseDocs = ResponseSuccess[HideVideoResponse]
+type DeleteVideoResponseDocs = ResponseSuccess[DeleteVideoResponse]
+type RestoreVideoResponseDocs = ResponseSuccess[RestoreVideoResponse]
+type RestoreAccountResponseDocs = ResponseSuccess[RestoreAccountResponse]
+type ListAuditEventsResponseDocs = ResponseSuccessPagingation[[]AuditEventResponse]
+type PlaylistResponseDocs = ResponseSuccess[PlaylistResponse]
+type ListPlaylistsResponseDocs = ResponseSuccessPagingation[[]PlaylistResponse]
+type ListPlaylistVideosResponseDocs = ResponseSuccessPagingation[[]PlaylistVideoResponse]
+type PlaylistItemResponseDocs = ResponseSuccess[PlaylistItemResponse]
+type DeletePlaylistResponseDocs = ResponseSuccess[DeletePlaylistResponse]
+type WatchPlaylistResponseDocs = ResponseSuccess[WatchPlaylistResponse]
+type BookmarkResponseDocs = ResponseSuccess[BookmarkResponse]
+type DeleteBookmarkResponseDocs = ResponseSuccess[DeleteBookmarkResponse]
+type RecordViewResponseDocs = ResponseSuccess[RecordViewResponse]
+type VideoCommentResponseDocs = ResponseSuccess[VideoCommentResponse]
+type ListVideoCommentsResponseDocs = ResponseSuccessPagingation[[]VideoCommentResponse]
+type DeleteVideoCommentResponseDocs = ResponseSuccess[DeleteVideoCommentResponse]
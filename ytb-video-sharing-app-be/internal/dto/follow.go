@@ -0,0 +1,9 @@
+package dto
+
+type FollowResponse struct {
+	FollowerID  int64 `json:"follower_id"`
+	FollowingID int64 `json:"following_id"`
+}
+
+type UnfollowResponse struct {
+}
@@ -0,0 +1,36 @@
+package dto
+
+// MFAChallengeResponse is returned by login instead of tokens when 2FA is enabled.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	// Code is a TOTP code or one of the recovery codes
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type EnrollMFAResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type VerifyMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type VerifyMFAResponse struct {
+	// RecoveryCodes are shown only once, each can be used once instead of a TOTP code
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type DisableMFARequest struct {
+	// Code is a TOTP code or one of the recovery codes
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type DisableMFAResponse struct {
+}
@@ -0,0 +1,14 @@
+package dto
+
+type OIDCAuthorizeResponse struct {
+	// AuthorizationURL is where the browser is sent to log in at the identity provider
+	AuthorizationURL string `json:"authorization_url"`
+	// StateBinding is set as an HttpOnly cookie, tying the login to the browser that started it
+	StateBinding string `json:"-"`
+}
+
+// OIDCCallbackRequest carries query parameters the identity provider redirected back with.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type CreatePersonalAccessTokenRequest struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=videos:write videos:read notifications:read"`
+	// ExpiresInDays is optional, the token never expires when omitted
+	ExpiresInDays int `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+type PersonalAccessTokenResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the token, to tell tokens apart
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	// Token is shown only once, send it as "Authorization: Bearer <token>"
+	Token string `json:"token"`
+}
+
+type RevokePersonalAccessTokenResponse struct {
+}
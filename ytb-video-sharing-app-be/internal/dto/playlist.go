@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+type CreatePlaylistRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// Visibility is private unless given
+	Visibility string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+}
+
+// UpdatePlaylistRequest renames playlist or changes its visibility, omitted fields are left as they are.
+type UpdatePlaylistRequest struct {
+	Name       *string `json:"name" binding:"omitempty,max=100"`
+	Visibility *string `json:"visibility" binding:"omitempty,oneof=public unlisted private"`
+}
+
+type AddPlaylistVideoRequest struct {
+	VideoID int64 `json:"video_id" binding:"required,min=1"`
+}
+
+type MovePlaylistVideoRequest struct {
+	// Position starts from 1, positions past the end move the video to the end
+	Position int `json:"position" binding:"required,min=1"`
+}
+
+type PlaylistResponse struct {
+	ID         int64     `json:"id"`
+	AccountID  int64     `json:"account_id"`
+	Name       string    `json:"name"`
+	Visibility string    `json:"visibility"`
+	VideoCount int       `json:"video_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type PlaylistVideoResponse struct {
+	Position int           `json:"position"`
+	AddedAt  time.Time     `json:"added_at"`
+	Video    VideoResponse `json:"video"`
+}
+
+type PlaylistItemResponse struct {
+	PlaylistID int64 `json:"playlist_id"`
+	VideoID    int64 `json:"video_id"`
+	// Position is 0 once the video is removed
+	Position int `json:"position"`
+}
+
+type DeletePlaylistResponse struct {
+	PlaylistID int64 `json:"playlist_id"`
+}
+
+type WatchPlaylistResponse struct {
+	PlaylistID int64 `json:"playlist_id"`
+	Watching   bool  `json:"watching"`
+}
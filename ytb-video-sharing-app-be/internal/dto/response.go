@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ResponseSuccess[T any] struct {
 	Data     T        `json:"data,omitempty"`
 	Metadata Metadata `json:"metadata"`
@@ -10,6 +12,11 @@ type ResponseSuccessPagingation[T any] struct {
 	Metadata MetadataWithPagination `json:"metadata"`
 }
 
+type ResponseSuccessCursor[T any] struct {
+	Data     T                  `json:"data,omitempty"`
+	Metadata MetadataWithCursor `json:"metadata"`
+}
+
 type ResponseError struct {
 	Metadata Metadata    `json:"metadata"`
 	Error    interface{} `json:"error,omitempty"`
@@ -33,11 +40,30 @@ type Pagination struct {
 	IsPrevious bool `json:"is_previous"`
 }
 
+type MetadataWithCursor struct {
+	Code   int     `json:"code"`
+	Cursor *Cursor `json:"cursor"`
+}
+
+type Cursor struct {
+	Limit      int   `json:"limit"`
+	NextCursor int64 `json:"next_cursor"`
+	HasMore    bool  `json:"has_more"`
+}
+
 type ErrorResponse struct {
 	Code    int    `json:"-"`
 	Message string `json:"message"`
+	// RetryAfter is sent as Retry-After header when set
+	RetryAfter time.Duration `json:"-"`
+	// Cause tells callers what went wrong without matching Message, it is never sent
+	Cause error `json:"-"`
 }
 
 func (e *ErrorResponse) Error() string {
 	return e.Message
 }
+
+func (e *ErrorResponse) Unwrap() error {
+	return e.Cause
+}
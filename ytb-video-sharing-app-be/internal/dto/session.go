@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// SessionMetadata describes the client a session is created or refreshed from.
+type SessionMetadata struct {
+	// DeviceLabel is optional, derived from user agent when empty
+	DeviceLabel string
+	UserAgent   string
+	IPAddress   string
+}
+
+type SessionResponse struct {
+	ID          string    `json:"id"`
+	DeviceLabel string    `json:"device_label"`
+	UserAgent   string    `json:"user_agent"`
+	IPAddress   string    `json:"ip_address"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+	Current     bool      `json:"current"`
+}
+
+type RevokeSessionResponse struct {
+}
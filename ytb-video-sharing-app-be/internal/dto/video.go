@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 type ShareVideoRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description" binding:"omitempty"`
@@ -7,25 +9,57 @@ type ShareVideoRequest struct {
 	DownVote    int64  `json:"downvote" binding:"omitempty"`
 	Thumbnail   string `json:"thumbnail" binding:"required"`
 	VideoUrl    string `json:"video_url" binding:"required,url"`
+	// Tags are lowercased and deduplicated, see utils.MaxVideoTags and utils.MaxTagLength for limits
+	Tags []string `json:"tags" binding:"omitempty"`
 }
 
 type ShareVideoResponse struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	UpVote      int64  `json:"upvote"`
-	DownVote    int64  `json:"downvote"`
-	Thumbnail   string `json:"thumbnail"`
-	VideoUrl    string `json:"video_url"`
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	UpVote      int64    `json:"upvote"`
+	DownVote    int64    `json:"downvote"`
+	Thumbnail   string   `json:"thumbnail"`
+	VideoUrl    string   `json:"video_url"`
+	Tags        []string `json:"tags"`
 }
 
 type VideoResponse struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	UpVote      int64  `json:"upvote"`
-	DownVote    int64  `json:"downvote"`
-	Thumbnail   string `json:"thumbnail"`
-	VideoUrl    string `json:"video_url"`
-	SharedBy    string `json:"shared_by"`
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	UpVote      int64    `json:"upvote"`
+	DownVote    int64    `json:"downvote"`
+	Thumbnail   string   `json:"thumbnail"`
+	VideoUrl    string   `json:"video_url"`
+	SharedBy    string   `json:"shared_by"`
+	Tags        []string `json:"tags"`
+	// Hidden is only ever true for the owner, nobody else is shown hidden videos
+	Hidden bool `json:"hidden"`
+	// BookmarkCount is how many accounts bookmarked the video
+	BookmarkCount int64 `json:"bookmark_count"`
+	// IsBookmarked is only ever true for logged in callers who bookmarked the video
+	IsBookmarked bool `json:"is_bookmarked"`
+}
+
+type DeleteVideoResponse struct {
+	VideoID int64 `json:"video_id"`
+	// RestorableUntil is when the video is purged for good
+	RestorableUntil time.Time `json:"restorable_until"`
+}
+
+type RestoreVideoResponse struct {
+	VideoID int64 `json:"video_id"`
+}
+
+type RecordViewResponse struct {
+	VideoID int64 `json:"video_id"`
+	// Counted is false when the caller viewed the video within the last half hour already
+	Counted bool `json:"counted"`
+}
+
+type TagResponse struct {
+	Name string `json:"name"`
+	// VideoCount is how many visible videos carry the tag
+	VideoCount int `json:"video_count"`
 }
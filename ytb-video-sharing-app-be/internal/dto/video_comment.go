@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type CommentVideoRequest struct {
+	Content string `json:"content" binding:"required,max=1000"`
+}
+
+type VideoCommentResponse struct {
+	ID        int64     `json:"id"`
+	VideoID   int64     `json:"video_id"`
+	AccountID int64     `json:"account_id"`
+	FullName  string    `json:"fullname"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DeleteVideoCommentResponse struct {
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type ReportVideoRequest struct {
+	Reason string `json:"reason" binding:"required,oneof=spam inappropriate broken copyright other"`
+	Note   string `json:"note" binding:"omitempty,max=500"`
+}
+
+type VideoReportResponse struct {
+	ID         int64     `json:"id"`
+	VideoID    int64     `json:"video_id"`
+	ReporterID int64     `json:"reporter_id"`
+	Reason     string    `json:"reason"`
+	Note       string    `json:"note"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReportedVideoResponse is a video in the moderation queue together with its open reports.
+type ReportedVideoResponse struct {
+	Video          VideoResponse `json:"video"`
+	ReportCount    int           `json:"report_count"`
+	LastReportedAt time.Time     `json:"last_reported_at"`
+	// Reasons counts open reports by reason
+	Reasons map[string]int         `json:"reasons"`
+	Reports []*VideoReportResponse `json:"reports"`
+}
+
+type HideVideoResponse struct {
+	VideoID int64 `json:"video_id"`
+	Hidden  bool  `json:"hidden"`
+}
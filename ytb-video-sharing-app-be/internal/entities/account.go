@@ -1,13 +1,40 @@
 package entities
 
+import (
+	"database/sql"
+	"time"
+)
+
 type Account struct {
-	ID        int64  `db:"id"`
-	Email     string `db:"email"`
-	FullName  string `db:"fullname"`
-	AvatarURL string `db:"avatar_url"`
+	ID              int64        `db:"id"`
+	Email           string       `db:"email"`
+	FullName        string       `db:"fullname"`
+	AvatarURL       string       `db:"avatar_url"`
+	Bio             string       `db:"bio"`
+	CreatedAt       time.Time    `db:"created_at"`
+	EmailVerifiedAt sql.NullTime `db:"email_verified_at"`
+	Role            Role         `db:"role"`
+	// SuspendedAt is set while account is suspended, SuspendedUntil is null when suspended until lifted
+	SuspendedAt      sql.NullTime `db:"suspended_at"`
+	SuspendedUntil   sql.NullTime `db:"suspended_until"`
+	SuspensionReason string       `db:"suspension_reason"`
+	// DeletedAt is set while account is soft deleted, it is purged for good after the retention period
+	DeletedAt sql.NullTime  `db:"deleted_at"`
+	DeletedBy sql.NullInt64 `db:"deleted_by"`
+}
+
+// IsSuspended tells whether account may not log in or use its tokens at now.
+func (a *Account) IsSuspended(now time.Time) bool {
+	return a.SuspendedAt.Valid && (!a.SuspendedUntil.Valid || a.SuspendedUntil.Time.After(now))
 }
 
 type AccountPassword struct {
 	ID       int64  `db:"id"`
 	Password string `db:"password"`
 }
+
+type AccountStats struct {
+	VideosShared    int64
+	UpvotesReceived int64
+	FollowerCount   int64
+}
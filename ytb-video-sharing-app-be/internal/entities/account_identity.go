@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// AccountIdentity links account to a user of an external identity provider.
+type AccountIdentity struct {
+	ID        int64  `db:"id"`
+	AccountID int64  `db:"account_id"`
+	Provider  string `db:"provider"`
+	// Subject is the user id at the provider, unlike email it never changes
+	Subject   string    `db:"subject"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// OIDCLoginState is kept between redirecting to the provider and its callback.
+type OIDCLoginState struct {
+	StateHash    string    `db:"state_hash"`
+	CodeVerifier string    `db:"code_verifier"`
+	Nonce        string    `db:"nonce"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	CreatedAt    time.Time `db:"created_at"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AccountMFA is the TOTP secret of account, 2FA is only enforced once EnabledAt is set.
+type AccountMFA struct {
+	AccountID int64  `db:"account_id"`
+	Secret    string `db:"secret"`
+	// EnabledAt is null while enrollment is not verified yet
+	EnabledAt sql.NullTime `db:"enabled_at"`
+	// LastUsedStep is the latest accepted TOTP time step, a code is never accepted twice
+	LastUsedStep int64     `db:"last_used_step"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AuditEvent is a row of the append-only audit log.
+type AuditEvent struct {
+	ID     int64
+	Action string
+	// ActorID is null for anonymous callers and the system
+	ActorID    sql.NullInt64
+	TargetType string
+	TargetID   sql.NullInt64
+	Detail     string
+	IPAddress  string
+	UserAgent  string
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+// AuditEventFilter narrows down audit events, zero fields do not filter.
+type AuditEventFilter struct {
+	ActorID    int64
+	TargetType string
+	TargetID   int64
+	Action     string
+	From       time.Time
+	To         time.Time
+}
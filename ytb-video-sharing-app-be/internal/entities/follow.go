@@ -0,0 +1,9 @@
+package entities
+
+import "time"
+
+type Follow struct {
+	FollowerID  int64     `db:"follower_id"`
+	FollowingID int64     `db:"following_id"`
+	CreatedAt   time.Time `db:"created_at"`
+}
@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PasswordResetToken struct {
+	ID        int64        `db:"id"`
+	AccountID int64        `db:"account_id"`
+	TokenHash string       `db:"token_hash"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	UsedAt    sql.NullTime `db:"used_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PersonalAccessToken is a long lived token of account for scripts and bots, only its hash is stored.
+type PersonalAccessToken struct {
+	ID        int64  `db:"id"`
+	AccountID int64  `db:"account_id"`
+	Name      string `db:"name"`
+	// TokenPrefix is the start of the token kept in plain text, so the user can tell tokens apart
+	TokenPrefix string `db:"token_prefix"`
+	TokenHash   string `db:"token_hash"`
+	// Scopes is stored comma separated
+	Scopes     []string     `db:"scopes"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	RevokedAt  sql.NullTime `db:"revoked_at"`
+	CreatedAt  time.Time    `db:"created_at"`
+}
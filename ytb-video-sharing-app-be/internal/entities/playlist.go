@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// PlaylistVisibility decides who can see a playlist besides its owner.
+type PlaylistVisibility string
+
+const (
+	// PlaylistPublic playlists are listed on the profile of their owner
+	PlaylistPublic PlaylistVisibility = "public"
+	// PlaylistUnlisted playlists are not listed, but anyone with the link can see them
+	PlaylistUnlisted PlaylistVisibility = "unlisted"
+	// PlaylistPrivate playlists are only seen by their owner
+	PlaylistPrivate PlaylistVisibility = "private"
+)
+
+type Playlist struct {
+	ID         int64              `db:"id"`
+	AccountID  int64              `db:"account_id"`
+	Name       string             `db:"name"`
+	Visibility PlaylistVisibility `db:"visibility"`
+	CreatedAt  time.Time          `db:"created_at"`
+	UpdatedAt  time.Time          `db:"updated_at"`
+	VideoCount int
+}
+
+// VisibleTo tells whether viewerID (0 for anonymous viewers) may see the playlist.
+func (p *Playlist) VisibleTo(viewerID int64) bool {
+	return p.Visibility != PlaylistPrivate || p.AccountID == viewerID
+}
+
+// PlaylistItem is a video at its position in a playlist.
+type PlaylistItem struct {
+	VideoID  int64     `db:"video_id"`
+	Position int       `db:"position"`
+	AddedAt  time.Time `db:"added_at"`
+}
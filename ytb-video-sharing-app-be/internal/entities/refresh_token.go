@@ -1,12 +1,21 @@
 package entities
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type RefreshToken struct {
-	ID        int64     `db:"id"`
-	AccountID int64     `db:"account_id"`
-	Token     string    `db:"token"`
-	ExpiresAt time.Time `db:"expires_at"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID          int64        `db:"id"`
+	AccountID   int64        `db:"account_id"`
+	TokenHash   string       `db:"token_hash"`
+	FamilyID    string       `db:"family_id"`
+	DeviceLabel string       `db:"device_label"`
+	UserAgent   string       `db:"user_agent"`
+	IPAddress   string       `db:"ip_address"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	UsedAt      sql.NullTime `db:"used_at"`
+	LastUsedAt  time.Time    `db:"last_used_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   time.Time    `db:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package entities
+
+// Role decides what an account may do besides managing its own content.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// roleRanks orders roles, a higher role has every power of the lower ones.
+var roleRanks = map[Role]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+// Valid tells whether r is a known role.
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// AtLeast tells whether r is the same or a higher role than min, unknown roles are lower than every role.
+func (r Role) AtLeast(min Role) bool {
+	return roleRanks[r] > 0 && roleRanks[r] >= roleRanks[min]
+}
@@ -0,0 +1,7 @@
+package entities
+
+// TagUsage is a tag with how many visible videos carry it.
+type TagUsage struct {
+	Name       string `db:"name"`
+	VideoCount int    `db:"video_count"`
+}
@@ -1,5 +1,7 @@
 package entities
 
+import "database/sql"
+
 type Video struct {
 	ID          int64  `db:"id"`
 	Title       string `db:"title"`
@@ -9,5 +11,16 @@ type Video struct {
 	Thumbnail   string `db:"thumbnail"`
 	VideoUrl    string `db:"video_url"`
 	AccountID   int64  `db:"account_id"`
-	FullName    string
+	// HiddenAt is set when a moderator or too many reports hid the video, only its owner still sees it
+	HiddenAt sql.NullTime `db:"hidden_at"`
+	// DeletedAt is set while video is soft deleted, it is purged for good after the retention period
+	DeletedAt sql.NullTime  `db:"deleted_at"`
+	DeletedBy sql.NullInt64 `db:"deleted_by"`
+	FullName  string
+	// Tags are normalized tag names, saved apart from the video in video_tags
+	Tags []string
+	// BookmarkCount is how many accounts bookmarked the video
+	BookmarkCount int64
+	// IsBookmarked is whether the account the video was loaded for bookmarked it
+	IsBookmarked bool
 }
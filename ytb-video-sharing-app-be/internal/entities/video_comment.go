@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+type VideoComment struct {
+	ID        int64     `db:"id"`
+	VideoID   int64     `db:"video_id"`
+	AccountID int64     `db:"account_id"`
+	Content   string    `db:"content"`
+	CreatedAt time.Time `db:"created_at"`
+	FullName  string
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ReportReason is the category a reporter picked for a video.
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "spam"
+	ReportReasonInappropriate ReportReason = "inappropriate"
+	ReportReasonBroken        ReportReason = "broken"
+	ReportReasonCopyright     ReportReason = "copyright"
+	ReportReasonOther         ReportReason = "other"
+)
+
+// ReportStatus is where a report is in the moderation queue.
+type ReportStatus string
+
+const (
+	// ReportStatusOpen reports wait in the moderation queue
+	ReportStatusOpen ReportStatus = "open"
+	// ReportStatusDismissed reports were reviewed and found groundless
+	ReportStatusDismissed ReportStatus = "dismissed"
+	// ReportStatusActioned reports were resolved by hiding their video
+	ReportStatusActioned ReportStatus = "actioned"
+)
+
+type VideoReport struct {
+	ID         int64         `db:"id"`
+	VideoID    int64         `db:"video_id"`
+	ReporterID int64         `db:"reporter_id"`
+	Reason     ReportReason  `db:"reason"`
+	Note       string        `db:"note"`
+	Status     ReportStatus  `db:"status"`
+	CreatedAt  time.Time     `db:"created_at"`
+	ResolvedAt sql.NullTime  `db:"resolved_at"`
+	ResolvedBy sql.NullInt64 `db:"resolved_by"`
+}
+
+// ReportedVideo is a video in the moderation queue with a summary of its open reports.
+type ReportedVideo struct {
+	Video
+	ReportCount    int
+	LastReportedAt time.Time
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TrendingWindow is how far back videos are ranked for trending.
+type TrendingWindow string
+
+const (
+	TrendingDay  TrendingWindow = "day"
+	TrendingWeek TrendingWindow = "week"
+	TrendingAll  TrendingWindow = "all"
+)
+
+// TrendingWindows are the windows scores are recomputed for.
+var TrendingWindows = []TrendingWindow{TrendingDay, TrendingWeek, TrendingAll}
+
+// Since is when videos ranked in window were shared at the earliest, zero time for all time.
+func (w TrendingWindow) Since(now time.Time) time.Time {
+	switch w {
+	case TrendingDay:
+		return now.Add(-24 * time.Hour)
+	case TrendingWeek:
+		return now.Add(-7 * 24 * time.Hour)
+	}
+
+	return time.Time{}
+}
+
+// ViewsSince is when views start counting as recent for window, a week back for all time.
+func (w TrendingWindow) ViewsSince(now time.Time) time.Time {
+	if since := w.Since(now); !since.IsZero() {
+		return since
+	}
+
+	return now.Add(-7 * 24 * time.Hour)
+}
+
+// Valid is whether w is one of TrendingWindows.
+func (w TrendingWindow) Valid() bool {
+	return w == TrendingDay || w == TrendingWeek || w == TrendingAll
+}
+
+// VideoStats is what a video is scored on, CreatedAt is not valid for videos shared before it was recorded.
+type VideoStats struct {
+	VideoID      int64        `db:"id"`
+	UpVote       int64        `db:"upvote"`
+	DownVote     int64        `db:"downvote"`
+	CreatedAt    sql.NullTime `db:"created_at"`
+	CommentCount int64
+	// ViewCount is how many times the video was viewed recently
+	ViewCount int64
+}
+
+type VideoScore struct {
+	VideoID int64   `db:"video_id"`
+	Score   float64 `db:"score"`
+}
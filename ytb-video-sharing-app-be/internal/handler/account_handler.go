@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -18,14 +22,16 @@ type AccountHandler struct {
 	router         *gin.Engine
 	// queue          pkg.Queue
 	opts           websock.RetentionMap
+	wsManager      *websock.Manager
 }
 
-func NewAccountHandler(accountService service.AccountService, router *gin.Engine, opts websock.RetentionMap) *AccountHandler {
+func NewAccountHandler(accountService service.AccountService, router *gin.Engine, opts websock.RetentionMap, wsManager *websock.Manager) *AccountHandler {
 	return &AccountHandler{
 		accountService: accountService,
 		router:         router,
 		// queue:          queue,
 		opts:           opts,
+		wsManager:      wsManager,
 	}
 }
 
@@ -50,14 +56,14 @@ func (h *AccountHandler) Register(ctx *gin.Context) {
 		Email:     data.Email,
 		FullName:  data.FullName,
 		AvatarURL: data.AvatarURL,
-	}, &entities.AccountPassword{Password: data.Password})
+	}, &entities.AccountPassword{Password: data.Password}, sessionMetadata(ctx))
 
 	if err != nil {
 		utils.ErrorResponse(ctx, err.Code, err.Error())
 		return
 	}
 
-	newOTP := h.opts.NewOTP().Key
+	newOTP := h.opts.NewOTP(res.ID, res.SessionID, "").Key
 
 	response := &dto.CreateAccountResponseWithOTP{
 		CreateAccountResponse: *res,
@@ -71,12 +77,14 @@ func (h *AccountHandler) Register(ctx *gin.Context) {
 //
 //	@Summary		Login account
 //	@Tags			accounts
-//	@Description	Authenticate user and return access token & refresh token
+//	@Description	Authenticate user and return access token & refresh token, or dto.MFAChallengeResponse with mfa_required to exchange at /accounts/login/mfa when 2FA is enabled
 //	@Accept			json
 //	@Produce		json
-//	@Param			request	body		dto.LoginRequest	true	"Login payload"
+//	@Param			request			body		dto.LoginRequest	true	"Login payload"
+//	@Param			X-Device-Label	header		string				false	"Label of device shown in active sessions"
 //	@Success		200		{object}	dto.LoginResponseDocs
 //	@Failure		400		{object}	dto.ResponseError
+//	@Failure		429		{object}	dto.ResponseError	"Too many failed attempts, retry after Retry-After seconds"
 //	@Failure		500		{object}	dto.ResponseError
 //	@Router			/accounts/login [post]
 func (h *AccountHandler) Login(ctx *gin.Context) {
@@ -84,14 +92,134 @@ func (h *AccountHandler) Login(ctx *gin.Context) {
 	data := req.(dto.LoginRequest)
 
 	// call service to login
-	res, err := h.accountService.Login(ctx, data.Email, data.Password)
+	res, challenge, err := h.accountService.Login(ctx, data.Email, data.Password, sessionMetadata(ctx))
+
+	if err != nil {
+		if err.RetryAfter > 0 {
+			utils.RetryAfter(ctx, err.RetryAfter)
+		}
+
+		utils.ErrorResponse(ctx, err.Code, err.Error())
+		return
+	}
+
+	if challenge != nil {
+		utils.SuccessResponse(ctx, http.StatusOK, challenge)
+		return
+	}
+
+	h.loginSuccess(ctx, res)
+}
+
+// LoginMFA godoc
+//
+//	@Summary		Complete login with second factor
+//	@Tags			accounts
+//	@Description	Exchange the challenge returned by login and a TOTP or recovery code for access token & refresh token
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		dto.LoginMFARequest	true	"Login MFA payload"
+//	@Param			X-Device-Label	header		string				false	"Label of device shown in active sessions"
+//	@Success		200		{object}	dto.LoginResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		401		{object}	dto.ResponseError
+//	@Failure		429		{object}	dto.ResponseError	"Too many failed attempts, retry after Retry-After seconds"
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/login/mfa [post]
+func (h *AccountHandler) LoginMFA(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.LoginMFARequest)
+
+	res, err := h.accountService.LoginMFA(ctx, data.MFAToken, data.Code, sessionMetadata(ctx))
+
+	if err != nil {
+		if err.RetryAfter > 0 {
+			utils.RetryAfter(ctx, err.RetryAfter)
+		}
+
+		utils.ErrorResponse(ctx, err.Code, err.Error())
+		return
+	}
+
+	h.loginSuccess(ctx, res)
+}
+
+// OIDCAuthorize godoc
+//
+//	@Summary		Start login with identity provider
+//	@Tags			accounts
+//	@Description	Return the authorization URL of the configured OpenID Connect provider, the browser is redirected there to log in. An HttpOnly oidc_state cookie ties the login to this browser
+//	@Produce		json
+//	@Success		200	{object}	dto.OIDCAuthorizeResponseDocs	"Sets oidc_state cookie"
+//	@Failure		404	{object}	dto.ResponseError	"OIDC login is not configured"
+//	@Failure		502	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/oidc/authorize [get]
+func (h *AccountHandler) OIDCAuthorize(ctx *gin.Context) {
+	res, err := h.accountService.StartOIDCLogin(ctx)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, err.Code, err.Error())
+		return
+	}
+
+	setOIDCStateCookie(ctx, res.StateBinding, int(service.OIDCLoginStateLifetime.Seconds()))
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// OIDCCallback godoc
+//
+//	@Summary		Complete login with identity provider
+//	@Tags			accounts
+//	@Description	Exchange code and state the identity provider redirected back with for access token & refresh token, or dto.MFAChallengeResponse when 2FA is enabled. The account is linked by verified email or created on first login. Requires the oidc_state cookie set by /accounts/oidc/authorize in the same browser
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		dto.OIDCCallbackRequest	true	"OIDC callback payload"
+//	@Param			X-Device-Label	header		string					false	"Label of device shown in active sessions"
+//	@Success		200		{object}	dto.LoginResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		401		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError	"OIDC login is not configured"
+//	@Failure		409		{object}	dto.ResponseError	"Account with the same email is not verified"
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/oidc/callback [post]
+func (h *AccountHandler) OIDCCallback(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.OIDCCallbackRequest)
+
+	stateBinding, _ := ctx.Cookie(oidcStateCookie)
+	// the state is single use, so is the cookie
+	setOIDCStateCookie(ctx, "", -1)
+
+	res, challenge, err := h.accountService.LoginWithOIDC(ctx, data.Code, data.State, stateBinding, sessionMetadata(ctx))
 
 	if err != nil {
 		utils.ErrorResponse(ctx, err.Code, err.Error())
 		return
 	}
 
-	newOTP := h.opts.NewOTP().Key
+	if challenge != nil {
+		utils.SuccessResponse(ctx, http.StatusOK, challenge)
+		return
+	}
+
+	h.loginSuccess(ctx, res)
+}
+
+// oidcStateCookie ties an OIDC login to the browser that started it.
+const oidcStateCookie = "oidc_state"
+
+// setOIDCStateCookie scopes the cookie to the OIDC endpoints, whatever prefix they are mounted under.
+func setOIDCStateCookie(ctx *gin.Context, value string, maxAge int) {
+	path := ctx.Request.URL.Path[:strings.LastIndex(ctx.Request.URL.Path, "/")]
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, value, maxAge, path, "", true, true)
+}
+
+func (h *AccountHandler) loginSuccess(ctx *gin.Context, res *dto.LoginResponse) {
+	newOTP := h.opts.NewOTP(res.ID, res.SessionID, "").Key
 
 	response := &dto.LoginResponseWithOTP{
 		LoginResponse: *res,
@@ -124,8 +252,11 @@ func (h *AccountHandler) Logout(ctx *gin.Context) {
 		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
 	}
 
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
 	// delete in database
-	_, errRe := h.accountService.Logout(ctx, int64(accountID), refreshToken)
+	_, errRe := h.accountService.Logout(ctx, int64(accountID), refreshToken, claims)
 
 	if errRe != nil {
 		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
@@ -149,6 +280,7 @@ func (h *AccountHandler) Logout(ctx *gin.Context) {
 //
 //	@Success		200	{object}	dto.RefreshTokenResponseDocs
 //	@Failure		400	{object}	dto.ErrorResponse
+//	@Failure		401	{object}	dto.ErrorResponse
 //	@Failure		500	{object}	dto.ErrorResponse
 //	@Router			/accounts/refresh-token [post]
 func (h *AccountHandler) RefreshToken(ctx *gin.Context) {
@@ -163,9 +295,15 @@ func (h *AccountHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	res, errRe := h.accountService.RefreshToken(ctx, accountID, refreshToken)
+	res, errRe := h.accountService.RefreshToken(ctx, accountID, refreshToken, sessionMetadata(ctx))
 
 	if errRe != nil {
+		var reused *service.ReusedRefreshTokenError
+		if errors.As(errRe, &reused) {
+			h.sendSecurityAlert(accountID, "refresh_token_reused")
+			h.wsManager.CloseSession(accountID, reused.SessionID)
+		}
+
 		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
 		return
 	}
@@ -176,7 +314,7 @@ func (h *AccountHandler) RefreshToken(ctx *gin.Context) {
 // CheckToken godoc
 //
 //	@Summary		check access token
-//	@Description	check token every user access web page
+//	@Description	check token every user access web page, returns websocket otp. Accepts personal access tokens with notifications:read scope
 //	@Tags			accounts
 //	@Accept			json
 //	@Produce		json
@@ -187,8 +325,402 @@ func (h *AccountHandler) RefreshToken(ctx *gin.Context) {
 //	@Failure		400	{object}	dto.ErrorResponse
 //	@Router			/accounts/check-token [get]
 func (h *AccountHandler) CheckToken(ctx *gin.Context) {
-	newOTP := h.opts.NewOTP().Key
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	newOTP := h.opts.NewOTP(claims.AccountID, claims.SessionID, claims.ID).Key
 	utils.SuccessResponse(ctx, http.StatusOK, &dto.CheckTokenResponse{
 		OTP: newOTP,
 	})
 }
+
+// GetProfile godoc
+//
+//	@Summary		Get account profile
+//	@Tags			accounts
+//	@Description	Get public profile of account with sharing stats
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.ProfileResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/{id} [get]
+func (h *AccountHandler) GetProfile(ctx *gin.Context) {
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRe := h.accountService.GetProfile(ctx, accountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// UpdateProfile godoc
+//
+//	@Summary		Update my profile
+//	@Tags			accounts
+//	@Description	Update fullname and bio of current account, omitted fields are kept
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.UpdateProfileRequest	true	"Profile payload"
+//	@Success		200		{object}	dto.AccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me [patch]
+func (h *AccountHandler) UpdateProfile(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.UpdateProfileRequest)
+
+	res, errRe := h.accountService.UpdateProfile(ctx, claims.AccountID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// UpdateAvatar godoc
+//
+//	@Summary		Upload my avatar
+//	@Tags			accounts
+//	@Description	Upload jpeg, png or gif image as avatar of current account, image is cropped and resized
+//	@Accept			multipart/form-data
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			avatar	formData	file	true	"Avatar image"
+//	@Success		200		{object}	dto.AccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		413		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/avatar [put]
+func (h *AccountHandler) UpdateAvatar(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, utils.MAX_AVATAR_UPLOAD_SIZE+1<<20)
+
+	fileHeader, err := ctx.FormFile("avatar")
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Missing avatar file")
+		return
+	}
+
+	if fileHeader.Size > utils.MAX_AVATAR_UPLOAD_SIZE {
+		utils.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, "Avatar must not be larger than 5MB")
+		return
+	}
+
+	file, err := fileHeader.Open()
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Cannot read avatar file")
+		return
+	}
+	defer file.Close()
+
+	image, err := io.ReadAll(io.LimitReader(file, utils.MAX_AVATAR_UPLOAD_SIZE))
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Cannot read avatar file")
+		return
+	}
+
+	res, errRe := h.accountService.UpdateAvatar(ctx, claims.AccountID, image)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change my password
+//	@Tags			accounts
+//	@Description	Change password of current account, revoke sessions on other devices and personal access tokens
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			X-Authorization	header		string						true	"Refresh Token of current session"
+//	@Param			request			body		dto.ChangePasswordRequest	true	"Change password payload"
+//	@Success		200				{object}	dto.ChangePasswordResponseDocs
+//	@Failure		400				{object}	dto.ResponseError
+//	@Failure		500				{object}	dto.ResponseError
+//	@Router			/accounts/me/password [post]
+func (h *AccountHandler) ChangePassword(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	parts := strings.Split(ctx.GetHeader("X-Authorization"), " ")
+
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Missing refresh token of current session")
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.ChangePasswordRequest)
+
+	res, errRe := h.accountService.ChangePassword(ctx, claims.AccountID, parts[1], &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	// force logout websocket connections of the revoked sessions
+	payload, err := json.Marshal(websock.EventForceLogoutMessage{Reason: "password_changed"})
+
+	if err != nil {
+		log.Println("error when marshaling json: ", err)
+	}
+
+	for _, sessionID := range res.RevokedSessions {
+		if err == nil {
+			h.wsManager.SendToSession(websock.Event{
+				Type:    websock.EventForceLogout,
+				Payload: payload,
+			}, claims.AccountID, sessionID)
+		}
+
+		h.wsManager.CloseSession(claims.AccountID, sessionID)
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// VerifyEmail godoc
+//
+//	@Summary		Verify email
+//	@Tags			accounts
+//	@Description	Verify email of account with token from verification link
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			request	body		dto.VerifyEmailRequest	true	"Verify email payload"
+//	@Success		200		{object}	dto.VerifyEmailResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/verify-email [post]
+func (h *AccountHandler) VerifyEmail(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.VerifyEmailRequest)
+
+	res, errRe := h.accountService.VerifyEmail(ctx, data.Token)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// ResendVerificationEmail godoc
+//
+//	@Summary		Resend verification email
+//	@Tags			accounts
+//	@Description	Send a new verification link to email of current account
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Success		200	{object}	dto.ResendVerificationEmailResponseDocs
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Router			/accounts/verify-email/resend [post]
+func (h *AccountHandler) ResendVerificationEmail(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	res, errRe := h.accountService.ResendVerificationEmail(ctx, claims.AccountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// sendSecurityAlert warns every active websocket session of account.
+func (h *AccountHandler) sendSecurityAlert(accountID int64, reason string) {
+	payload, err := json.Marshal(websock.EventSecurityMessage{Reason: reason})
+
+	if err != nil {
+		log.Println("error when marshaling json: ", err)
+		return
+	}
+
+	h.wsManager.SendToAccounts(websock.Event{
+		Type:    websock.EventSecurity,
+		Payload: payload,
+	}, []int64{accountID}, "")
+}
+
+// ListSessions godoc
+//
+//	@Summary		List my active sessions
+//	@Tags			accounts
+//	@Description	List devices where current account is logged in
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Success		200	{object}	dto.ListSessionsResponseDocs
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/me/sessions [get]
+func (h *AccountHandler) ListSessions(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	res, errRe := h.accountService.ListSessions(ctx, claims.AccountID, claims.SessionID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Tags			accounts
+//	@Description	Log out one device of current account and close its websocket connections
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		string	true	"Session ID"
+//	@Success		200	{object}	dto.RevokeSessionResponseDocs
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/me/sessions/{id} [delete]
+func (h *AccountHandler) RevokeSession(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	sessionID := ctx.Param("id")
+
+	res, errRe := h.accountService.RevokeSession(ctx, claims.AccountID, sessionID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseSession(claims.AccountID, sessionID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// DeleteAccount godoc
+//
+//	@Summary		Delete my account
+//	@Tags			accounts
+//	@Description	Soft delete current account, log out all of its sessions and close its websocket connections. It can be restored until purged after the retention period
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.DeleteMyAccountRequest	true	"Delete account payload"
+//	@Success		200		{object}	dto.DeleteAccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me [delete]
+func (h *AccountHandler) DeleteAccount(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.DeleteMyAccountRequest)
+
+	res, errRe := h.accountService.DeleteAccount(ctx, claims.AccountID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseAccount(claims.AccountID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RestoreAccount godoc
+//
+//	@Summary		Restore my account
+//	@Tags			accounts
+//	@Description	Restore account deleted by its owner with its email and password, then log in as usual. Accounts deleted by an admin are only restored by an admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.RestoreAccountRequest	true	"Restore account payload"
+//	@Success		200		{object}	dto.RestoreAccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		429		{object}	dto.ResponseError	"Too many failed attempts, retry after Retry-After seconds"
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/restore [post]
+func (h *AccountHandler) RestoreAccount(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.RestoreAccountRequest)
+
+	res, errRe := h.accountService.RestoreAccount(ctx, &data, sessionMetadata(ctx))
+
+	if errRe != nil {
+		if errRe.RetryAfter > 0 {
+			utils.RetryAfter(ctx, errRe.RetryAfter)
+		}
+
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// sessionMetadata reads client info of request, values are cut to fit refresh_token columns.
+func sessionMetadata(ctx *gin.Context) *dto.SessionMetadata {
+	return &dto.SessionMetadata{
+		DeviceLabel: truncate(strings.TrimSpace(ctx.GetHeader("X-Device-Label")), 255),
+		UserAgent:   truncate(ctx.Request.UserAgent(), 512),
+		IPAddress:   truncate(ctx.ClientIP(), 45),
+	}
+}
+
+func truncate(s string, maxChars int) string {
+	if runes := []rune(s); len(runes) > maxChars {
+		return string(runes[:maxChars])
+	}
+
+	return s
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/internal/websock"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService service.AdminService
+	wsManager    *websock.Manager
+}
+
+func NewAdminHandler(adminService service.AdminService, wsManager *websock.Manager) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+		wsManager:    wsManager,
+	}
+}
+
+// ListAccounts godoc
+//
+//	@Summary		List accounts
+//	@Tags			admin
+//	@Description	List accounts newest first, optionally searching email and full name. Only soft deleted accounts are listed with deleted=true
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			q		query		string	false	"Search email or full name"
+//	@Param			deleted	query		bool	false	"List soft deleted accounts"
+//	@Param			limit	query		int		true	"Limit number of records returned"
+//	@Param			page	query		int		true	"page"
+//	@Success		200		{object}	dto.ListAdminAccountsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/admin/accounts [get]
+func (h *AdminHandler) ListAccounts(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.adminService.ListAccounts(ctx, ctx.Query("q"), ctx.Query("deleted") == "true", limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// UpdateRole godoc
+//
+//	@Summary		Change role of account
+//	@Tags			admin
+//	@Description	Make account a user, moderator or admin. Admins can not change their own role
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int						true	"Account ID"
+//	@Param			request	body		dto.UpdateRoleRequest	true	"Update role payload"
+//	@Success		200		{object}	dto.AccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id}/role [put]
+func (h *AdminHandler) UpdateRole(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.UpdateRoleRequest)
+
+	res, errRe := h.adminService.UpdateRole(ctx, claims.AccountID, accountID, entities.Role(data.Role))
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Suspend godoc
+//
+//	@Summary		Suspend account
+//	@Tags			admin
+//	@Description	Suspend account for duration_hours, or until unsuspended when it is omitted. All sessions of the account are logged out
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int							true	"Account ID"
+//	@Param			request	body		dto.SuspendAccountRequest	true	"Suspend payload"
+//	@Success		200		{object}	dto.AdminAccountResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		409		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id}/suspend [post]
+func (h *AdminHandler) Suspend(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.SuspendAccountRequest)
+
+	res, errRe := h.adminService.Suspend(ctx, claims.AccountID, accountID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseAccount(accountID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Unsuspend godoc
+//
+//	@Summary		Unsuspend account
+//	@Tags			admin
+//	@Description	Lift suspension of account
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.AdminAccountResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id}/suspend [delete]
+func (h *AdminHandler) Unsuspend(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRe := h.adminService.Unsuspend(ctx, claims.AccountID, accountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// ForceLogout godoc
+//
+//	@Summary		Force logout account
+//	@Tags			admin
+//	@Description	Log account out of every session and close its websocket connections
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.ForceLogoutResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRe := h.adminService.ForceLogout(ctx, claims.AccountID, accountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseAccount(accountID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// DeleteAccount godoc
+//
+//	@Summary		Delete account
+//	@Tags			admin
+//	@Description	Soft delete account and log out all of its sessions, it can be restored until purged after the retention period. With permanent=true delete account and everything it owns for good, including its videos. This can not be undone
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id			path		int		true	"Account ID"
+//	@Param			permanent	query		bool	false	"Delete for good"
+//	@Success		200			{object}	dto.DeleteAccountResponseDocs
+//	@Failure		400			{object}	dto.ResponseError
+//	@Failure		403			{object}	dto.ResponseError
+//	@Failure		404			{object}	dto.ResponseError
+//	@Failure		409			{object}	dto.ResponseError
+//	@Failure		500			{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id} [delete]
+func (h *AdminHandler) DeleteAccount(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRe := h.adminService.DeleteAccount(ctx, claims.AccountID, accountID, ctx.Query("permanent") == "true")
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseAccount(accountID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RestoreAccount godoc
+//
+//	@Summary		Restore account
+//	@Tags			admin
+//	@Description	Restore soft deleted account that is not purged yet, its owner can log in again
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.AdminAccountResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/admin/accounts/{id}/restore [post]
+func (h *AdminHandler) RestoreAccount(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRe := h.adminService.RestoreAccount(ctx, claims.AccountID, accountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEvents godoc
+//
+//	@Summary		List audit events
+//	@Tags			admin
+//	@Description	List audit events newest first, filtered by actor, target, action and a time range. from is inclusive, to is exclusive
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			actor_id	query		int		false	"Account that performed the action"
+//	@Param			target_type	query		string	false	"account, video, video_report or personal_access_token"
+//	@Param			target_id	query		int		false	"ID of the target"
+//	@Param			action		query		string	false	"Action, e.g. login_failed"
+//	@Param			from		query		string	false	"RFC 3339 time"
+//	@Param			to			query		string	false	"RFC 3339 time"
+//	@Param			limit		query		int		true	"Limit number of records returned"
+//	@Param			page		query		int		true	"page"
+//	@Success		200			{object}	dto.ListAuditEventsResponseDocs
+//	@Failure		400			{object}	dto.ResponseError
+//	@Failure		403			{object}	dto.ResponseError
+//	@Failure		500			{object}	dto.ResponseError
+//	@Router			/admin/audit-events [get]
+func (h *AuditHandler) ListEvents(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	filter := &dto.AuditEventFilter{
+		TargetType: ctx.Query("target_type"),
+		Action:     ctx.Query("action"),
+	}
+
+	if actorID := ctx.Query("actor_id"); actorID != "" {
+		filter.ActorID, err = strconv.ParseInt(actorID, 10, 64)
+		if err != nil || filter.ActorID <= 0 {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid actor_id parameter")
+			return
+		}
+	}
+
+	if targetID := ctx.Query("target_id"); targetID != "" {
+		filter.TargetID, err = strconv.ParseInt(targetID, 10, 64)
+		if err != nil || filter.TargetID <= 0 {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid target_id parameter")
+			return
+		}
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid from parameter")
+			return
+		}
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid to parameter")
+			return
+		}
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.auditService.ListEvents(ctx, filter, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// ListMyActivity godoc
+//
+//	@Summary		List my security activity
+//	@Tags			accounts
+//	@Description	List logins, failed logins, password and MFA changes and other security events of current account, newest first. Admins and moderators acting on the account are not identified
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListAuditEventsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/security-activity [get]
+func (h *AuditHandler) ListMyActivity(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.auditService.ListAccountActivity(ctx, claims.AccountID, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
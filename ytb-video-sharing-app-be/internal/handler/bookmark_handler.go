@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkHandler struct {
+	bookmarkService service.BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkService service.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService: bookmarkService,
+	}
+}
+
+// Bookmark godoc
+//
+//	@Summary		Bookmark video
+//	@Tags			bookmarks
+//	@Description	Save video to watch later. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.BookmarkResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/videos/{id}/bookmark [post]
+func (b *BookmarkHandler) Bookmark(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRes := b.bookmarkService.Bookmark(ctx, claims.AccountID, videoID)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// DeleteBookmark godoc
+//
+//	@Summary		Delete bookmark
+//	@Tags			bookmarks
+//	@Description	Remove video from bookmarks. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.DeleteBookmarkResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/videos/{id}/bookmark [delete]
+func (b *BookmarkHandler) DeleteBookmark(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRes := b.bookmarkService.DeleteBookmark(ctx, claims.AccountID, videoID)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetBookmarkedVideos godoc
+//
+//	@Summary		Get bookmarked videos
+//	@Tags			bookmarks
+//	@Description	Get videos bookmarked by current account, the latest bookmarked first. Accepts personal access tokens with videos:read scope
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListVideosResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/bookmarks [get]
+func (b *BookmarkHandler) GetBookmarkedVideos(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := b.bookmarkService.GetBookmarkedVideos(ctx, claims.AccountID, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followService service.FollowService
+}
+
+func NewFollowHandler(followService service.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// Follow godoc
+//
+//	@Summary		Follow account
+//	@Tags			follows
+//	@Description	Follow another account to receive its videos in feed
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.FollowResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/{id}/follow [post]
+func (f *FollowHandler) Follow(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRes := f.followService.Follow(ctx, claims.AccountID, accountID)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Unfollow godoc
+//
+//	@Summary		Unfollow account
+//	@Tags			follows
+//	@Description	Stop following an account
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Account ID"
+//	@Success		200	{object}	dto.UnfollowResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/{id}/follow [delete]
+func (f *FollowHandler) Unfollow(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return
+	}
+
+	res, errRes := f.followService.Unfollow(ctx, claims.AccountID, accountID)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetFollowers godoc
+//
+//	@Summary		Get followers
+//	@Tags			follows
+//	@Description	Get list accounts following the account
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			id		path		int	true	"Account ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListAccountsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/{id}/followers [get]
+func (f *FollowHandler) GetFollowers(ctx *gin.Context) {
+	accountID, limit, page, ok := parseAccountListParams(ctx)
+	if !ok {
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := f.followService.GetFollowers(ctx, accountID, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// GetFollowing godoc
+//
+//	@Summary		Get following
+//	@Tags			follows
+//	@Description	Get list accounts the account is following
+//	@Accept			json
+//	@Produce		json
+//
+//	@Param			id		path		int	true	"Account ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListAccountsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/{id}/following [get]
+func (f *FollowHandler) GetFollowing(ctx *gin.Context) {
+	accountID, limit, page, ok := parseAccountListParams(ctx)
+	if !ok {
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := f.followService.GetFollowing(ctx, accountID, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+func parseAccountListParams(ctx *gin.Context) (int64, int, int, bool) {
+	accountID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid account id")
+		return 0, 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return 0, 0, 0, false
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return 0, 0, 0, false
+	}
+
+	return accountID, limit, page, true
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSHandler struct {
+	keyManager *utils.KeyManager
+}
+
+func NewJWKSHandler(keyManager *utils.KeyManager) *JWKSHandler {
+	return &JWKSHandler{
+		keyManager: keyManager,
+	}
+}
+
+// GetJWKS godoc
+//
+//	@Summary		JSON Web Key Set
+//	@Tags			keys
+//	@Description	Public keys verifying tokens issued by this service, in standard JWKS format
+//	@Produce		json
+//	@Success		200	{object}	utils.JWKSet
+//	@Router			/.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(ctx *gin.Context) {
+	// verifiers must pick up a rotated key soon, keep cache short
+	ctx.Header("Cache-Control", "public, max-age=300")
+
+	// plain JWKS document instead of the usual response envelope, so standard clients can read it
+	ctx.JSON(http.StatusOK, h.keyManager.JWKS())
+}
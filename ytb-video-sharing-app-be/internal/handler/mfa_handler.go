@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// Enroll godoc
+//
+//	@Summary		Enroll TOTP two-factor authentication
+//	@Tags			accounts
+//	@Description	Generate a TOTP secret, 2FA is enabled once a code of it is verified
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Success		200	{object}	dto.EnrollMFAResponseDocs
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/me/mfa/enroll [post]
+func (h *MFAHandler) Enroll(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	res, errRe := h.mfaService.Enroll(ctx, claims.AccountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Verify godoc
+//
+//	@Summary		Enable TOTP two-factor authentication
+//	@Tags			accounts
+//	@Description	Verify a code of the enrolled secret to enable 2FA, recovery codes are returned only once
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.VerifyMFARequest	true	"Verify MFA payload"
+//	@Success		200		{object}	dto.VerifyMFAResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		409		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/mfa/verify [post]
+func (h *MFAHandler) Verify(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.VerifyMFARequest)
+
+	res, errRe := h.mfaService.Verify(ctx, claims.AccountID, data.Code)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Disable godoc
+//
+//	@Summary		Disable TOTP two-factor authentication
+//	@Tags			accounts
+//	@Description	Disable 2FA with a TOTP or recovery code, remaining recovery codes are deleted
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.DisableMFARequest	true	"Disable MFA payload"
+//	@Success		200		{object}	dto.DisableMFAResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/mfa/disable [post]
+func (h *MFAHandler) Disable(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.DisableMFARequest)
+
+	res, errRe := h.mfaService.Disable(ctx, claims.AccountID, data.Code)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
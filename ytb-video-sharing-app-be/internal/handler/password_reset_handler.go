@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordResetHandler struct {
+	passwordResetService service.PasswordResetService
+}
+
+func NewPasswordResetHandler(passwordResetService service.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{
+		passwordResetService: passwordResetService,
+	}
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Forgot password
+//	@Tags			accounts
+//	@Description	Send reset password link to email, response is the same whether the email exists or not
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.ForgotPasswordRequest	true	"Forgot password payload"
+//	@Success		200		{object}	dto.ForgotPasswordResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/password/forgot [post]
+func (h *PasswordResetHandler) ForgotPassword(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.ForgotPasswordRequest)
+
+	res, err := h.passwordResetService.ForgotPassword(ctx, data.Email)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Tags			accounts
+//	@Description	Set new password using token from reset password email, every session of the account is revoked
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.ResetPasswordRequest	true	"Reset password payload"
+//	@Success		200		{object}	dto.ResetPasswordResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/password/reset [post]
+func (h *PasswordResetHandler) ResetPassword(ctx *gin.Context) {
+	req, _ := ctx.Get("data")
+	data := req.(dto.ResetPasswordRequest)
+
+	res, err := h.passwordResetService.ResetPassword(ctx, data.Token, data.NewPassword)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
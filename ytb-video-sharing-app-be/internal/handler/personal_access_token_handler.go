@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/internal/websock"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalAccessTokenHandler struct {
+	personalAccessTokenService service.PersonalAccessTokenService
+	wsManager                  *websock.Manager
+}
+
+func NewPersonalAccessTokenHandler(personalAccessTokenService service.PersonalAccessTokenService, wsManager *websock.Manager) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		personalAccessTokenService: personalAccessTokenService,
+		wsManager:                  wsManager,
+	}
+}
+
+// Create godoc
+//
+//	@Summary		Create personal access token
+//	@Tags			accounts
+//	@Description	Create a long lived token for scripts and bots, limited to the given scopes (videos:write, videos:read, notifications:read). The token is returned only once
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.CreatePersonalAccessTokenRequest	true	"Create personal access token payload"
+//	@Success		201		{object}	dto.CreatePersonalAccessTokenResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		409		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/me/tokens [post]
+func (h *PersonalAccessTokenHandler) Create(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.CreatePersonalAccessTokenRequest)
+
+	res, errRe := h.personalAccessTokenService.Create(ctx, claims.AccountID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, res)
+}
+
+// List godoc
+//
+//	@Summary		List personal access tokens
+//	@Tags			accounts
+//	@Description	List personal access tokens of current account that are not revoked, only their prefix is shown
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Success		200	{object}	dto.ListPersonalAccessTokensResponseDocs
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/me/tokens [get]
+func (h *PersonalAccessTokenHandler) List(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	res, errRe := h.personalAccessTokenService.List(ctx, claims.AccountID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Revoke godoc
+//
+//	@Summary		Revoke personal access token
+//	@Tags			accounts
+//	@Description	Revoke personal access token of current account and close websocket connections opened with it
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Personal access token ID"
+//	@Success		200	{object}	dto.RevokePersonalAccessTokenResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/accounts/me/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) Revoke(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid token id")
+		return
+	}
+
+	res, errRe := h.personalAccessTokenService.Revoke(ctx, claims.AccountID, id)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.wsManager.CloseSession(claims.AccountID, utils.PersonalAccessTokenSessionID(id))
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,498 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/internal/websock"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PlaylistHandler struct {
+	playlistService service.PlaylistService
+	wsManager       *websock.Manager
+}
+
+func NewPlaylistHandler(playlistService service.PlaylistService, wsManager *websock.Manager) *PlaylistHandler {
+	return &PlaylistHandler{
+		playlistService: playlistService,
+		wsManager:       wsManager,
+	}
+}
+
+// Create godoc
+//
+//	@Summary		Create playlist
+//	@Tags			playlists
+//	@Description	Create an empty playlist, private unless visibility is given. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			request	body		dto.CreatePlaylistRequest	true	"Create playlist payload"
+//	@Success		201		{object}	dto.PlaylistResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists [post]
+func (h *PlaylistHandler) Create(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.CreatePlaylistRequest)
+
+	res, errRe := h.playlistService.Create(ctx, claims.AccountID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, res)
+}
+
+// GetPlaylist godoc
+//
+//	@Summary		Get playlist
+//	@Tags			playlists
+//	@Description	Get playlist. Private playlists are only shown to their owner, logging in is optional
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Playlist ID"
+//	@Success		200	{object}	dto.PlaylistResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Router			/playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(ctx *gin.Context) {
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	res, errRe := h.playlistService.GetPlaylist(ctx, viewerAccountID(ctx), playlistID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetAccountPlaylists godoc
+//
+//	@Summary		Get playlists of account
+//	@Tags			playlists
+//	@Description	Get public playlists of account newest first, the owner also sees unlisted and private ones. Logging in is optional
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int	true	"Account ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListPlaylistsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/{id}/playlists [get]
+func (h *PlaylistHandler) GetAccountPlaylists(ctx *gin.Context) {
+	accountID, limit, page, ok := parseAccountListParams(ctx)
+	if !ok {
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.playlistService.GetPlaylistsByAccount(ctx, accountID, viewerAccountID(ctx), limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// Update godoc
+//
+//	@Summary		Update playlist
+//	@Tags			playlists
+//	@Description	Rename own playlist or change its visibility. Watchers of the playlist are notified. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int							true	"Playlist ID"
+//	@Param			request	body		dto.UpdatePlaylistRequest	true	"Update playlist payload"
+//	@Success		200		{object}	dto.PlaylistResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists/{id} [patch]
+func (h *PlaylistHandler) Update(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.UpdatePlaylistRequest)
+
+	res, errRe := h.playlistService.Update(ctx, claims.AccountID, playlistID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	// a playlist made private is no longer watched by anyone but its owner
+	if res.Visibility == string(entities.PlaylistPrivate) {
+		h.wsManager.UnwatchExcept(playlistTopic(playlistID), claims.AccountID)
+	}
+
+	h.notifyWatchers(ctx, websock.EventPlaylistMessage{PlaylistID: playlistID, Change: "updated"})
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Delete godoc
+//
+//	@Summary		Delete playlist
+//	@Tags			playlists
+//	@Description	Delete own playlist, the videos in it are kept. Watchers of the playlist are notified. Accepts personal access tokens with videos:write scope
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Playlist ID"
+//	@Success		200	{object}	dto.DeletePlaylistResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/playlists/{id} [delete]
+func (h *PlaylistHandler) Delete(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	res, errRe := h.playlistService.Delete(ctx, claims.AccountID, playlistID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.notifyWatchers(ctx, websock.EventPlaylistMessage{PlaylistID: playlistID, Change: "deleted"})
+	h.wsManager.UnwatchExcept(playlistTopic(playlistID), 0)
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetVideos godoc
+//
+//	@Summary		Get videos of playlist
+//	@Tags			playlists
+//	@Description	Get videos of playlist in order. Hidden and deleted videos are left out of the page but keep their position. Logging in is optional
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int	true	"Playlist ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListPlaylistVideosResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/videos [get]
+func (h *PlaylistHandler) GetVideos(ctx *gin.Context) {
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.playlistService.GetVideos(ctx, viewerAccountID(ctx), playlistID, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// AddVideo godoc
+//
+//	@Summary		Add video to playlist
+//	@Tags			playlists
+//	@Description	Append video to the end of own playlist. Watchers of the playlist are notified. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int							true	"Playlist ID"
+//	@Param			request	body		dto.AddPlaylistVideoRequest	true	"Add video payload"
+//	@Success		200		{object}	dto.PlaylistItemResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		409		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/videos [post]
+func (h *PlaylistHandler) AddVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.AddPlaylistVideoRequest)
+
+	res, errRe := h.playlistService.AddVideo(ctx, claims.AccountID, playlistID, data.VideoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.notifyWatchers(ctx, websock.EventPlaylistMessage{PlaylistID: playlistID, Change: "video_added", VideoID: res.VideoID, Position: res.Position})
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RemoveVideo godoc
+//
+//	@Summary		Remove video from playlist
+//	@Tags			playlists
+//	@Description	Remove video from own playlist, the videos after it move up. Watchers of the playlist are notified. Accepts personal access tokens with videos:write scope
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int	true	"Playlist ID"
+//	@Param			videoID	path		int	true	"Video ID"
+//	@Success		200		{object}	dto.PlaylistItemResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/videos/{videoID} [delete]
+func (h *PlaylistHandler) RemoveVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, videoID, ok := parsePlaylistVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	res, errRe := h.playlistService.RemoveVideo(ctx, claims.AccountID, playlistID, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.notifyWatchers(ctx, websock.EventPlaylistMessage{PlaylistID: playlistID, Change: "video_removed", VideoID: videoID})
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// MoveVideo godoc
+//
+//	@Summary		Move video in playlist
+//	@Tags			playlists
+//	@Description	Move video of own playlist to position, the videos in between shift by one. Watchers of the playlist are notified. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int								true	"Playlist ID"
+//	@Param			videoID	path		int								true	"Video ID"
+//	@Param			request	body		dto.MovePlaylistVideoRequest	true	"Move video payload"
+//	@Success		200		{object}	dto.PlaylistItemResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/videos/{videoID}/position [put]
+func (h *PlaylistHandler) MoveVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, videoID, ok := parsePlaylistVideoIDs(ctx)
+	if !ok {
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.MovePlaylistVideoRequest)
+
+	res, errRe := h.playlistService.MoveVideo(ctx, claims.AccountID, playlistID, videoID, data.Position)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	h.notifyWatchers(ctx, websock.EventPlaylistMessage{PlaylistID: playlistID, Change: "video_moved", VideoID: videoID, Position: res.Position})
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// Watch godoc
+//
+//	@Summary		Watch playlist
+//	@Tags			playlists
+//	@Description	Receive playlist_changed websocket events on connection conn_id whenever the playlist changes, until unwatched or the connection closes
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int		true	"Playlist ID"
+//	@Param			conn_id	query		string	true	"WebSocket connection ID"
+//	@Success		200		{object}	dto.WatchPlaylistResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/watch [post]
+func (h *PlaylistHandler) Watch(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	connID := ctx.Query("conn_id")
+
+	if connID == "" {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Missing conn_id in query")
+		return
+	}
+
+	if _, errRe := h.playlistService.GetPlaylist(ctx, claims.AccountID, playlistID); errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	if !h.wsManager.Watch(playlistTopic(playlistID), connID, claims.AccountID) {
+		utils.ErrorResponse(ctx, http.StatusNotFound, "WebSocket connection is not found")
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, &dto.WatchPlaylistResponse{PlaylistID: playlistID, Watching: true})
+}
+
+// Unwatch godoc
+//
+//	@Summary		Unwatch playlist
+//	@Tags			playlists
+//	@Description	Stop receiving playlist_changed websocket events on connection conn_id
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int		true	"Playlist ID"
+//	@Param			conn_id	query		string	true	"WebSocket connection ID"
+//	@Success		200		{object}	dto.WatchPlaylistResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Router			/playlists/{id}/watch [delete]
+func (h *PlaylistHandler) Unwatch(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return
+	}
+
+	connID := ctx.Query("conn_id")
+
+	if connID == "" {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Missing conn_id in query")
+		return
+	}
+
+	h.wsManager.Unwatch(playlistTopic(playlistID), connID, claims.AccountID)
+
+	utils.SuccessResponse(ctx, http.StatusOK, &dto.WatchPlaylistResponse{PlaylistID: playlistID, Watching: false})
+}
+
+// notifyWatchers sends the change to every connection watching the playlist but the one conn_id that made it.
+func (h *PlaylistHandler) notifyWatchers(ctx *gin.Context, message websock.EventPlaylistMessage) {
+	payload, err := json.Marshal(message)
+
+	if err != nil {
+		log.Println("error when marshaling json: ", err)
+		return
+	}
+
+	h.wsManager.SendToWatchers(websock.Event{
+		Type:    websock.EventPlaylist,
+		Payload: payload,
+	}, playlistTopic(message.PlaylistID), ctx.Query("conn_id"))
+}
+
+func playlistTopic(playlistID int64) string {
+	return "playlist:" + strconv.FormatInt(playlistID, 10)
+}
+
+func parsePlaylistID(ctx *gin.Context) (int64, bool) {
+	playlistID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid playlist id")
+		return 0, false
+	}
+
+	return playlistID, true
+}
+
+func parsePlaylistVideoIDs(ctx *gin.Context) (int64, int64, bool) {
+	playlistID, ok := parsePlaylistID(ctx)
+	if !ok {
+		return 0, 0, false
+	}
+
+	videoID, err := strconv.ParseInt(ctx.Param("videoID"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return 0, 0, false
+	}
+
+	return playlistID, videoID, true
+}
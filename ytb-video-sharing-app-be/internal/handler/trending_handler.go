@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrendingHandler struct {
+	trendingService service.TrendingService
+}
+
+func NewTrendingHandler(trendingService service.TrendingService) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+	}
+}
+
+// GetTrendingVideos godoc
+//
+//	@Summary		Get trending videos
+//	@Tags			videos
+//	@Description	Get videos shared within window, ranked by their votes decayed by age. Scores are recomputed in the background every TRENDING_RECOMPUTE_INTERVAL, so new votes and videos show up after a while. Logging in is optional
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			window	query		string	false	"day, week or all, TRENDING_DEFAULT_WINDOW by default"	Enums(day, week, all)
+//	@Param			limit	query		int		true	"Limit number of records returned"
+//	@Param			page	query		int		true	"page"
+//	@Success		200		{object}	dto.ListVideosResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/videos/trending [get]
+func (t *TrendingHandler) GetTrendingVideos(ctx *gin.Context) {
+	window := entities.TrendingWindow(ctx.Query("window"))
+	if window != "" && !window.Valid() {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid window parameter")
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := t.trendingService.GetTrendingVideos(ctx, window, viewerAccountID(ctx), limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
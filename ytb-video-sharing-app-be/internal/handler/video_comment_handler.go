@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VideoCommentHandler struct {
+	videoCommentService service.VideoCommentService
+}
+
+func NewVideoCommentHandler(videoCommentService service.VideoCommentService) *VideoCommentHandler {
+	return &VideoCommentHandler{
+		videoCommentService: videoCommentService,
+	}
+}
+
+// Comment godoc
+//
+//	@Summary		Comment on video
+//	@Tags			comments
+//	@Description	Add comment to video, comments count towards trending. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int							true	"Video ID"
+//	@Param			request	body		dto.CommentVideoRequest		true	"Comment payload"
+//	@Success		200		{object}	dto.VideoCommentResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/videos/{id}/comments [post]
+func (v *VideoCommentHandler) Comment(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.CommentVideoRequest)
+
+	res, errRes := v.videoCommentService.Comment(ctx, claims.AccountID, videoID, data.Content)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetComments godoc
+//
+//	@Summary		Get comments of video
+//	@Tags			comments
+//	@Description	Get comments of video, the oldest first. Logging in is optional
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int	true	"Video ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListVideoCommentsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/videos/{id}/comments [get]
+func (v *VideoCommentHandler) GetComments(ctx *gin.Context) {
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := v.videoCommentService.GetComments(ctx, videoID, viewerAccountID(ctx), limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// DeleteComment godoc
+//
+//	@Summary		Delete comment
+//	@Tags			comments
+//	@Description	Delete own comment. Accepts personal access tokens with videos:write scope
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Comment ID"
+//	@Success		200	{object}	dto.DeleteVideoCommentResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/comments/{id} [delete]
+func (v *VideoCommentHandler) DeleteComment(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	commentID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	res, errRes := v.videoCommentService.DeleteComment(ctx, claims.AccountID, commentID)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
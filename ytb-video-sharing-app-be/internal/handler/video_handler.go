@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"ytb-video-sharing-app-be/internal/dto"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/internal/service"
@@ -16,13 +17,15 @@ import (
 
 type VideoHandler struct {
 	videoService  service.VideoService
+	followService service.FollowService
 	// messageBroker pkg.Queue
 	wsManager     *websock.Manager
 }
 
-func NewVideoHandler(videoService service.VideoService, wsManager *websock.Manager) *VideoHandler {
+func NewVideoHandler(videoService service.VideoService, followService service.FollowService, wsManager *websock.Manager) *VideoHandler {
 	return &VideoHandler{
 		videoService:  videoService,
+		followService: followService,
 		// messageBroker: messageBroker,
 		wsManager:     wsManager,
 	}
@@ -32,7 +35,7 @@ func NewVideoHandler(videoService service.VideoService, wsManager *websock.Manag
 //
 //	@Summary		Share new video
 //	@Tags			videos
-//	@Description	Create new video and return itself.
+//	@Description	Create new video and return itself. Up to 5 tags are lowercased and saved with it. Accepts personal access tokens with videos:write scope.
 //	@Accept			json
 //	@Produce		json
 //
@@ -69,6 +72,7 @@ func (v *VideoHandler) ShareVideo(ctx *gin.Context) {
 		DownVote:    data.DownVote,
 		Thumbnail:   data.Thumbnail,
 		VideoUrl:    data.VideoUrl,
+		Tags:        data.Tags,
 	})
 
 	if err != nil {
@@ -90,13 +94,20 @@ func (v *VideoHandler) ShareVideo(ctx *gin.Context) {
 	// 	v.messageBroker.Produce(os.Getenv("KAFKA_TOPIC"), payloadBytes)
 	// }()
 
-	// send through websocket
+	// send through websocket to followers of the sharer
 	// go func() {
 	if connID != "" {
+		followerIDs, errRes := v.followService.GetFollowerIDs(ctx, claims.AccountID)
+
+		if errRes != nil {
+			log.Println("error when getting followers: ", errRes.Message)
+		}
+
 		newEvent := websock.EventNotificationMessage{
 			Title:     data.Title,
 			SharedBy:  claims.Email,
 			Thumbnail: data.Thumbnail,
+			Tags:      res.Tags,
 		}
 
 		payload, err := json.Marshal(newEvent)
@@ -106,10 +117,10 @@ func (v *VideoHandler) ShareVideo(ctx *gin.Context) {
 			return
 		}
 
-		v.wsManager.SendBroadCast(websock.Event{
+		v.wsManager.SendToAccounts(websock.Event{
 			Type:    "new_video",
 			Payload: payload,
-		}, connID)
+		}, followerIDs, connID)
 	}
 	// }()
 
@@ -120,12 +131,15 @@ func (v *VideoHandler) ShareVideo(ctx *gin.Context) {
 //
 //	@Summary		Get list videos
 //	@Tags			videos
-//	@Description	Get list videos
+//	@Description	Get list videos, optionally only those with a tag. Hidden videos are only listed for their owner, logging in is optional
 //	@Accept			json
 //	@Produce		json
 //
-//	@Param			limit	query		int	true	"Limit number of records returned"
-//	@Param			page	query		int	true	"page"
+//	@Security		BearerAuth
+//
+//	@Param			tag		query		string	false	"Only list videos with this tag"
+//	@Param			limit	query		int		true	"Limit number of records returned"
+//	@Param			page	query		int		true	"page"
 //	@Success		200		{object}	dto.ListVideosResponseDocs
 //	@Failure		400		{object}	dto.ResponseError
 //	@Failure		500		{object}	dto.ResponseError
@@ -143,8 +157,17 @@ func (v *VideoHandler) GetListVideos(ctx *gin.Context) {
 		return
 	}
 
+	tag := ctx.Query("tag")
+	if tag != "" {
+		var ok bool
+		if tag, ok = utils.NormalizeTag(tag); !ok {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid tag parameter")
+			return
+		}
+	}
+
 	// Call service to get videos
-	res, totalItems, totalPages, isNext, isPrevious, errRes := v.videoService.GetListVideos(ctx, limit, page)
+	res, totalItems, totalPages, isNext, isPrevious, errRes := v.videoService.GetListVideos(ctx, viewerAccountID(ctx), tag, limit, page)
 	if errRes != nil {
 		utils.ErrorResponse(ctx, errRes.Code, errRes)
 		return
@@ -152,3 +175,233 @@ func (v *VideoHandler) GetListVideos(ctx *gin.Context) {
 
 	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
 }
+
+// ListTags godoc
+//
+//	@Summary		List tags
+//	@Tags			videos
+//	@Description	List tags starting with q for autocomplete, the most used first. Only visible videos are counted
+//	@Produce		json
+//
+//	@Param			q		query		string	false	"Start of the tag"
+//	@Param			limit	query		int		true	"Limit number of records returned"
+//	@Success		200		{object}	dto.ListTagsResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/tags [get]
+func (v *VideoHandler) ListTags(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	res, errRes := v.videoService.ListTags(ctx, strings.ToLower(strings.TrimSpace(ctx.Query("q"))), limit)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// GetFeed godoc
+//
+//	@Summary		Get following feed
+//	@Tags			videos
+//	@Description	Get videos shared by accounts the caller follows, newest first. Accepts personal access tokens with videos:read scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			cursor	query		int	false	"next_cursor returned by the previous page"
+//	@Success		200		{object}	dto.FeedResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/feed [get]
+func (v *VideoHandler) GetFeed(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	var cursor int64
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		cursor, err = strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || cursor < 0 {
+			utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid cursor parameter")
+			return
+		}
+	}
+
+	res, nextCursor, hasMore, errRes := v.videoService.GetFeed(ctx, claims.AccountID, cursor, limit)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.CursorResponse(ctx, res, limit, nextCursor, hasMore)
+}
+
+// GetAccountVideos godoc
+//
+//	@Summary		Get videos shared by account
+//	@Tags			videos
+//	@Description	Get sharing history of account, newest first. Hidden videos are only listed for their owner, logging in is optional
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int	true	"Account ID"
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListVideosResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/accounts/{id}/videos [get]
+func (v *VideoHandler) GetAccountVideos(ctx *gin.Context) {
+	accountID, limit, page, ok := parseAccountListParams(ctx)
+	if !ok {
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := v.videoService.GetListVideosByAccount(ctx, accountID, viewerAccountID(ctx), limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// DeleteVideo godoc
+//
+//	@Summary		Delete video
+//	@Tags			videos
+//	@Description	Soft delete own video, moderators can delete any video. It can be restored until purged after the retention period. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.DeleteVideoResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/videos/{id} [delete]
+func (v *VideoHandler) DeleteVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRe := v.videoService.DeleteVideo(ctx, claims.AccountID, claims.Role, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RestoreVideo godoc
+//
+//	@Summary		Restore video
+//	@Tags			videos
+//	@Description	Restore soft deleted video that is not purged yet. Owners restore videos they deleted themselves, moderators restore any video. Accepts personal access tokens with videos:write scope
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.RestoreVideoResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/videos/{id}/restore [post]
+func (v *VideoHandler) RestoreVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRe := v.videoService.RestoreVideo(ctx, claims.AccountID, claims.Role, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// RecordView godoc
+//
+//	@Summary		Record view of video
+//	@Tags			videos
+//	@Description	Count view of video towards trending, a viewer counts once per video every half hour. Accepts personal access tokens with videos:read scope
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.RecordViewResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/videos/{id}/views [post]
+func (v *VideoHandler) RecordView(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRe := v.videoService.RecordView(ctx, claims.AccountID, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// viewerAccountID returns account of the caller on routes where logging in is optional, 0 for anonymous callers.
+func viewerAccountID(ctx *gin.Context) int64 {
+	claimsStr, _ := ctx.Get("claims")
+	claims, ok := claimsStr.(*utils.UserClaims)
+
+	if !ok {
+		return 0
+	}
+
+	return claims.AccountID
+}
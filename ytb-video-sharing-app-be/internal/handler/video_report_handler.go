@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/service"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VideoReportHandler struct {
+	videoReportService service.VideoReportService
+}
+
+func NewVideoReportHandler(videoReportService service.VideoReportService) *VideoReportHandler {
+	return &VideoReportHandler{
+		videoReportService: videoReportService,
+	}
+}
+
+// Report godoc
+//
+//	@Summary		Report video
+//	@Tags			videos
+//	@Description	Flag video as spam, inappropriate, broken, copyright or other. An account reports a video once, and only a few videos an hour
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id		path		int						true	"Video ID"
+//	@Param			request	body		dto.ReportVideoRequest	true	"Report payload"
+//	@Success		201		{object}	dto.VideoReportResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		404		{object}	dto.ResponseError
+//	@Failure		409		{object}	dto.ResponseError
+//	@Failure		429		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/videos/{id}/report [post]
+func (h *VideoReportHandler) Report(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	req, _ := ctx.Get("data")
+	data := req.(dto.ReportVideoRequest)
+
+	res, errRe := h.videoReportService.Report(ctx, claims.AccountID, videoID, &data)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, res)
+}
+
+// GetQueue godoc
+//
+//	@Summary		Get moderation queue
+//	@Tags			moderation
+//	@Description	Get videos with open reports grouped by video, the most reported first
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			limit	query		int	true	"Limit number of records returned"
+//	@Param			page	query		int	true	"page"
+//	@Success		200		{object}	dto.ListReportedVideosResponseDocs
+//	@Failure		400		{object}	dto.ResponseError
+//	@Failure		403		{object}	dto.ResponseError
+//	@Failure		500		{object}	dto.ResponseError
+//	@Router			/moderation/reports [get]
+func (h *VideoReportHandler) GetQueue(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.Query("limit"))
+	if err != nil || limit <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid limit parameter")
+		return
+	}
+
+	page, err := strconv.Atoi(ctx.Query("page"))
+	if err != nil || page <= 0 {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "Invalid page parameter")
+		return
+	}
+
+	res, totalItems, totalPages, isNext, isPrevious, errRes := h.videoReportService.GetQueue(ctx, limit, page)
+	if errRes != nil {
+		utils.ErrorResponse(ctx, errRes.Code, errRes)
+		return
+	}
+
+	utils.PaginatedResponse(ctx, res, page, limit, totalPages, totalItems, isNext, isPrevious)
+}
+
+// DismissReport godoc
+//
+//	@Summary		Dismiss report
+//	@Tags			moderation
+//	@Description	Resolve report as groundless, it leaves the moderation queue
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Report ID"
+//	@Success		200	{object}	dto.VideoReportResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/moderation/reports/{id}/dismiss [post]
+func (h *VideoReportHandler) DismissReport(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	reportID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid report id")
+		return
+	}
+
+	res, errRe := h.videoReportService.DismissReport(ctx, claims.AccountID, reportID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// HideVideo godoc
+//
+//	@Summary		Hide video
+//	@Tags			moderation
+//	@Description	Hide video from everyone but its owner and resolve its open reports
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.HideVideoResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/moderation/videos/{id}/hide [post]
+func (h *VideoReportHandler) HideVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRe := h.videoReportService.HideVideo(ctx, claims.AccountID, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
+
+// UnhideVideo godoc
+//
+//	@Summary		Unhide video
+//	@Tags			moderation
+//	@Description	Make hidden video visible to everyone again
+//	@Accept			json
+//	@Produce		json
+//
+//	@Security		BearerAuth
+//
+//	@Param			id	path		int	true	"Video ID"
+//	@Success		200	{object}	dto.HideVideoResponseDocs
+//	@Failure		400	{object}	dto.ResponseError
+//	@Failure		403	{object}	dto.ResponseError
+//	@Failure		404	{object}	dto.ResponseError
+//	@Failure		409	{object}	dto.ResponseError
+//	@Failure		500	{object}	dto.ResponseError
+//	@Router			/moderation/videos/{id}/hide [delete]
+func (h *VideoReportHandler) UnhideVideo(ctx *gin.Context) {
+	claimsStr, _ := ctx.Get("claims")
+	claims := claimsStr.(*utils.UserClaims)
+
+	videoID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		utils.ErrorResponse(ctx, http.StatusBadRequest, "invalid video id")
+		return
+	}
+
+	res, errRe := h.videoReportService.UnhideVideo(ctx, claims.AccountID, videoID)
+
+	if errRe != nil {
+		utils.ErrorResponse(ctx, errRe.Code, errRe.Message)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, res)
+}
@@ -2,25 +2,76 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
 	"ytb-video-sharing-app-be/utils"
 )
 
 type JwtAuthenticationMiddleware struct {
-	KeyManager *utils.KeyManager
+	KeyManager                    *utils.KeyManager
+	AccountRepository             repository.AccountRepository
+	RevocationList                pkg.RevocationList
+	PersonalAccessTokenRepository repository.PersonalAccessTokenRepository
 }
 
-func NewJWTAuthenticationMiddleware(keyManager *utils.KeyManager) *JwtAuthenticationMiddleware {
+func NewJWTAuthenticationMiddleware(keyManager *utils.KeyManager, accountRepository repository.AccountRepository, revocationList pkg.RevocationList, personalAccessTokenRepository repository.PersonalAccessTokenRepository) *JwtAuthenticationMiddleware {
 	return &JwtAuthenticationMiddleware{
-		KeyManager: keyManager,
+		KeyManager:                    keyManager,
+		AccountRepository:             accountRepository,
+		RevocationList:                revocationList,
+		PersonalAccessTokenRepository: personalAccessTokenRepository,
 	}
 }
 
-func JWTAuthMiddleware(params *JwtAuthenticationMiddleware) gin.HandlerFunc {
+type authOptions struct {
+	requireVerifiedEmail bool
+	scope                string
+	optional             bool
+}
+
+// AuthOption customizes checks done by JWTAuthMiddleware.
+type AuthOption func(*authOptions)
+
+// WithVerifiedEmail rejects accounts that have not verified their email yet.
+func WithVerifiedEmail() AuthOption {
+	return func(o *authOptions) {
+		o.requireVerifiedEmail = true
+	}
+}
+
+// WithScope accepts personal access tokens granted scope, without it only access tokens of a login session are accepted.
+func WithScope(scope string) AuthOption {
+	return func(o *authOptions) {
+		o.scope = scope
+	}
+}
+
+// WithOptionalAuth lets requests without authorization header through as anonymous, no claims are set for them.
+// A token that is sent must still be valid.
+func WithOptionalAuth() AuthOption {
+	return func(o *authOptions) {
+		o.optional = true
+	}
+}
+
+func JWTAuthMiddleware(params *JwtAuthenticationMiddleware, opts ...AuthOption) gin.HandlerFunc {
+	options := &authOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" && options.optional {
+			ctx.Next()
+			return
+		}
+
 		if authHeader == "" {
 			utils.ErrorResponse(ctx, http.StatusUnauthorized, &dto.ErrorResponse{Message: "Missing authorization header", Code: http.StatusUnauthorized})
 			ctx.Abort()
@@ -34,8 +85,14 @@ func JWTAuthMiddleware(params *JwtAuthenticationMiddleware) gin.HandlerFunc {
 			return
 		}
 
-		// Validate token
-		claims, errResp := utils.ValidateToken(parts[1], params.KeyManager)
+		var claims *utils.UserClaims
+		var errResp *dto.ErrorResponse
+
+		if utils.IsPersonalAccessToken(parts[1]) {
+			claims, errResp = authenticatePersonalAccessToken(ctx, params, parts[1])
+		} else {
+			claims, errResp = authenticateAccessToken(ctx, params, parts[1])
+		}
 
 		if errResp != nil {
 			utils.ErrorResponse(ctx, errResp.Code, dto.ErrorResponse{Message: errResp.Message, Code: errResp.Code})
@@ -43,12 +100,90 @@ func JWTAuthMiddleware(params *JwtAuthenticationMiddleware) gin.HandlerFunc {
 			return
 		}
 
+		if claims.TokenUse == utils.TokenUsePersonalAccess && (options.scope == "" || !claims.HasScope(options.scope)) {
+			utils.ErrorResponse(ctx, http.StatusForbidden, dto.ErrorResponse{Message: "Token does not have the required scope", Code: http.StatusForbidden})
+			ctx.Abort()
+			return
+		}
+
+		// account is loaded on every request, so suspension, deletion and role changes apply before tokens expire
+		account := params.AccountRepository.GetAccountByID(ctx, claims.AccountID)
+
+		if account == nil {
+			utils.ErrorResponse(ctx, http.StatusUnauthorized, dto.ErrorResponse{Message: "Account is not found", Code: http.StatusUnauthorized})
+			ctx.Abort()
+			return
+		}
+
+		if account.IsSuspended(time.Now()) {
+			utils.ErrorResponse(ctx, http.StatusForbidden, dto.ErrorResponse{Message: utils.ACCOUNT_SUSPENDED, Code: http.StatusForbidden})
+			ctx.Abort()
+			return
+		}
+
+		if options.requireVerifiedEmail && !account.EmailVerifiedAt.Valid {
+			utils.ErrorResponse(ctx, http.StatusForbidden, dto.ErrorResponse{Message: "Email is not verified", Code: http.StatusForbidden})
+			ctx.Abort()
+			return
+		}
+
+		claims.Email = account.Email
+		claims.Role = account.Role
+
 		ctx.Set("claims", claims)
 
 		ctx.Next()
 	}
 }
 
+// authenticateAccessToken validates JWT access token and checks it against revocation list.
+func authenticateAccessToken(ctx *gin.Context, params *JwtAuthenticationMiddleware, token string) (*utils.UserClaims, *dto.ErrorResponse) {
+	claims, errResp := utils.ValidateToken(token, params.KeyManager, utils.TokenUseAccess)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	revoked, err := params.RevocationList.IsRevoked(ctx, claims.RevocationKeys()...)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Message: utils.INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
+	}
+
+	if revoked {
+		return nil, &dto.ErrorResponse{Message: "Token has been revoked", Code: http.StatusUnauthorized}
+	}
+
+	return claims, nil
+}
+
+// authenticatePersonalAccessToken looks personal access token up by its hash and builds claims limited to its scopes.
+func authenticatePersonalAccessToken(ctx *gin.Context, params *JwtAuthenticationMiddleware, token string) (*utils.UserClaims, *dto.ErrorResponse) {
+	personalAccessToken := params.PersonalAccessTokenRepository.GetByHash(ctx, utils.HashToken(token))
+	now := time.Now()
+
+	if personalAccessToken == nil || personalAccessToken.RevokedAt.Valid {
+		return nil, &dto.ErrorResponse{Message: "Token is invalid or has been revoked", Code: http.StatusUnauthorized}
+	}
+
+	if personalAccessToken.ExpiresAt.Valid && !personalAccessToken.ExpiresAt.Time.After(now) {
+		return nil, &dto.ErrorResponse{Message: "Token has expired", Code: http.StatusUnauthorized}
+	}
+
+	// last use is only informative, the request goes on when it can not be saved
+	if err := params.PersonalAccessTokenRepository.MarkUsed(ctx, personalAccessToken.ID, now); err != nil {
+		log.Println("error when saving last use of personal access token: ", err)
+	}
+
+	// email and role are filled from the account by JWTAuthMiddleware
+	return &utils.UserClaims{
+		AccountID: personalAccessToken.AccountID,
+		TokenUse:  utils.TokenUsePersonalAccess,
+		SessionID: utils.PersonalAccessTokenSessionID(personalAccessToken.ID),
+		Scopes:    personalAccessToken.Scopes,
+	}, nil
+}
+
 func JWTRefreshTokenMiddleware(params *JwtAuthenticationMiddleware) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("X-Authorization")
@@ -66,7 +201,7 @@ func JWTRefreshTokenMiddleware(params *JwtAuthenticationMiddleware) gin.HandlerF
 		}
 
 		// Validate token
-		claims, errResp := utils.ValidateToken(parts[1], params.KeyManager)
+		claims, errResp := utils.ValidateToken(parts[1], params.KeyManager, utils.TokenUseRefresh)
 
 		if errResp != nil {
 			utils.ErrorResponse(ctx, errResp.Code, dto.ErrorResponse{Message: errResp.Message, Code: errResp.Code})
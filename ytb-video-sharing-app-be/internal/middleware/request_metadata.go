@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"regexp"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestIDPattern is what a request id sent by client may look like, anything else is replaced.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestMetadata stores request id, client IP and user agent of request for audit events.
+// The request id sent in X-Request-ID is kept when it looks sane, a new one is generated otherwise,
+// and it is sent back in X-Request-ID either way.
+func RequestMetadata() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader("X-Request-ID")
+
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Header("X-Request-ID", requestID)
+		ctx.Set(pkg.RequestMetadataKey, &pkg.RequestMetadata{
+			RequestID: requestID,
+			IPAddress: ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		})
+
+		ctx.Next()
+	}
+}
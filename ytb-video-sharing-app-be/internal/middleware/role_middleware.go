@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole rejects accounts below role, it must run after JWTAuthMiddleware.
+func RequireRole(role entities.Role) gin.HandlerFunc {
+	return requireRole(func(current entities.Role) bool {
+		return current.AtLeast(role)
+	})
+}
+
+// RequirePermission rejects accounts whose role is not granted permission, it must run after JWTAuthMiddleware.
+func RequirePermission(permission utils.Permission) gin.HandlerFunc {
+	return requireRole(func(current entities.Role) bool {
+		return utils.HasPermission(current, permission)
+	})
+}
+
+// requireRole checks role of claims, JWTAuthMiddleware has set it to the role account has now,
+// so a demoted account loses its powers before its access token expires.
+func requireRole(allowed func(entities.Role) bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claimsStr, _ := ctx.Get("claims")
+		claims, ok := claimsStr.(*utils.UserClaims)
+
+		if !ok || !allowed(claims.Role) {
+			utils.ErrorResponse(ctx, http.StatusForbidden, dto.ErrorResponse{Message: "You do not have permission to perform this action", Code: http.StatusForbidden})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
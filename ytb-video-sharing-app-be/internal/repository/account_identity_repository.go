@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type AccountIdentityRepository interface {
+	// GetIdentity get identity by provider and user id at the provider.
+	GetIdentity(ctx context.Context, provider string, subject string) *entities.AccountIdentity
+
+	// Save link identity to account within transaction.
+	Save(ctx context.Context, tx pkg.Tx, payload *entities.AccountIdentity) error
+}
+
+type accountIdentityRepository struct {
+	db pkg.Database
+}
+
+func NewAccountIdentityRepository(db pkg.Database) AccountIdentityRepository {
+	return &accountIdentityRepository{
+		db: db,
+	}
+}
+
+// GetIdentity implements AccountIdentityRepository.
+func (a *accountIdentityRepository) GetIdentity(ctx context.Context, provider string, subject string) *entities.AccountIdentity {
+	query := `SELECT id, account_id, provider, subject, email, created_at
+		FROM account_identities WHERE provider = ? AND subject = ?`
+
+	identity := new(entities.AccountIdentity)
+	row := a.db.QueryRow(ctx, query, provider, subject)
+
+	if err := row.Scan(&identity.ID, &identity.AccountID, &identity.Provider,
+		&identity.Subject, &identity.Email, &identity.CreatedAt); err != nil {
+		return nil
+	}
+
+	return identity
+}
+
+// Save implements AccountIdentityRepository.
+func (a *accountIdentityRepository) Save(ctx context.Context, tx pkg.Tx, payload *entities.AccountIdentity) error {
+	query := `INSERT INTO account_identities (account_id, provider, subject, email)
+		VALUES(?, ?, ?, ?)`
+
+	return tx.Exec(ctx, query, payload.AccountID, payload.Provider, payload.Subject, payload.Email)
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type accountIdentityConfig struct {
+	testConfig
+	repo AccountIdentityRepository
+}
+
+func SetupAccountIdentityConfig(t *testing.T) *accountIdentityConfig {
+	testConf := SetupTest(t)
+
+	return &accountIdentityConfig{
+		testConfig: *testConf,
+		repo:       NewAccountIdentityRepository(testConf.db),
+	}
+}
+
+func TestGetAccountIdentity(t *testing.T) {
+	t.Run("Should return identity if found", func(t *testing.T) {
+		cfg := SetupAccountIdentityConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expected := &entities.AccountIdentity{
+			ID:        1,
+			AccountID: 2,
+			Provider:  "google",
+			Subject:   "subject",
+			Email:     "test@example.com",
+			CreatedAt: time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "google", "subject").Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*int64) = expected.ID
+				*args[1].(*int64) = expected.AccountID
+				*args[2].(*string) = expected.Provider
+				*args[3].(*string) = expected.Subject
+				*args[4].(*string) = expected.Email
+				*args[5].(*time.Time) = expected.CreatedAt
+				return nil
+			})
+
+		rs := cfg.repo.GetIdentity(ctx, "google", "subject")
+
+		assert.Equal(t, expected, rs)
+	})
+
+	t.Run("Should return nil if identity is not linked", func(t *testing.T) {
+		cfg := SetupAccountIdentityConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "google", "subject").Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(sql.ErrNoRows)
+
+		rs := cfg.repo.GetIdentity(ctx, "google", "subject")
+
+		assert.Nil(t, rs)
+	})
+}
+
+func TestSaveAccountIdentity(t *testing.T) {
+	t.Run("Should link identity to account", func(t *testing.T) {
+		cfg := SetupAccountIdentityConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		identity := &entities.AccountIdentity{
+			AccountID: 2,
+			Provider:  "google",
+			Subject:   "subject",
+			Email:     "test@example.com",
+		}
+
+		cfg.tx.EXPECT().
+			Exec(ctx, gomock.Any(), identity.AccountID, identity.Provider, identity.Subject, identity.Email).
+			Return(nil)
+
+		err := cfg.repo.Save(ctx, cfg.tx, identity)
+
+		assert.NoError(t, err)
+	})
+}
@@ -104,3 +104,34 @@ func TestCreateAccountPassword(t *testing.T) {
 		assert.Equal(t, expectedErr, err)
 	})
 }
+
+func TestUpdateAccountPassword(t *testing.T) {
+	t.Run("Should update account password", func(t *testing.T) {
+		cfg := SetupAccountPasswordConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		payload := &entities.AccountPassword{ID: 1, Password: "new_hashed_password"}
+
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), payload.Password, payload.ID).Return(nil)
+
+		err := cfg.repo.UpdateAccountPassword(ctx, cfg.tx, payload)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if db execution fails", func(t *testing.T) {
+		cfg := SetupAccountPasswordConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(expectedErr)
+
+		err := cfg.repo.UpdateAccountPassword(ctx, cfg.tx, &entities.AccountPassword{ID: 1, Password: "new_hashed_password"})
+
+		assert.Error(t, err)
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -12,6 +12,9 @@ type AccountPasswordRepository interface {
 
 	// Create account password.
 	CreateAccountPassword(ctx context.Context, tx pkg.Tx, payload *entities.AccountPassword) error
+
+	// Update account password within transaction.
+	UpdateAccountPassword(ctx context.Context, tx pkg.Tx, payload *entities.AccountPassword) error
 }
 
 type accountPasswordRepository struct {
@@ -49,3 +52,10 @@ func (a *accountPasswordRepository) GetAccountPasswordByID(ctx context.Context,
 
 	return accountPassword
 }
+
+// UpdateAccountPassword implements AccountPasswordRepository.
+func (a *accountPasswordRepository) UpdateAccountPassword(ctx context.Context, tx pkg.Tx, payload *entities.AccountPassword) error {
+	query := `UPDATE account_password SET password = ? WHERE id = ?`
+
+	return tx.Exec(ctx, query, payload.Password, payload.ID)
+}
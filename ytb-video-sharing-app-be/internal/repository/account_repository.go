@@ -2,27 +2,82 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"strings"
+	"time"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/pkg"
 )
 
 type AccountRepository interface {
-	// Get account by email.
+	// Get account by email, soft deleted accounts are left out like in every other read.
 	GetAccountByEmail(ctx context.Context, email string) *entities.Account
 
 	// Get account by email.
 	GetAccountByID(ctx context.Context, id int64) *entities.Account
 
+	// Update fullname and bio of account.
+	UpdateProfile(ctx context.Context, id int64, fullName string, bio string) error
+
+	// Update avatar url of account.
+	UpdateAvatarURL(ctx context.Context, id int64, avatarURL string) error
+
+	// Mark email of account as verified, the email must still match.
+	MarkEmailVerified(ctx context.Context, id int64, email string) error
+
+	// Get number of shared videos, received upvotes and followers of account.
+	GetAccountStats(ctx context.Context, id int64) (*entities.AccountStats, error)
+
 	// Get account by email within trasaction.
 	GetAccountByEmailX(ctx context.Context, tx pkg.Tx, email string) *entities.Account
 
 	// Create new account.
 	CreateAccount(ctx context.Context, tx pkg.Tx, payload *entities.Account) error
 
+	// Update role of account.
+	UpdateRole(ctx context.Context, id int64, role entities.Role) error
+
+	// Make account of verified email admin, only while there is no admin yet.
+	PromoteFirstAdmin(ctx context.Context, email string) error
+
+	// Search accounts by email or fullname, every account when search is empty, newest first.
+	// Only soft deleted accounts are searched when deleted is true.
+	SearchAccounts(ctx context.Context, search string, deleted bool, page, limit int) ([]*entities.Account, int, error)
+
+	// Suspend account until the given time, until lifted when it is null.
+	Suspend(ctx context.Context, id int64, until sql.NullTime, reason string) error
+
+	// Lift suspension of account.
+	Unsuspend(ctx context.Context, id int64) error
+
+	// Delete account for good, its videos and everything else owned by it are deleted by cascade.
+	DeleteAccount(ctx context.Context, id int64) error
+
+	// Tell whether email belongs to an account, soft deleted ones included.
+	IsEmailTaken(ctx context.Context, email string) (bool, error)
+
+	// Get soft deleted account by id.
+	GetDeletedAccountByID(ctx context.Context, id int64) *entities.Account
+
+	// Get soft deleted account by email.
+	GetDeletedAccountByEmail(ctx context.Context, email string) *entities.Account
+
+	// Soft delete account, ErrNoRowsAffected when it is deleted already.
+	SoftDeleteAccount(ctx context.Context, id int64, deletedBy int64, at time.Time) error
+
+	// Restore soft deleted account, ErrNoRowsAffected when it is not deleted.
+	RestoreAccount(ctx context.Context, id int64) error
+
+	// Delete for good accounts soft deleted before before, returning how many were deleted.
+	PurgeDeletedAccounts(ctx context.Context, before time.Time) (int64, error)
+
 	// Begin transaction.
 	BeginTransaction(ctx context.Context) (pkg.Tx, error)
 }
 
+// accountColumns is the list of columns scanned by scanAccount.
+const accountColumns = "id, email, fullname, avatarURL, bio, created_at, email_verified_at, role, suspended_at, suspended_until, suspension_reason, deleted_at, deleted_by"
+
 type accountRepository struct {
 	db pkg.Database
 }
@@ -35,11 +90,11 @@ func NewAccountRepository(db pkg.Database) AccountRepository {
 
 // CreateAccount implements AccountRepository.
 func (a *accountRepository) CreateAccount(ctx context.Context, tx pkg.Tx, payload *entities.Account) error {
-	query := `INSERT INTO accounts(email, fullname, avatarURL)
-		VALUES(?, ?, ?)			
+	query := `INSERT INTO accounts(email, fullname, avatarURL, email_verified_at)
+		VALUES(?, ?, ?, ?)			
 	`
 
-	if err := tx.Exec(ctx, query, payload.Email, payload.FullName, payload.AvatarURL); err != nil {
+	if err := tx.Exec(ctx, query, payload.Email, payload.FullName, payload.AvatarURL, payload.EmailVerifiedAt); err != nil {
 		return err
 	}
 
@@ -48,12 +103,11 @@ func (a *accountRepository) CreateAccount(ctx context.Context, tx pkg.Tx, payloa
 
 // GetAccountByEmail implements AccountRepository.
 func (a *accountRepository) GetAccountByEmail(ctx context.Context, email string) *entities.Account {
-	query := "SELECT * FROM accounts WHERE email = ?"
+	query := "SELECT " + accountColumns + " FROM accounts WHERE email = ? AND deleted_at IS NULL"
 
 	res := a.db.QueryRow(ctx, query, email)
 
-	account := new(entities.Account)
-	err := res.Scan(&account.ID, &account.Email, &account.FullName, &account.AvatarURL)
+	account, err := scanAccount(res)
 
 	if err != nil {
 		return nil
@@ -64,12 +118,11 @@ func (a *accountRepository) GetAccountByEmail(ctx context.Context, email string)
 
 // GetAccountByEmailX implements AccountRepository.
 func (a *accountRepository) GetAccountByEmailX(ctx context.Context, tx pkg.Tx, email string) *entities.Account {
-	query := "SELECT * FROM accounts WHERE email = ?"
+	query := "SELECT " + accountColumns + " FROM accounts WHERE email = ? AND deleted_at IS NULL"
 
 	res := tx.QueryRow(ctx, query, email)
 
-	account := new(entities.Account)
-	err := res.Scan(&account.ID, &account.Email, &account.FullName, &account.AvatarURL)
+	account, err := scanAccount(res)
 
 	if err != nil {
 		return nil
@@ -80,12 +133,163 @@ func (a *accountRepository) GetAccountByEmailX(ctx context.Context, tx pkg.Tx, e
 
 // GetAccountByID implements AccountRepository.
 func (a *accountRepository) GetAccountByID(ctx context.Context, id int64) *entities.Account {
-	query := "SELECT * FROM accounts WHERE id = ?"
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = ? AND deleted_at IS NULL"
 
 	res := a.db.QueryRow(ctx, query, id)
 
-	account := new(entities.Account)
-	err := res.Scan(&account.ID, &account.Email, &account.FullName, &account.AvatarURL)
+	account, err := scanAccount(res)
+
+	if err != nil {
+		return nil
+	}
+
+	return account
+}
+
+// UpdateProfile implements AccountRepository.
+func (a *accountRepository) UpdateProfile(ctx context.Context, id int64, fullName string, bio string) error {
+	query := `UPDATE accounts SET fullname = ?, bio = ? WHERE id = ?`
+
+	// mysql reports no affected rows when values are unchanged, so Exec cannot be used here
+	_, err := a.db.ExecWithResult(ctx, query, fullName, bio, id)
+
+	return err
+}
+
+// UpdateAvatarURL implements AccountRepository.
+func (a *accountRepository) UpdateAvatarURL(ctx context.Context, id int64, avatarURL string) error {
+	query := `UPDATE accounts SET avatarURL = ? WHERE id = ?`
+
+	_, err := a.db.ExecWithResult(ctx, query, avatarURL, id)
+
+	return err
+}
+
+// MarkEmailVerified implements AccountRepository.
+func (a *accountRepository) MarkEmailVerified(ctx context.Context, id int64, email string) error {
+	query := `UPDATE accounts SET email_verified_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND email = ? AND email_verified_at IS NULL`
+
+	return a.db.Exec(ctx, query, id, email)
+}
+
+// GetAccountStats implements AccountRepository.
+func (a *accountRepository) GetAccountStats(ctx context.Context, id int64) (*entities.AccountStats, error) {
+	query := `SELECT
+		(SELECT COUNT(*) FROM videos WHERE account_id = ? AND deleted_at IS NULL),
+		(SELECT COALESCE(SUM(upvote), 0) FROM videos WHERE account_id = ? AND deleted_at IS NULL),
+		(SELECT COUNT(*) FROM follows WHERE following_id = ?)`
+
+	stats := new(entities.AccountStats)
+	if err := a.db.QueryRow(ctx, query, id, id, id).
+		Scan(&stats.VideosShared, &stats.UpvotesReceived, &stats.FollowerCount); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// UpdateRole implements AccountRepository.
+func (a *accountRepository) UpdateRole(ctx context.Context, id int64, role entities.Role) error {
+	query := `UPDATE accounts SET role = ? WHERE id = ?`
+
+	return a.db.Exec(ctx, query, role, id)
+}
+
+// PromoteFirstAdmin implements AccountRepository.
+func (a *accountRepository) PromoteFirstAdmin(ctx context.Context, email string) error {
+	// mysql can not read the updated table in a subquery, the derived table is materialized first
+	query := `UPDATE accounts SET role = ?
+		WHERE email = ? AND email_verified_at IS NOT NULL AND deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM (SELECT id FROM accounts WHERE role = ?) AS admins)`
+
+	return a.db.Exec(ctx, query, entities.RoleAdmin, email, entities.RoleAdmin)
+}
+
+// SearchAccounts implements AccountRepository.
+func (a *accountRepository) SearchAccounts(ctx context.Context, search string, deleted bool, page, limit int) ([]*entities.Account, int, error) {
+	where := " WHERE deleted_at IS NULL"
+	if deleted {
+		where = " WHERE deleted_at IS NOT NULL"
+	}
+
+	args := []any{}
+
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		where += " AND (email LIKE ? OR fullname LIKE ?)"
+		args = append(args, pattern, pattern)
+	}
+
+	var totalItems int
+	if err := a.db.QueryRow(ctx, "SELECT COUNT(*) FROM accounts"+where, args...).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + accountColumns + " FROM accounts" + where + " ORDER BY id DESC LIMIT ? OFFSET ?"
+
+	rows, err := a.db.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var accounts []*entities.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, totalItems, nil
+}
+
+// Suspend implements AccountRepository.
+func (a *accountRepository) Suspend(ctx context.Context, id int64, until sql.NullTime, reason string) error {
+	query := `UPDATE accounts SET suspended_at = CURRENT_TIMESTAMP, suspended_until = ?, suspension_reason = ?
+		WHERE id = ?`
+
+	return a.db.Exec(ctx, query, until, reason, id)
+}
+
+// Unsuspend implements AccountRepository.
+func (a *accountRepository) Unsuspend(ctx context.Context, id int64) error {
+	query := `UPDATE accounts SET suspended_at = NULL, suspended_until = NULL, suspension_reason = ''
+		WHERE id = ? AND suspended_at IS NOT NULL`
+
+	return a.db.Exec(ctx, query, id)
+}
+
+// DeleteAccount implements AccountRepository.
+func (a *accountRepository) DeleteAccount(ctx context.Context, id int64) error {
+	query := `DELETE FROM accounts WHERE id = ?`
+
+	return a.db.Exec(ctx, query, id)
+}
+
+// IsEmailTaken implements AccountRepository.
+func (a *accountRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM accounts WHERE email = ?)`
+
+	var taken bool
+	if err := a.db.QueryRow(ctx, query, email).Scan(&taken); err != nil {
+		return false, err
+	}
+
+	return taken, nil
+}
+
+// GetDeletedAccountByID implements AccountRepository.
+func (a *accountRepository) GetDeletedAccountByID(ctx context.Context, id int64) *entities.Account {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE id = ? AND deleted_at IS NOT NULL"
+
+	account, err := scanAccount(a.db.QueryRow(ctx, query, id))
 
 	if err != nil {
 		return nil
@@ -94,7 +298,65 @@ func (a *accountRepository) GetAccountByID(ctx context.Context, id int64) *entit
 	return account
 }
 
+// GetDeletedAccountByEmail implements AccountRepository.
+func (a *accountRepository) GetDeletedAccountByEmail(ctx context.Context, email string) *entities.Account {
+	query := "SELECT " + accountColumns + " FROM accounts WHERE email = ? AND deleted_at IS NOT NULL"
+
+	account, err := scanAccount(a.db.QueryRow(ctx, query, email))
+
+	if err != nil {
+		return nil
+	}
+
+	return account
+}
+
+// SoftDeleteAccount implements AccountRepository.
+func (a *accountRepository) SoftDeleteAccount(ctx context.Context, id int64, deletedBy int64, at time.Time) error {
+	query := `UPDATE accounts SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL`
+
+	return a.db.Exec(ctx, query, at, deletedBy, id)
+}
+
+// RestoreAccount implements AccountRepository.
+func (a *accountRepository) RestoreAccount(ctx context.Context, id int64) error {
+	query := `UPDATE accounts SET deleted_at = NULL, deleted_by = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+
+	return a.db.Exec(ctx, query, id)
+}
+
+// PurgeDeletedAccounts implements AccountRepository.
+func (a *accountRepository) PurgeDeletedAccounts(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM accounts WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	rs, err := a.db.ExecWithResult(ctx, query, before)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rs.RowsAffected()
+}
+
 // BeginTransaction implements AccountRepository.
 func (a *accountRepository) BeginTransaction(ctx context.Context) (pkg.Tx, error) {
 	return a.db.Begin(ctx)
 }
+
+func scanAccount(row pkg.Row) (*entities.Account, error) {
+	account := new(entities.Account)
+
+	if err := row.Scan(&account.ID, &account.Email, &account.FullName, &account.AvatarURL, &account.Bio, &account.CreatedAt, &account.EmailVerifiedAt, &account.Role,
+		&account.SuspendedAt, &account.SuspendedUntil, &account.SuspensionReason, &account.DeletedAt, &account.DeletedBy); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// likeEscaper escapes wildcards of LIKE pattern, backslash is the default escape character of mysql.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
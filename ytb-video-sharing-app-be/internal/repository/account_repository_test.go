@@ -7,7 +7,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"testing"
+	"time"
 	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
 )
 
 type accountConfig struct {
@@ -24,6 +26,32 @@ func SetupAccountConfig(t *testing.T) *accountConfig {
 	}
 }
 
+// accountScanArgs matches one argument per column in accountColumns.
+func accountScanArgs() []any {
+	args := make([]any, 13)
+	for i := range args {
+		args[i] = gomock.Any()
+	}
+
+	return args
+}
+
+func fillAccount(args []interface{}, account *entities.Account) {
+	*args[0].(*int64) = account.ID
+	*args[1].(*string) = account.Email
+	*args[2].(*string) = account.FullName
+	*args[3].(*string) = account.AvatarURL
+	*args[4].(*string) = account.Bio
+	*args[5].(*time.Time) = account.CreatedAt
+	*args[6].(*sql.NullTime) = account.EmailVerifiedAt
+	*args[7].(*entities.Role) = account.Role
+	*args[8].(*sql.NullTime) = account.SuspendedAt
+	*args[9].(*sql.NullTime) = account.SuspendedUntil
+	*args[10].(*string) = account.SuspensionReason
+	*args[11].(*sql.NullTime) = account.DeletedAt
+	*args[12].(*sql.NullInt64) = account.DeletedBy
+}
+
 func TestCreateAccount(t *testing.T) {
 	t.Run("Should create an account successfully", func(t *testing.T) {
 		cfg := SetupAccountConfig(t)
@@ -37,7 +65,7 @@ func TestCreateAccount(t *testing.T) {
 		}
 
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), account.Email, account.FullName, account.AvatarURL).
+			Exec(ctx, gomock.Any(), account.Email, account.FullName, account.AvatarURL, account.EmailVerifiedAt).
 			Return(nil)
 
 		err := cfg.repo.CreateAccount(ctx, cfg.tx, account)
@@ -57,7 +85,7 @@ func TestCreateAccount(t *testing.T) {
 
 		expectedErr := errors.New("db execution failed")
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), account.Email, account.FullName, account.AvatarURL).
+			Exec(ctx, gomock.Any(), account.Email, account.FullName, account.AvatarURL, account.EmailVerifiedAt).
 			Return(expectedErr)
 
 		err := cfg.repo.CreateAccount(ctx, cfg.tx, account)
@@ -77,6 +105,8 @@ func TestGetAccountByEmail(t *testing.T) {
 			Email:     "test@example.com",
 			FullName:  "Test User",
 			AvatarURL: "https://avatar.url",
+			Bio:       "Hello",
+			CreatedAt: time.Now(),
 		}
 
 		cfg.db.EXPECT().
@@ -84,12 +114,9 @@ func TestGetAccountByEmail(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			DoAndReturn(func(args ...interface{}) error {
-				*args[0].(*int64) = expectedAccount.ID
-				*args[1].(*string) = expectedAccount.Email
-				*args[2].(*string) = expectedAccount.FullName
-				*args[3].(*string) = expectedAccount.AvatarURL
+				fillAccount(args, expectedAccount)
 				return nil
 			})
 
@@ -108,7 +135,7 @@ func TestGetAccountByEmail(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			Return(sql.ErrNoRows)
 
 		account := cfg.repo.GetAccountByEmail(ctx, "test@example.com")
@@ -123,7 +150,7 @@ func TestGetAccountByEmail(t *testing.T) {
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any()).Return(cfg.row)
 
 		err := errors.New("scan error")
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).Return(err)
 
 		result := cfg.repo.GetAccountByID(ctx, 1)
 
@@ -149,12 +176,9 @@ func TestGetAccountByEmailX(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			DoAndReturn(func(args ...interface{}) error {
-				*args[0].(*int64) = expectedAccount.ID
-				*args[1].(*string) = expectedAccount.Email
-				*args[2].(*string) = expectedAccount.FullName
-				*args[3].(*string) = expectedAccount.AvatarURL
+				fillAccount(args, expectedAccount)
 				return nil
 			})
 
@@ -173,7 +197,7 @@ func TestGetAccountByEmailX(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			Return(sql.ErrNoRows)
 
 		account := cfg.repo.GetAccountByEmailX(ctx, cfg.tx, "test@example.com")
@@ -188,7 +212,7 @@ func TestGetAccountByEmailX(t *testing.T) {
 		cfg.tx.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any()).Return(cfg.row)
 
 		err := errors.New("scan error")
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).Return(err)
 
 		result := cfg.repo.GetAccountByEmailX(ctx, cfg.tx, "test@example.com")
 
@@ -207,6 +231,9 @@ func TestGetAccountByID(t *testing.T) {
 			Email:     "test@example.com",
 			FullName:  "Test User",
 			AvatarURL: "https://avatar.url",
+			Bio:       "Hello",
+			CreatedAt: time.Now(),
+			Role:      entities.RoleModerator,
 		}
 
 		cfg.db.EXPECT().
@@ -214,12 +241,9 @@ func TestGetAccountByID(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			DoAndReturn(func(args ...interface{}) error {
-				*args[0].(*int64) = expectedAccount.ID
-				*args[1].(*string) = expectedAccount.Email
-				*args[2].(*string) = expectedAccount.FullName
-				*args[3].(*string) = expectedAccount.AvatarURL
+				fillAccount(args, expectedAccount)
 				return nil
 			})
 
@@ -238,7 +262,7 @@ func TestGetAccountByID(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(accountScanArgs()...).
 			Return(sql.ErrNoRows)
 
 		account := cfg.repo.GetAccountByID(ctx, 1)
@@ -253,7 +277,7 @@ func TestGetAccountByID(t *testing.T) {
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any()).Return(cfg.row)
 
 		err := errors.New("scan error")
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).Return(err)
 
 		result := cfg.repo.GetAccountByID(ctx, 1)
 
@@ -261,6 +285,486 @@ func TestGetAccountByID(t *testing.T) {
 	})
 }
 
+func TestUpdateProfile(t *testing.T) {
+	t.Run("Should update fullname and bio", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "New Name", "New bio", int64(1)).
+			Return(&MockSQLResult{RowAffected: 1}, nil)
+
+		err := cfg.repo.UpdateProfile(ctx, 1, "New Name", "New bio")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error when DB execution fails", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db execution failed")
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, expectedErr)
+
+		err := cfg.repo.UpdateProfile(ctx, 1, "New Name", "New bio")
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestUpdateAvatarURL(t *testing.T) {
+	t.Run("Should update avatar url", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "http://localhost:3000/static/avatars/1.png", int64(1)).
+			Return(&MockSQLResult{RowAffected: 1}, nil)
+
+		err := cfg.repo.UpdateAvatarURL(ctx, 1, "http://localhost:3000/static/avatars/1.png")
+		assert.NoError(t, err)
+	})
+}
+
+func TestMarkEmailVerified(t *testing.T) {
+	t.Run("Should mark email as verified", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), int64(1), "test@example.com").
+			Return(nil)
+
+		err := cfg.repo.MarkEmailVerified(ctx, 1, "test@example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if already verified or email changed", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), int64(1), "test@example.com").
+			Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.MarkEmailVerified(ctx, 1, "test@example.com")
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestUpdateRole(t *testing.T) {
+	t.Run("Should update role of account", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), entities.RoleModerator, int64(1)).
+			Return(nil)
+
+		err := cfg.repo.UpdateRole(ctx, 1, entities.RoleModerator)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if account is not found", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), entities.RoleModerator, int64(1)).
+			Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.UpdateRole(ctx, 1, entities.RoleModerator)
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestPromoteFirstAdmin(t *testing.T) {
+	t.Run("Should promote account of email", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), entities.RoleAdmin, "admin@example.com", entities.RoleAdmin).
+			Return(nil)
+
+		err := cfg.repo.PromoteFirstAdmin(ctx, "admin@example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return ErrNoRowsAffected if there is an admin already", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), entities.RoleAdmin, "admin@example.com", entities.RoleAdmin).
+			Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.PromoteFirstAdmin(ctx, "admin@example.com")
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestSearchAccounts(t *testing.T) {
+	t.Run("Should search accounts by escaped pattern", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedAccounts := []*entities.Account{
+			{ID: 2, Email: "spam_bot@example.com", FullName: "Spam"},
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "%spam\\_bot%", "%spam\\_bot%").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), "%spam\\_bot%", "%spam\\_bot%", 10, 10).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(1)
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(accountScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAccount(args, expectedAccounts[0])
+			return nil
+		})
+		cfg.rows.EXPECT().Close().Times(1)
+
+		accounts, totalItems, err := cfg.repo.SearchAccounts(ctx, "spam_bot", false, 2, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, totalItems)
+		assert.Equal(t, expectedAccounts, accounts)
+	})
+
+	t.Run("Should list every account if search is empty", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(nil)
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Close().Times(1)
+
+		accounts, _, err := cfg.repo.SearchAccounts(ctx, "", false, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, accounts)
+	})
+
+	t.Run("Should only list soft deleted accounts if deleted is true", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Regex("deleted_at IS NOT NULL")).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(nil)
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("deleted_at IS NOT NULL"), 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Close().Times(1)
+
+		accounts, _, err := cfg.repo.SearchAccounts(ctx, "", true, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, accounts)
+	})
+
+	t.Run("Should return error if count fails", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		accounts, _, err := cfg.repo.SearchAccounts(ctx, "", false, 1, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, accounts)
+	})
+}
+
+func TestSuspend(t *testing.T) {
+	t.Run("Should suspend account until given time", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		until := sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true}
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), until, "spam", int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.Suspend(ctx, 1, until, "spam"))
+	})
+
+	t.Run("Should return error if account is not found", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), sql.NullTime{}, "spam", int64(1)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Suspend(ctx, 1, sql.NullTime{}, "spam"), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestUnsuspend(t *testing.T) {
+	t.Run("Should lift suspension", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.Unsuspend(ctx, 1))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if account is not suspended", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Unsuspend(ctx, 1), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestDeleteAccount(t *testing.T) {
+	t.Run("Should delete account", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.DeleteAccount(ctx, 1))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if account is not found", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.DeleteAccount(ctx, 1), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestIsEmailTaken(t *testing.T) {
+	t.Run("Should tell email of soft deleted account is taken", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "deleted@example.com").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*bool) = true
+			return nil
+		})
+
+		taken, err := cfg.repo.IsEmailTaken(ctx, "deleted@example.com")
+
+		assert.NoError(t, err)
+		assert.True(t, taken)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "deleted@example.com").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		taken, err := cfg.repo.IsEmailTaken(ctx, "deleted@example.com")
+
+		assert.Equal(t, expectedErr, err)
+		assert.False(t, taken)
+	})
+}
+
+func TestGetDeletedAccount(t *testing.T) {
+	expectedAccount := &entities.Account{
+		ID:        2,
+		Email:     "deleted@example.com",
+		FullName:  "Deleted",
+		CreatedAt: time.Now(),
+		DeletedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		DeletedBy: sql.NullInt64{Int64: 2, Valid: true},
+	}
+
+	t.Run("Should return soft deleted account by id", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(2)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAccount(args, expectedAccount)
+			return nil
+		})
+
+		assert.Equal(t, expectedAccount, cfg.repo.GetDeletedAccountByID(ctx, 2))
+	})
+
+	t.Run("Should return soft deleted account by email", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "deleted@example.com").Return(cfg.row)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAccount(args, expectedAccount)
+			return nil
+		})
+
+		assert.Equal(t, expectedAccount, cfg.repo.GetDeletedAccountByEmail(ctx, "deleted@example.com"))
+	})
+
+	t.Run("Should return nil if account is not deleted", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(2)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(accountScanArgs()...).Return(sql.ErrNoRows)
+
+		assert.Nil(t, cfg.repo.GetDeletedAccountByID(ctx, 2))
+	})
+}
+
+func TestSoftDeleteAccount(t *testing.T) {
+	t.Run("Should soft delete account", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		at := time.Now()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), at, int64(1), int64(2)).Return(nil)
+
+		assert.NoError(t, cfg.repo.SoftDeleteAccount(ctx, 2, 1, at))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if account is deleted already", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), gomock.Any(), int64(1), int64(2)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.SoftDeleteAccount(ctx, 2, 1, time.Now()), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestRestoreAccount(t *testing.T) {
+	t.Run("Should restore soft deleted account", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(2)).Return(nil)
+
+		assert.NoError(t, cfg.repo.RestoreAccount(ctx, 2))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if account is not deleted", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(2)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.RestoreAccount(ctx, 2), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestPurgeDeletedAccounts(t *testing.T) {
+	t.Run("Should return how many accounts were purged", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		before := time.Now().Add(-720 * time.Hour)
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), before).Return(&MockSQLResult{RowAffected: 3}, nil)
+
+		purged, err := cfg.repo.PurgeDeletedAccounts(ctx, before)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("Should return error if delete fails", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		purged, err := cfg.repo.PurgeDeletedAccounts(ctx, time.Now())
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, purged)
+	})
+}
+
+func TestGetAccountStats(t *testing.T) {
+	t.Run("Should return stats of account", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedStats := &entities.AccountStats{VideosShared: 3, UpvotesReceived: 12, FollowerCount: 4}
+
+		cfg.db.EXPECT().
+			QueryRow(ctx, gomock.Any(), int64(1), int64(1), int64(1)).
+			Return(cfg.row)
+
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*int64) = expectedStats.VideosShared
+				*args[1].(*int64) = expectedStats.UpvotesReceived
+				*args[2].(*int64) = expectedStats.FollowerCount
+				return nil
+			})
+
+		stats, err := cfg.repo.GetAccountStats(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedStats, stats)
+	})
+
+	t.Run("Should return error if scan fails", func(t *testing.T) {
+		cfg := SetupAccountConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		err := errors.New("scan error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+
+		stats, errRes := cfg.repo.GetAccountStats(ctx, 1)
+
+		assert.Equal(t, err, errRes)
+		assert.Nil(t, stats)
+	})
+}
+
 func TestBeginTransaction(t *testing.T) {
 	t.Run("Should begin a transaction successfully", func(t *testing.T) {
 		cfg := SetupAccountConfig(t)
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// AuditEventRepository reads and appends audit events, they are never updated or deleted.
+type AuditEventRepository interface {
+	// Create append event.
+	Create(ctx context.Context, event *entities.AuditEvent) error
+
+	// List get events matching filter, newest first, and how many there are.
+	List(ctx context.Context, filter *entities.AuditEventFilter, page, limit int) ([]*entities.AuditEvent, int, error)
+
+	// ListByAccount get events account performed or was the target of, newest first, and how many there are.
+	ListByAccount(ctx context.Context, accountID int64, page, limit int) ([]*entities.AuditEvent, int, error)
+}
+
+type auditEventRepository struct {
+	db pkg.Database
+}
+
+func NewAuditEventRepository(db pkg.Database) AuditEventRepository {
+	return &auditEventRepository{
+		db: db,
+	}
+}
+
+const auditEventColumns = "id, action, actor_id, target_type, target_id, detail, ip_address, user_agent, request_id, created_at"
+
+// Create implements AuditEventRepository.
+func (r *auditEventRepository) Create(ctx context.Context, event *entities.AuditEvent) error {
+	query := `INSERT INTO audit_events (action, actor_id, target_type, target_id, detail, ip_address, user_agent, request_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	return r.db.Exec(ctx, query, event.Action, event.ActorID, event.TargetType, event.TargetID, event.Detail,
+		event.IPAddress, event.UserAgent, event.RequestID)
+}
+
+// List implements AuditEventRepository.
+func (r *auditEventRepository) List(ctx context.Context, filter *entities.AuditEventFilter, page, limit int) ([]*entities.AuditEvent, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+
+	if filter.TargetID != 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return r.list(ctx, where, args, page, limit)
+}
+
+// ListByAccount implements AuditEventRepository.
+func (r *auditEventRepository) ListByAccount(ctx context.Context, accountID int64, page, limit int) ([]*entities.AuditEvent, int, error) {
+	where := " WHERE actor_id = ? OR (target_type = ? AND target_id = ?)"
+
+	return r.list(ctx, where, []any{accountID, pkg.AuditTargetAccount, accountID}, page, limit)
+}
+
+func (r *auditEventRepository) list(ctx context.Context, where string, args []any, page, limit int) ([]*entities.AuditEvent, int, error) {
+	var totalItems int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM audit_events"+where, args...).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + auditEventColumns + " FROM audit_events" + where + " ORDER BY id DESC LIMIT ? OFFSET ?"
+
+	rows, err := r.db.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var events []*entities.AuditEvent
+	for rows.Next() {
+		event := new(entities.AuditEvent)
+		if err := rows.Scan(&event.ID, &event.Action, &event.ActorID, &event.TargetType, &event.TargetID, &event.Detail,
+			&event.IPAddress, &event.UserAgent, &event.RequestID, &event.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return events, totalItems, nil
+}
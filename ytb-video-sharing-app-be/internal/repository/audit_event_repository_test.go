@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type auditEventConfig struct {
+	testConfig
+	repo AuditEventRepository
+}
+
+func SetupAuditEventConfig(t *testing.T) *auditEventConfig {
+	testConf := SetupTest(t)
+
+	return &auditEventConfig{
+		testConfig: *testConf,
+		repo:       NewAuditEventRepository(testConf.db),
+	}
+}
+
+func auditEventScanArgs() []any {
+	args := make([]any, 10)
+	for i := range args {
+		args[i] = gomock.Any()
+	}
+
+	return args
+}
+
+func fillAuditEvent(args []interface{}, event *entities.AuditEvent) {
+	*args[0].(*int64) = event.ID
+	*args[1].(*string) = event.Action
+	*args[2].(*sql.NullInt64) = event.ActorID
+	*args[3].(*string) = event.TargetType
+	*args[4].(*sql.NullInt64) = event.TargetID
+	*args[5].(*string) = event.Detail
+	*args[6].(*string) = event.IPAddress
+	*args[7].(*string) = event.UserAgent
+	*args[8].(*string) = event.RequestID
+	*args[9].(*time.Time) = event.CreatedAt
+}
+
+func TestCreateAuditEvent(t *testing.T) {
+	t.Run("Should append event", func(t *testing.T) {
+		cfg := SetupAuditEventConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		event := &entities.AuditEvent{
+			Action:     "login_failed",
+			TargetType: pkg.AuditTargetAccount,
+			TargetID:   sql.NullInt64{Int64: 1, Valid: true},
+			Detail:     "user@example.com",
+			IPAddress:  "127.0.0.1",
+			UserAgent:  "curl",
+			RequestID:  "req-1",
+		}
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), "login_failed", sql.NullInt64{}, pkg.AuditTargetAccount, sql.NullInt64{Int64: 1, Valid: true},
+			"user@example.com", "127.0.0.1", "curl", "req-1").Return(nil)
+
+		assert.NoError(t, cfg.repo.Create(ctx, event))
+	})
+}
+
+func TestListAuditEvents(t *testing.T) {
+	expected := &entities.AuditEvent{
+		ID:         7,
+		Action:     "account_suspended",
+		ActorID:    sql.NullInt64{Int64: 1, Valid: true},
+		TargetType: pkg.AuditTargetAccount,
+		TargetID:   sql.NullInt64{Int64: 2, Valid: true},
+		Detail:     "spam",
+		CreatedAt:  time.Now(),
+	}
+
+	t.Run("Should filter events and return total items", func(t *testing.T) {
+		cfg := SetupAuditEventConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		from := time.Now().Add(-time.Hour)
+		filter := &entities.AuditEventFilter{ActorID: 1, Action: "account_suspended", From: from}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Regex("actor_id = \\? AND action = \\? AND created_at >= \\?"), int64(1), "account_suspended", from).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), "account_suspended", from, 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(auditEventScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAuditEvent(args, expected)
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		events, total, err := cfg.repo.List(ctx, filter, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []*entities.AuditEvent{expected}, events)
+	})
+
+	t.Run("Should list every event without filter", func(t *testing.T) {
+		cfg := SetupAuditEventConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, "SELECT COUNT(*) FROM audit_events").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 0
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), 10, 10).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Close()
+
+		events, total, err := cfg.repo.List(ctx, &entities.AuditEventFilter{}, 2, 10)
+
+		assert.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, events)
+	})
+
+	t.Run("Should return error if count fails", func(t *testing.T) {
+		cfg := SetupAuditEventConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		events, total, err := cfg.repo.List(ctx, &entities.AuditEventFilter{}, 1, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, total)
+		assert.Nil(t, events)
+	})
+}
+
+func TestListAuditEventsByAccount(t *testing.T) {
+	t.Run("Should list events account performed or was the target of", func(t *testing.T) {
+		cfg := SetupAuditEventConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expected := &entities.AuditEvent{
+			ID:         3,
+			Action:     "login_failed",
+			TargetType: pkg.AuditTargetAccount,
+			TargetID:   sql.NullInt64{Int64: 2, Valid: true},
+			CreatedAt:  time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(2), pkg.AuditTargetAccount, int64(2)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(2), pkg.AuditTargetAccount, int64(2), 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(auditEventScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAuditEvent(args, expected)
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		events, total, err := cfg.repo.ListByAccount(ctx, 2, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []*entities.AuditEvent{expected}, events)
+	})
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type BookmarkRepository interface {
+	// Create bookmark video for account, ErrDuplicate when it is bookmarked already.
+	Create(ctx context.Context, accountID, videoID int64) error
+
+	// Delete remove bookmark of video for account, ErrNoRowsAffected when there is none.
+	Delete(ctx context.Context, accountID, videoID int64) error
+
+	// GetBookmarkedVideos get videos bookmarked by account, the latest bookmarked first, and how many there are.
+	// Deleted videos are left out, and hidden ones unless the account owns them.
+	GetBookmarkedVideos(ctx context.Context, accountID int64, page, limit int) ([]*entities.Video, int, error)
+}
+
+type bookmarkRepository struct {
+	db pkg.Database
+}
+
+func NewBookmarkRepository(db pkg.Database) BookmarkRepository {
+	return &bookmarkRepository{
+		db: db,
+	}
+}
+
+// Create implements BookmarkRepository.
+func (b *bookmarkRepository) Create(ctx context.Context, accountID, videoID int64) error {
+	query := `INSERT INTO bookmarks (account_id, video_id) VALUES (?, ?)`
+
+	return b.db.Exec(ctx, query, accountID, videoID)
+}
+
+// Delete implements BookmarkRepository.
+func (b *bookmarkRepository) Delete(ctx context.Context, accountID, videoID int64) error {
+	query := `DELETE FROM bookmarks WHERE account_id = ? AND video_id = ?`
+
+	return b.db.Exec(ctx, query, accountID, videoID)
+}
+
+// GetBookmarkedVideos implements BookmarkRepository.
+func (b *bookmarkRepository) GetBookmarkedVideos(ctx context.Context, accountID int64, page, limit int) ([]*entities.Video, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM bookmarks mb
+	JOIN videos v ON v.id = mb.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE mb.account_id = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)`
+	if err := b.db.QueryRow(ctx, countQuery, accountID, accountID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id)
+	FROM bookmarks mb
+	JOIN videos v ON v.id = mb.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE mb.account_id = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)
+	ORDER BY mb.created_at DESC, v.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := b.db.Query(ctx, query, accountID, accountID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.Video
+	for rows.Next() {
+		video := &entities.Video{IsBookmarked: true}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount); err != nil {
+			return nil, 0, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return videos, totalItems, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type bookmarkConfig struct {
+	testConfig
+	repo BookmarkRepository
+}
+
+func SetupBookmarkConfig(t *testing.T) *bookmarkConfig {
+	testConf := SetupTest(t)
+
+	return &bookmarkConfig{
+		testConfig: *testConf,
+		repo:       NewBookmarkRepository(testConf.db),
+	}
+}
+
+func TestCreateBookmark(t *testing.T) {
+	t.Run("Should bookmark video", func(t *testing.T) {
+		cfg := SetupBookmarkConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Regex("INSERT INTO bookmarks"), int64(1), int64(3)).Return(nil)
+
+		assert.NoError(t, cfg.repo.Create(ctx, 1, 3))
+	})
+
+	t.Run("Should return ErrDuplicate if video is bookmarked already", func(t *testing.T) {
+		cfg := SetupBookmarkConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), int64(3)).Return(pkg.ErrDuplicate)
+
+		assert.ErrorIs(t, cfg.repo.Create(ctx, 1, 3), pkg.ErrDuplicate)
+	})
+}
+
+func TestDeleteBookmark(t *testing.T) {
+	t.Run("Should return ErrNoRowsAffected if video is not bookmarked", func(t *testing.T) {
+		cfg := SetupBookmarkConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Regex("DELETE FROM bookmarks"), int64(1), int64(3)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Delete(ctx, 1, 3), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestGetBookmarkedVideos(t *testing.T) {
+	t.Run("Should return bookmarked videos with their bookmark count", func(t *testing.T) {
+		cfg := SetupBookmarkConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 3
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("ORDER BY mb.created_at DESC"), int64(1), int64(1), 2, 2).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 5
+			*args[10].(*int64) = 4
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		videos, total, err := cfg.repo.GetBookmarkedVideos(ctx, 1, 2, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Len(t, videos, 1)
+		assert.Equal(t, int64(4), videos[0].BookmarkCount)
+		assert.True(t, videos[0].IsBookmarked)
+	})
+
+	t.Run("Should return error if count query fails", func(t *testing.T) {
+		cfg := SetupBookmarkConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		videos, total, err := cfg.repo.GetBookmarkedVideos(ctx, 1, 1, 2)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, videos)
+		assert.Equal(t, 0, total)
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type FollowRepository interface {
+	// Follow create a follow relation from follower to following.
+	Follow(ctx context.Context, followerID, followingID int64) error
+
+	// Unfollow delete a follow relation from follower to following.
+	Unfollow(ctx context.Context, followerID, followingID int64) error
+
+	// GetFollowers get paginated accounts that follow the account.
+	GetFollowers(ctx context.Context, accountID int64, page, limit int) ([]*entities.Account, int, error)
+
+	// GetFollowing get paginated accounts that the account follows.
+	GetFollowing(ctx context.Context, accountID int64, page, limit int) ([]*entities.Account, int, error)
+
+	// GetFollowerIDs get ids of all accounts that follow the account.
+	GetFollowerIDs(ctx context.Context, accountID int64) ([]int64, error)
+}
+
+type followRepository struct {
+	db pkg.Database
+}
+
+func NewFollowRepository(db pkg.Database) FollowRepository {
+	return &followRepository{
+		db: db,
+	}
+}
+
+// Follow implements FollowRepository.
+func (f *followRepository) Follow(ctx context.Context, followerID, followingID int64) error {
+	query := `INSERT INTO follows (follower_id, following_id) VALUES (?, ?)`
+
+	return f.db.Exec(ctx, query, followerID, followingID)
+}
+
+// Unfollow implements FollowRepository.
+func (f *followRepository) Unfollow(ctx context.Context, followerID, followingID int64) error {
+	query := `DELETE FROM follows WHERE follower_id = ? AND following_id = ?`
+
+	return f.db.Exec(ctx, query, followerID, followingID)
+}
+
+// GetFollowers implements FollowRepository.
+func (f *followRepository) GetFollowers(ctx context.Context, accountID int64, page, limit int) ([]*entities.Account, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM follows f
+	JOIN accounts a ON f.follower_id = a.id
+	WHERE f.following_id = ? AND a.deleted_at IS NULL`
+	if err := f.db.QueryRow(ctx, countQuery, accountID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.email, a.fullname, a.avatarURL, a.bio, a.created_at, a.email_verified_at, a.role,
+	a.suspended_at, a.suspended_until, a.suspension_reason, a.deleted_at, a.deleted_by
+	FROM follows f
+	JOIN accounts a ON f.follower_id = a.id
+	WHERE f.following_id = ? AND a.deleted_at IS NULL
+	ORDER BY f.created_at DESC LIMIT ? OFFSET ?`
+
+	accounts, err := f.queryAccounts(ctx, query, accountID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, totalItems, nil
+}
+
+// GetFollowing implements FollowRepository.
+func (f *followRepository) GetFollowing(ctx context.Context, accountID int64, page, limit int) ([]*entities.Account, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM follows f
+	JOIN accounts a ON f.following_id = a.id
+	WHERE f.follower_id = ? AND a.deleted_at IS NULL`
+	if err := f.db.QueryRow(ctx, countQuery, accountID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.email, a.fullname, a.avatarURL, a.bio, a.created_at, a.email_verified_at, a.role,
+	a.suspended_at, a.suspended_until, a.suspension_reason, a.deleted_at, a.deleted_by
+	FROM follows f
+	JOIN accounts a ON f.following_id = a.id
+	WHERE f.follower_id = ? AND a.deleted_at IS NULL
+	ORDER BY f.created_at DESC LIMIT ? OFFSET ?`
+
+	accounts, err := f.queryAccounts(ctx, query, accountID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, totalItems, nil
+}
+
+// GetFollowerIDs implements FollowRepository.
+func (f *followRepository) GetFollowerIDs(ctx context.Context, accountID int64) ([]int64, error) {
+	query := `SELECT follower_id FROM follows WHERE following_id = ?`
+
+	rows, err := f.db.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (f *followRepository) queryAccounts(ctx context.Context, query string, args ...any) ([]*entities.Account, error) {
+	rows, err := f.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var accounts []*entities.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type followConfig struct {
+	testConfig
+	repo FollowRepository
+}
+
+func SetupFollowConfig(t *testing.T) *followConfig {
+	testConf := SetupTest(t)
+
+	return &followConfig{
+		testConfig: *testConf,
+		repo:       NewFollowRepository(testConf.db),
+	}
+}
+
+func TestFollow(t *testing.T) {
+	t.Run("Should follow an account", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), int64(2)).Return(nil)
+
+		err := cfg.repo.Follow(ctx, 1, 2)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return duplicate error if already following", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), int64(2)).Return(pkg.ErrDuplicate)
+
+		err := cfg.repo.Follow(ctx, 1, 2)
+
+		assert.ErrorIs(t, err, pkg.ErrDuplicate)
+	})
+}
+
+func TestUnfollow(t *testing.T) {
+	t.Run("Should unfollow an account", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), int64(2)).Return(nil)
+
+		err := cfg.repo.Unfollow(ctx, 1, 2)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if not following", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), int64(2)).Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.Unfollow(ctx, 1, 2)
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestGetFollowers(t *testing.T) {
+	t.Run("Should return list followers and total items", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedAccounts := []*entities.Account{
+			{ID: 2, Email: "two@example.com", FullName: "User Two", AvatarURL: "avatar2"},
+			{ID: 3, Email: "three@example.com", FullName: "User Three", AvatarURL: "avatar3"},
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = len(expectedAccounts)
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedAccounts))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(accountScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillAccount(args, expectedAccounts[0])
+			expectedAccounts = expectedAccounts[1:]
+			return nil
+		}).Times(len(expectedAccounts))
+		cfg.rows.EXPECT().Close().Times(1)
+
+		accounts, total, err := cfg.repo.GetFollowers(ctx, 1, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, accounts, 2)
+	})
+
+	t.Run("Should return error if count query fails", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		accounts, total, err := cfg.repo.GetFollowers(ctx, 1, 1, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, accounts)
+		assert.Equal(t, 0, total)
+	})
+}
+
+func TestGetFollowing(t *testing.T) {
+	t.Run("Should return error if db query fails", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db execute failed")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 5
+			return nil
+		})
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), 5, 5).Return(nil, expectedErr)
+
+		accounts, total, err := cfg.repo.GetFollowing(ctx, 1, 2, 5)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, accounts)
+		assert.Equal(t, 0, total)
+	})
+}
+
+func TestGetFollowerIDs(t *testing.T) {
+	t.Run("Should return ids of followers", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedIDs := []int64{2, 3}
+		remaining := append([]int64{}, expectedIDs...)
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedIDs))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = remaining[0]
+			remaining = remaining[1:]
+			return nil
+		}).Times(len(expectedIDs))
+		cfg.rows.EXPECT().Close().Times(1)
+
+		ids, err := cfg.repo.GetFollowerIDs(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedIDs, ids)
+	})
+
+	t.Run("Should return error if scan fails", func(t *testing.T) {
+		cfg := SetupFollowConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("scan error")
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+		cfg.rows.EXPECT().Close().Times(1)
+
+		ids, err := cfg.repo.GetFollowerIDs(ctx, 1)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, ids)
+	})
+}
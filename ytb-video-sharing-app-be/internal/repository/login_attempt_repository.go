@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type LoginAttemptRepository interface {
+	// SaveFailure save failed login attempt of key.
+	SaveFailure(ctx context.Context, key string, at time.Time) error
+
+	// GetState count failed attempts of key since since, with the latest one and lock of key.
+	GetState(ctx context.Context, key string, since time.Time) (*pkg.LoginAttemptState, error)
+
+	// SaveLockout lock key until lockedUntil, a later lock wins when key is already locked.
+	SaveLockout(ctx context.Context, key string, lockedUntil time.Time) error
+
+	// Delete delete failed attempts and lock of key.
+	Delete(ctx context.Context, key string) error
+
+	// DeleteExpired delete failed attempts and locks that are over, both older than before.
+	DeleteExpired(ctx context.Context, before time.Time) error
+}
+
+type loginAttemptRepository struct {
+	db pkg.Database
+}
+
+func NewLoginAttemptRepository(db pkg.Database) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		db: db,
+	}
+}
+
+// SaveFailure implements LoginAttemptRepository.
+func (l *loginAttemptRepository) SaveFailure(ctx context.Context, key string, at time.Time) error {
+	query := `INSERT INTO login_attempts (throttle_key, attempted_at) VALUES(?, ?)`
+
+	return l.db.Exec(ctx, query, key, at)
+}
+
+// GetState implements LoginAttemptRepository.
+func (l *loginAttemptRepository) GetState(ctx context.Context, key string, since time.Time) (*pkg.LoginAttemptState, error) {
+	query := `SELECT COUNT(*), MAX(attempted_at) FROM login_attempts WHERE throttle_key = ? AND attempted_at > ?`
+
+	var (
+		state         pkg.LoginAttemptState
+		lastFailureAt sql.NullTime
+	)
+
+	if err := l.db.QueryRow(ctx, query, key, since).Scan(&state.Failures, &lastFailureAt); err != nil {
+		return nil, err
+	}
+
+	state.LastFailureAt = lastFailureAt.Time
+
+	query = `SELECT locked_until FROM login_lockouts WHERE throttle_key = ?`
+
+	if err := l.db.QueryRow(ctx, query, key).Scan(&state.LockedUntil); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// SaveLockout implements LoginAttemptRepository.
+func (l *loginAttemptRepository) SaveLockout(ctx context.Context, key string, lockedUntil time.Time) error {
+	query := `INSERT INTO login_lockouts (throttle_key, locked_until) VALUES(?, ?)
+		ON DUPLICATE KEY UPDATE locked_until = GREATEST(locked_until, VALUES(locked_until))`
+
+	// locking again until an earlier time affects no rows, which is not an error here
+	_, err := l.db.ExecWithResult(ctx, query, key, lockedUntil)
+
+	return err
+}
+
+// Delete implements LoginAttemptRepository.
+func (l *loginAttemptRepository) Delete(ctx context.Context, key string) error {
+	if _, err := l.db.ExecWithResult(ctx, `DELETE FROM login_attempts WHERE throttle_key = ?`, key); err != nil {
+		return err
+	}
+
+	_, err := l.db.ExecWithResult(ctx, `DELETE FROM login_lockouts WHERE throttle_key = ?`, key)
+
+	return err
+}
+
+// DeleteExpired implements LoginAttemptRepository.
+func (l *loginAttemptRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	if _, err := l.db.ExecWithResult(ctx, `DELETE FROM login_attempts WHERE attempted_at <= ?`, before); err != nil {
+		return err
+	}
+
+	_, err := l.db.ExecWithResult(ctx, `DELETE FROM login_lockouts WHERE locked_until <= ?`, before)
+
+	return err
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type loginAttemptConfig struct {
+	testConfig
+	repo LoginAttemptRepository
+}
+
+func SetupLoginAttemptConfig(t *testing.T) *loginAttemptConfig {
+	testConf := SetupTest(t)
+
+	return &loginAttemptConfig{
+		testConfig: *testConf,
+		repo:       NewLoginAttemptRepository(testConf.db),
+	}
+}
+
+func TestSaveLoginFailure(t *testing.T) {
+	t.Run("Should save failed attempt", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		at := time.Now()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), "account:test@example.com", at).Return(nil)
+
+		err := cfg.repo.SaveFailure(ctx, "account:test@example.com", at)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetLoginAttemptState(t *testing.T) {
+	t.Run("Should return failures and lock of key", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		since := time.Now().Add(-time.Hour)
+		expected := &pkg.LoginAttemptState{
+			Failures:      3,
+			LastFailureAt: time.Now(),
+			LockedUntil:   time.Now().Add(time.Hour),
+		}
+
+		gomock.InOrder(
+			cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "ip:127.0.0.1", since).Return(cfg.row),
+			cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(args ...interface{}) error {
+					*args[0].(*int) = expected.Failures
+					*args[1].(*sql.NullTime) = sql.NullTime{Time: expected.LastFailureAt, Valid: true}
+					return nil
+				}),
+			cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "ip:127.0.0.1").Return(cfg.row),
+			cfg.row.EXPECT().Scan(gomock.Any()).
+				DoAndReturn(func(args ...interface{}) error {
+					*args[0].(*time.Time) = expected.LockedUntil
+					return nil
+				}),
+		)
+
+		rs, err := cfg.repo.GetState(ctx, "ip:127.0.0.1", since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rs)
+	})
+
+	t.Run("Should return zero lock if key was never locked", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		since := time.Now().Add(-time.Hour)
+
+		gomock.InOrder(
+			cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "ip:127.0.0.1", since).Return(cfg.row),
+			cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(nil),
+			cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "ip:127.0.0.1").Return(cfg.row),
+			cfg.row.EXPECT().Scan(gomock.Any()).Return(sql.ErrNoRows),
+		)
+
+		rs, err := cfg.repo.GetState(ctx, "ip:127.0.0.1", since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &pkg.LoginAttemptState{}, rs)
+	})
+
+	t.Run("Should return error if counting fails", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		since := time.Now().Add(-time.Hour)
+		expectedErr := errors.New("db error")
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "ip:127.0.0.1", since).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any()).Return(expectedErr)
+
+		rs, err := cfg.repo.GetState(ctx, "ip:127.0.0.1", since)
+
+		assert.Nil(t, rs)
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestSaveLoginLockout(t *testing.T) {
+	t.Run("Should not fail when an existing lock is later", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		lockedUntil := time.Now().Add(time.Hour)
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "account:test@example.com", lockedUntil).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		err := cfg.repo.SaveLockout(ctx, "account:test@example.com", lockedUntil)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteLoginAttempts(t *testing.T) {
+	t.Run("Should delete failed attempts and lock", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "account:test@example.com").
+			Return(&MockSQLResult{RowAffected: 0}, nil).
+			Times(2)
+
+		err := cfg.repo.Delete(ctx, "account:test@example.com")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteExpiredLoginAttempts(t *testing.T) {
+	t.Run("Should delete old attempts and locks", func(t *testing.T) {
+		cfg := SetupLoginAttemptConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		before := time.Now().Add(-time.Hour)
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), before).
+			Return(&MockSQLResult{RowAffected: 5}, nil).
+			Times(2)
+
+		err := cfg.repo.DeleteExpired(ctx, before)
+
+		assert.NoError(t, err)
+	})
+}
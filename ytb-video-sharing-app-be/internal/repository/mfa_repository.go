@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type MFARepository interface {
+	// SaveSecret save TOTP secret of a pending enrollment, returns pkg.ErrNoRowsAffected when 2FA is already enabled.
+	SaveSecret(ctx context.Context, accountID int64, secret string) error
+
+	// GetMFA get TOTP secret of account, nil if account never enrolled.
+	GetMFA(ctx context.Context, accountID int64) *entities.AccountMFA
+
+	// UseStep mark TOTP step as used, returns pkg.ErrNoRowsAffected when the step or a later one was already used.
+	UseStep(ctx context.Context, accountID int64, step int64) error
+
+	// Enable enable 2FA of a pending enrollment within transaction.
+	Enable(ctx context.Context, tx pkg.Tx, accountID int64) error
+
+	// Delete delete TOTP secret and recovery codes of account within transaction.
+	Delete(ctx context.Context, tx pkg.Tx, accountID int64) error
+
+	// ReplaceRecoveryCodes replace every recovery code of account within transaction.
+	ReplaceRecoveryCodes(ctx context.Context, tx pkg.Tx, accountID int64, codeHashes []string) error
+
+	// UseRecoveryCode mark recovery code as used, returns pkg.ErrNoRowsAffected when it is unknown or already used.
+	UseRecoveryCode(ctx context.Context, accountID int64, codeHash string) error
+}
+
+type mfaRepository struct {
+	db pkg.Database
+}
+
+func NewMFARepository(db pkg.Database) MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+// SaveSecret implements MFARepository.
+func (m *mfaRepository) SaveSecret(ctx context.Context, accountID int64, secret string) error {
+	// an enabled secret is kept, so enrolling again can not silently replace it
+	query := `INSERT INTO account_mfa (account_id, secret) VALUES(?, ?)
+		ON DUPLICATE KEY UPDATE
+			secret = IF(enabled_at IS NULL, VALUES(secret), secret),
+			last_used_step = IF(enabled_at IS NULL, 0, last_used_step)`
+
+	return m.db.Exec(ctx, query, accountID, secret)
+}
+
+// GetMFA implements MFARepository.
+func (m *mfaRepository) GetMFA(ctx context.Context, accountID int64) *entities.AccountMFA {
+	query := `SELECT account_id, secret, enabled_at, last_used_step, created_at, updated_at
+		FROM account_mfa WHERE account_id = ?`
+
+	mfa := new(entities.AccountMFA)
+	row := m.db.QueryRow(ctx, query, accountID)
+
+	if err := row.Scan(&mfa.AccountID, &mfa.Secret, &mfa.EnabledAt,
+		&mfa.LastUsedStep, &mfa.CreatedAt, &mfa.UpdatedAt); err != nil {
+		return nil
+	}
+
+	return mfa
+}
+
+// UseStep implements MFARepository.
+func (m *mfaRepository) UseStep(ctx context.Context, accountID int64, step int64) error {
+	// conditional update, so a code replayed concurrently is accepted only once
+	query := `UPDATE account_mfa SET last_used_step = ? WHERE account_id = ? AND last_used_step < ?`
+
+	return m.db.Exec(ctx, query, step, accountID, step)
+}
+
+// Enable implements MFARepository.
+func (m *mfaRepository) Enable(ctx context.Context, tx pkg.Tx, accountID int64) error {
+	query := `UPDATE account_mfa SET enabled_at = CURRENT_TIMESTAMP WHERE account_id = ? AND enabled_at IS NULL`
+
+	return tx.Exec(ctx, query, accountID)
+}
+
+// Delete implements MFARepository.
+func (m *mfaRepository) Delete(ctx context.Context, tx pkg.Tx, accountID int64) error {
+	if err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE account_id = ?`, accountID); err != nil {
+		return err
+	}
+
+	return tx.Exec(ctx, `DELETE FROM account_mfa WHERE account_id = ?`, accountID)
+}
+
+// ReplaceRecoveryCodes implements MFARepository.
+func (m *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, tx pkg.Tx, accountID int64, codeHashes []string) error {
+	if err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE account_id = ?`, accountID); err != nil {
+		return err
+	}
+
+	if len(codeHashes) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO mfa_recovery_codes (account_id, code_hash) VALUES (?, ?)` +
+		strings.Repeat(", (?, ?)", len(codeHashes)-1)
+
+	args := make([]any, 0, len(codeHashes)*2)
+	for _, codeHash := range codeHashes {
+		args = append(args, accountID, codeHash)
+	}
+
+	return tx.Exec(ctx, query, args...)
+}
+
+// UseRecoveryCode implements MFARepository.
+func (m *mfaRepository) UseRecoveryCode(ctx context.Context, accountID int64, codeHash string) error {
+	query := `UPDATE mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE account_id = ? AND code_hash = ? AND used_at IS NULL`
+
+	return m.db.Exec(ctx, query, accountID, codeHash)
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type mfaConfig struct {
+	testConfig
+	repo MFARepository
+}
+
+func SetupMFAConfig(t *testing.T) *mfaConfig {
+	testConf := SetupTest(t)
+
+	return &mfaConfig{
+		testConfig: *testConf,
+		repo:       NewMFARepository(testConf.db),
+	}
+}
+
+func TestSaveMFASecret(t *testing.T) {
+	t.Run("Should save secret of pending enrollment", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "SECRET").Return(nil)
+
+		err := cfg.repo.SaveSecret(ctx, 1, "SECRET")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return ErrNoRowsAffected if 2FA is already enabled", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "SECRET").Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.SaveSecret(ctx, 1, "SECRET")
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestGetMFA(t *testing.T) {
+	t.Run("Should return secret if found", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expected := &entities.AccountMFA{
+			AccountID:    1,
+			Secret:       "SECRET",
+			EnabledAt:    sql.NullTime{Time: time.Now(), Valid: true},
+			LastUsedStep: 100,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*int64) = expected.AccountID
+				*args[1].(*string) = expected.Secret
+				*args[2].(*sql.NullTime) = expected.EnabledAt
+				*args[3].(*int64) = expected.LastUsedStep
+				*args[4].(*time.Time) = expected.CreatedAt
+				*args[5].(*time.Time) = expected.UpdatedAt
+				return nil
+			})
+
+		rs := cfg.repo.GetMFA(ctx, 1)
+
+		assert.Equal(t, expected, rs)
+	})
+
+	t.Run("Should return nil if account never enrolled", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(sql.ErrNoRows)
+
+		rs := cfg.repo.GetMFA(ctx, 1)
+
+		assert.Nil(t, rs)
+	})
+}
+
+func TestUseMFAStep(t *testing.T) {
+	t.Run("Should mark step as used", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(100), int64(1), int64(100)).Return(nil)
+
+		err := cfg.repo.UseStep(ctx, 1, 100)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return ErrNoRowsAffected if step was already used", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(100), int64(1), int64(100)).Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.UseStep(ctx, 1, 100)
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestEnableMFA(t *testing.T) {
+	t.Run("Should enable 2FA", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil)
+
+		err := cfg.repo.Enable(ctx, cfg.tx, 1)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteMFA(t *testing.T) {
+	t.Run("Should delete recovery codes and secret", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil).Times(2)
+
+		err := cfg.repo.Delete(ctx, cfg.tx, 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should stop if deleting recovery codes fails", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(expectedErr)
+
+		err := cfg.repo.Delete(ctx, cfg.tx, 1)
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestReplaceRecoveryCodes(t *testing.T) {
+	t.Run("Should replace recovery codes in one insert", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1), "hash1", int64(1), "hash2").Return(nil),
+		)
+
+		err := cfg.repo.ReplaceRecoveryCodes(ctx, cfg.tx, 1, []string{"hash1", "hash2"})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	t.Run("Should mark recovery code as used", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "hash").Return(nil)
+
+		err := cfg.repo.UseRecoveryCode(ctx, 1, "hash")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return ErrNoRowsAffected if code is unknown or used", func(t *testing.T) {
+		cfg := SetupMFAConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "hash").Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.UseRecoveryCode(ctx, 1, "hash")
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
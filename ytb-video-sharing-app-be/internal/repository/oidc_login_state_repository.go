@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type OIDCLoginStateRepository interface {
+	// Save save state of a login redirected to the identity provider.
+	Save(ctx context.Context, payload *entities.OIDCLoginState) error
+
+	// GetState get state by its hash.
+	GetState(ctx context.Context, stateHash string) *entities.OIDCLoginState
+
+	// Delete delete state, returns pkg.ErrNoRowsAffected when it was already used.
+	Delete(ctx context.Context, stateHash string) error
+
+	// DeleteExpired delete states that are expired.
+	DeleteExpired(ctx context.Context) error
+}
+
+type oidcLoginStateRepository struct {
+	db pkg.Database
+}
+
+func NewOIDCLoginStateRepository(db pkg.Database) OIDCLoginStateRepository {
+	return &oidcLoginStateRepository{
+		db: db,
+	}
+}
+
+// Save implements OIDCLoginStateRepository.
+func (o *oidcLoginStateRepository) Save(ctx context.Context, payload *entities.OIDCLoginState) error {
+	query := `INSERT INTO oidc_login_states (state_hash, code_verifier, nonce, expires_at)
+		VALUES(?, ?, ?, ?)`
+
+	return o.db.Exec(ctx, query, payload.StateHash, payload.CodeVerifier, payload.Nonce, payload.ExpiresAt)
+}
+
+// GetState implements OIDCLoginStateRepository.
+func (o *oidcLoginStateRepository) GetState(ctx context.Context, stateHash string) *entities.OIDCLoginState {
+	query := `SELECT state_hash, code_verifier, nonce, expires_at, created_at
+		FROM oidc_login_states WHERE state_hash = ?`
+
+	state := new(entities.OIDCLoginState)
+	row := o.db.QueryRow(ctx, query, stateHash)
+
+	if err := row.Scan(&state.StateHash, &state.CodeVerifier, &state.Nonce,
+		&state.ExpiresAt, &state.CreatedAt); err != nil {
+		return nil
+	}
+
+	return state
+}
+
+// Delete implements OIDCLoginStateRepository.
+func (o *oidcLoginStateRepository) Delete(ctx context.Context, stateHash string) error {
+	query := `DELETE FROM oidc_login_states WHERE state_hash = ?`
+
+	return o.db.Exec(ctx, query, stateHash)
+}
+
+// DeleteExpired implements OIDCLoginStateRepository.
+func (o *oidcLoginStateRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM oidc_login_states WHERE expires_at <= CURRENT_TIMESTAMP`
+
+	_, err := o.db.ExecWithResult(ctx, query)
+
+	return err
+}
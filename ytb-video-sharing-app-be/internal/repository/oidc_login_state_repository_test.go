@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type oidcLoginStateConfig struct {
+	testConfig
+	repo OIDCLoginStateRepository
+}
+
+func SetupOIDCLoginStateConfig(t *testing.T) *oidcLoginStateConfig {
+	testConf := SetupTest(t)
+
+	return &oidcLoginStateConfig{
+		testConfig: *testConf,
+		repo:       NewOIDCLoginStateRepository(testConf.db),
+	}
+}
+
+func TestSaveOIDCLoginState(t *testing.T) {
+	t.Run("Should save login state", func(t *testing.T) {
+		cfg := SetupOIDCLoginStateConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		state := &entities.OIDCLoginState{
+			StateHash:    "hash",
+			CodeVerifier: "verifier",
+			Nonce:        "nonce",
+			ExpiresAt:    time.Now().Add(10 * time.Minute),
+		}
+
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), state.StateHash, state.CodeVerifier, state.Nonce, state.ExpiresAt).
+			Return(nil)
+
+		err := cfg.repo.Save(ctx, state)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetOIDCLoginState(t *testing.T) {
+	t.Run("Should return state if found", func(t *testing.T) {
+		cfg := SetupOIDCLoginStateConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expected := &entities.OIDCLoginState{
+			StateHash:    "hash",
+			CodeVerifier: "verifier",
+			Nonce:        "nonce",
+			ExpiresAt:    time.Now().Add(10 * time.Minute),
+			CreatedAt:    time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "hash").Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*string) = expected.StateHash
+				*args[1].(*string) = expected.CodeVerifier
+				*args[2].(*string) = expected.Nonce
+				*args[3].(*time.Time) = expected.ExpiresAt
+				*args[4].(*time.Time) = expected.CreatedAt
+				return nil
+			})
+
+		rs := cfg.repo.GetState(ctx, "hash")
+
+		assert.Equal(t, expected, rs)
+	})
+
+	t.Run("Should return nil if state not found", func(t *testing.T) {
+		cfg := SetupOIDCLoginStateConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "hash").Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(sql.ErrNoRows)
+
+		rs := cfg.repo.GetState(ctx, "hash")
+
+		assert.Nil(t, rs)
+	})
+}
+
+func TestDeleteOIDCLoginState(t *testing.T) {
+	t.Run("Should return ErrNoRowsAffected if state was already used", func(t *testing.T) {
+		cfg := SetupOIDCLoginStateConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), "hash").Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.Delete(ctx, "hash")
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestDeleteExpiredOIDCLoginStates(t *testing.T) {
+	t.Run("Should not fail when nothing is expired", func(t *testing.T) {
+		cfg := SetupOIDCLoginStateConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any()).Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		err := cfg.repo.DeleteExpired(ctx)
+
+		assert.NoError(t, err)
+	})
+}
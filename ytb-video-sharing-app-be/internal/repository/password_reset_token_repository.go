@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type PasswordResetTokenRepository interface {
+	// Save password reset token.
+	Save(ctx context.Context, payload *entities.PasswordResetToken) error
+
+	// DeleteUnusedTokens delete tokens of account that have not been used yet.
+	DeleteUnusedTokens(ctx context.Context, accountID int64) error
+
+	// GetTokenByHashForUpdate get token by its hash and lock the row within transaction.
+	GetTokenByHashForUpdate(ctx context.Context, tx pkg.Tx, tokenHash string) *entities.PasswordResetToken
+
+	// MarkUsed mark token as used within transaction.
+	MarkUsed(ctx context.Context, tx pkg.Tx, id int64) error
+}
+
+type passwordResetTokenRepository struct {
+	db pkg.Database
+}
+
+func NewPasswordResetTokenRepository(db pkg.Database) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+// Save implements PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) Save(ctx context.Context, payload *entities.PasswordResetToken) error {
+	query := `INSERT INTO password_reset_tokens (account_id, token_hash, expires_at)
+				VALUES(?, ?, ?)`
+
+	return p.db.Exec(ctx, query, payload.AccountID, payload.TokenHash, payload.ExpiresAt)
+}
+
+// DeleteUnusedTokens implements PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) DeleteUnusedTokens(ctx context.Context, accountID int64) error {
+	query := `DELETE FROM password_reset_tokens WHERE account_id = ? AND used_at IS NULL`
+
+	_, err := p.db.ExecWithResult(ctx, query, accountID)
+
+	return err
+}
+
+// GetTokenByHashForUpdate implements PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) GetTokenByHashForUpdate(ctx context.Context, tx pkg.Tx, tokenHash string) *entities.PasswordResetToken {
+	query := `SELECT id, account_id, token_hash, expires_at, used_at, created_at
+		FROM password_reset_tokens WHERE token_hash = ? FOR UPDATE`
+
+	token := new(entities.PasswordResetToken)
+	row := tx.QueryRow(ctx, query, tokenHash)
+
+	if err := row.Scan(&token.ID, &token.AccountID, &token.TokenHash,
+		&token.ExpiresAt, &token.UsedAt, &token.CreatedAt); err != nil {
+		return nil
+	}
+
+	return token
+}
+
+// MarkUsed implements PasswordResetTokenRepository.
+func (p *passwordResetTokenRepository) MarkUsed(ctx context.Context, tx pkg.Tx, id int64) error {
+	query := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	return tx.Exec(ctx, query, id)
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type passwordResetTokenConfig struct {
+	testConfig
+	repo PasswordResetTokenRepository
+}
+
+func SetupPasswordResetTokenConfig(t *testing.T) *passwordResetTokenConfig {
+	testConf := SetupTest(t)
+
+	return &passwordResetTokenConfig{
+		testConfig: *testConf,
+		repo:       NewPasswordResetTokenRepository(testConf.db),
+	}
+}
+
+func TestSavePasswordResetToken(t *testing.T) {
+	t.Run("Should save a new reset token", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		token := &entities.PasswordResetToken{
+			AccountID: 1,
+			TokenHash: "hash",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), token.AccountID, token.TokenHash, token.ExpiresAt).
+			Return(nil)
+
+		err := cfg.repo.Save(ctx, token)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteUnusedPasswordResetTokens(t *testing.T) {
+	t.Run("Should not fail when account has no unused token", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), int64(1)).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		err := cfg.repo.DeleteUnusedTokens(ctx, 1)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetPasswordResetTokenByHashForUpdate(t *testing.T) {
+	t.Run("Should return token if found", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expected := &entities.PasswordResetToken{
+			ID:        1,
+			AccountID: 2,
+			TokenHash: "hash",
+			ExpiresAt: time.Now().Add(time.Hour),
+			CreatedAt: time.Now(),
+		}
+
+		cfg.tx.EXPECT().QueryRow(ctx, gomock.Any(), expected.TokenHash).Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*int64) = expected.ID
+				*args[1].(*int64) = expected.AccountID
+				*args[2].(*string) = expected.TokenHash
+				*args[3].(*time.Time) = expected.ExpiresAt
+				*args[4].(*sql.NullTime) = expected.UsedAt
+				*args[5].(*time.Time) = expected.CreatedAt
+				return nil
+			})
+
+		rs := cfg.repo.GetTokenByHashForUpdate(ctx, cfg.tx, expected.TokenHash)
+
+		assert.Equal(t, expected, rs)
+	})
+
+	t.Run("Should return nil if token not found", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.tx.EXPECT().QueryRow(ctx, gomock.Any(), "hash").Return(cfg.row)
+		cfg.row.EXPECT().
+			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(sql.ErrNoRows)
+
+		rs := cfg.repo.GetTokenByHashForUpdate(ctx, cfg.tx, "hash")
+
+		assert.Nil(t, rs)
+	})
+}
+
+func TestMarkPasswordResetTokenUsed(t *testing.T) {
+	t.Run("Should mark token as used", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(nil)
+
+		err := cfg.repo.MarkUsed(ctx, cfg.tx, 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if db execution fails", func(t *testing.T) {
+		cfg := SetupPasswordResetTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(expectedErr)
+
+		err := cfg.repo.MarkUsed(ctx, cfg.tx, 1)
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type PersonalAccessTokenRepository interface {
+	// Create save personal access token, returning its id.
+	Create(ctx context.Context, payload *entities.PersonalAccessToken) (int64, error)
+
+	// GetByHash get personal access token by its hash, revoked and expired tokens are returned too.
+	GetByHash(ctx context.Context, tokenHash string) *entities.PersonalAccessToken
+
+	// GetTokens get personal access tokens of account that are not revoked, newest first.
+	GetTokens(ctx context.Context, accountID int64) ([]*entities.PersonalAccessToken, error)
+
+	// Revoke mark personal access token owned by account as revoked.
+	Revoke(ctx context.Context, accountID int64, id int64) error
+
+	// RevokeAll mark every personal access token of account as revoked within transaction.
+	RevokeAll(ctx context.Context, tx pkg.Tx, accountID int64) error
+
+	// MarkUsed set last used time of token, at most once a minute to keep writes low.
+	MarkUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+type personalAccessTokenRepository struct {
+	db pkg.Database
+}
+
+func NewPersonalAccessTokenRepository(db pkg.Database) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{
+		db: db,
+	}
+}
+
+const personalAccessTokenColumns = "id, account_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, revoked_at, created_at"
+
+func scanPersonalAccessToken(row pkg.Row) (*entities.PersonalAccessToken, error) {
+	token := new(entities.PersonalAccessToken)
+	var scopes string
+
+	if err := row.Scan(&token.ID, &token.AccountID, &token.Name, &token.TokenPrefix,
+		&token.TokenHash, &scopes, &token.ExpiresAt, &token.LastUsedAt,
+		&token.RevokedAt, &token.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+
+	return token, nil
+}
+
+// Create implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) Create(ctx context.Context, payload *entities.PersonalAccessToken) (int64, error) {
+	query := `INSERT INTO personal_access_tokens (account_id, name, token_prefix, token_hash, scopes, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	rs, err := p.db.ExecWithResult(ctx, query, payload.AccountID, payload.Name, payload.TokenPrefix,
+		payload.TokenHash, strings.Join(payload.Scopes, ","), payload.ExpiresAt)
+
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertId, err := rs.LastInsertId()
+
+	if err != nil || lastInsertId == 0 {
+		return 0, errors.New("db execution failed")
+	}
+
+	return lastInsertId, nil
+}
+
+// GetByHash implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) GetByHash(ctx context.Context, tokenHash string) *entities.PersonalAccessToken {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = ?`
+
+	token, err := scanPersonalAccessToken(p.db.QueryRow(ctx, query, tokenHash))
+
+	if err != nil {
+		return nil
+	}
+
+	return token
+}
+
+// GetTokens implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) GetTokens(ctx context.Context, accountID int64) ([]*entities.PersonalAccessToken, error) {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens
+		WHERE account_id = ? AND revoked_at IS NULL
+		ORDER BY created_at DESC, id DESC`
+
+	rows, err := p.db.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*entities.PersonalAccessToken
+	for rows.Next() {
+		token, err := scanPersonalAccessToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) Revoke(ctx context.Context, accountID int64, id int64) error {
+	query := `UPDATE personal_access_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND account_id = ? AND revoked_at IS NULL`
+
+	return p.db.Exec(ctx, query, id, accountID)
+}
+
+// RevokeAll implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) RevokeAll(ctx context.Context, tx pkg.Tx, accountID int64) error {
+	query := `UPDATE personal_access_tokens SET revoked_at = CURRENT_TIMESTAMP
+		WHERE account_id = ? AND revoked_at IS NULL`
+
+	return tx.Exec(ctx, query, accountID)
+}
+
+// MarkUsed implements PersonalAccessTokenRepository.
+func (p *personalAccessTokenRepository) MarkUsed(ctx context.Context, id int64, at time.Time) error {
+	query := `UPDATE personal_access_tokens SET last_used_at = ?
+		WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ?)`
+
+	_, err := p.db.ExecWithResult(ctx, query, at, id, at.Add(-time.Minute))
+
+	return err
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type personalAccessTokenConfig struct {
+	testConfig
+	repo PersonalAccessTokenRepository
+}
+
+func SetupPersonalAccessTokenConfig(t *testing.T) *personalAccessTokenConfig {
+	testConf := SetupTest(t)
+
+	return &personalAccessTokenConfig{
+		testConfig: *testConf,
+		repo:       NewPersonalAccessTokenRepository(testConf.db),
+	}
+}
+
+func personalAccessTokenScanArgs() []any {
+	args := make([]any, 10)
+	for i := range args {
+		args[i] = gomock.Any()
+	}
+
+	return args
+}
+
+func fillPersonalAccessToken(args []interface{}, token *entities.PersonalAccessToken, scopes string) {
+	*args[0].(*int64) = token.ID
+	*args[1].(*int64) = token.AccountID
+	*args[2].(*string) = token.Name
+	*args[3].(*string) = token.TokenPrefix
+	*args[4].(*string) = token.TokenHash
+	*args[5].(*string) = scopes
+	*args[6].(*sql.NullTime) = token.ExpiresAt
+	*args[7].(*sql.NullTime) = token.LastUsedAt
+	*args[8].(*sql.NullTime) = token.RevokedAt
+	*args[9].(*time.Time) = token.CreatedAt
+}
+
+func TestCreatePersonalAccessToken(t *testing.T) {
+	token := &entities.PersonalAccessToken{
+		AccountID:   1,
+		Name:        "ci bot",
+		TokenPrefix: "ytbpat_abcdefgh",
+		TokenHash:   "hash",
+		Scopes:      []string{"videos:write", "videos:read"},
+	}
+
+	t.Run("Should save token with joined scopes", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), int64(1), "ci bot", "ytbpat_abcdefgh", "hash", "videos:write,videos:read", sql.NullTime{}).
+			Return(&MockSQLResult{LastInsertID: 7, RowAffected: 1}, nil)
+
+		id, err := cfg.repo.Create(ctx, token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), id)
+	})
+
+	t.Run("Should return error if insert fails", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, expectedErr)
+
+		id, err := cfg.repo.Create(ctx, token)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, id)
+	})
+}
+
+func TestGetPersonalAccessTokenByHash(t *testing.T) {
+	t.Run("Should return token with split scopes", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expected := &entities.PersonalAccessToken{
+			ID:          7,
+			AccountID:   1,
+			Name:        "ci bot",
+			TokenPrefix: "ytbpat_abcdefgh",
+			TokenHash:   "hash",
+			Scopes:      []string{"videos:write", "notifications:read"},
+			ExpiresAt:   sql.NullTime{Time: time.Now().Add(time.Hour), Valid: true},
+			CreatedAt:   time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "hash").Return(cfg.row)
+		cfg.row.EXPECT().Scan(personalAccessTokenScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillPersonalAccessToken(args, expected, "videos:write,notifications:read")
+			return nil
+		})
+
+		token := cfg.repo.GetByHash(ctx, "hash")
+
+		assert.Equal(t, expected, token)
+	})
+
+	t.Run("Should return nil if not found", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "hash").Return(cfg.row)
+		cfg.row.EXPECT().Scan(personalAccessTokenScanArgs()...).Return(sql.ErrNoRows)
+
+		assert.Nil(t, cfg.repo.GetByHash(ctx, "hash"))
+	})
+}
+
+func TestGetPersonalAccessTokens(t *testing.T) {
+	t.Run("Should return tokens of account", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedTokens := []*entities.PersonalAccessToken{
+			{ID: 8, AccountID: 1, Name: "chat bot", Scopes: []string{"notifications:read"}},
+			{ID: 7, AccountID: 1, Name: "ci bot", Scopes: []string{"videos:write"}},
+		}
+		remaining := append([]*entities.PersonalAccessToken{}, expectedTokens...)
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedTokens))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(personalAccessTokenScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillPersonalAccessToken(args, remaining[0], remaining[0].Scopes[0])
+			remaining = remaining[1:]
+			return nil
+		}).Times(len(expectedTokens))
+		cfg.rows.EXPECT().Close().Times(1)
+
+		tokens, err := cfg.repo.GetTokens(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTokens, tokens)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(nil, expectedErr)
+
+		tokens, err := cfg.repo.GetTokens(ctx, 1)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, tokens)
+	})
+}
+
+func TestRevokePersonalAccessToken(t *testing.T) {
+	t.Run("Should revoke token of account", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(7), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.Revoke(ctx, 1, 7))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if token is not found or already revoked", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(7), int64(2)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Revoke(ctx, 2, 7), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestRevokeAllPersonalAccessTokens(t *testing.T) {
+	t.Run("Should revoke every token of account", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.tx.EXPECT().Exec(ctx, gomock.Regex("revoked_at IS NULL"), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.RevokeAll(ctx, cfg.tx, 1))
+	})
+
+	t.Run("Should return error if update fails", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(expectedErr)
+
+		assert.Equal(t, expectedErr, cfg.repo.RevokeAll(ctx, cfg.tx, 1))
+	})
+}
+
+func TestMarkPersonalAccessTokenUsed(t *testing.T) {
+	t.Run("Should skip update within a minute of last use", func(t *testing.T) {
+		cfg := SetupPersonalAccessTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		now := time.Now()
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), now, int64(7), now.Add(-time.Minute)).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		assert.NoError(t, cfg.repo.MarkUsed(ctx, 7, now))
+	})
+}
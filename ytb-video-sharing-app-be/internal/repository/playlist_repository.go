@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type PlaylistRepository interface {
+	// Create save playlist, returning its id.
+	Create(ctx context.Context, payload *entities.Playlist) (int64, error)
+
+	// GetPlaylist get playlist with its video count, nil when it does not exist or its owner is deleted.
+	GetPlaylist(ctx context.Context, id int64) *entities.Playlist
+
+	// GetPlaylistForUpdate lock playlist until tx ends, so changes to its videos are applied one at a time.
+	GetPlaylistForUpdate(ctx context.Context, tx pkg.Tx, id int64) *entities.Playlist
+
+	// GetPlaylistsByAccount get playlists of account newest first, only public ones unless includeAll, and how many there are.
+	GetPlaylistsByAccount(ctx context.Context, accountID int64, includeAll bool, page, limit int) ([]*entities.Playlist, int, error)
+
+	// Update change name and visibility of playlist, ErrNoRowsAffected when nothing changed.
+	Update(ctx context.Context, id int64, name string, visibility entities.PlaylistVisibility) error
+
+	// Delete delete playlist together with its items, ErrNoRowsAffected when it does not exist.
+	Delete(ctx context.Context, id int64) error
+
+	// CountVideos count videos of playlist.
+	CountVideos(ctx context.Context, tx pkg.Tx, playlistID int64) (int, error)
+
+	// GetVideoPosition get position of video in playlist, sql.ErrNoRows when it is not in the playlist.
+	GetVideoPosition(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64) (int, error)
+
+	// AddVideo put video at position, which must be right after the last one.
+	AddVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, position int) error
+
+	// RemoveVideo take video at position out of playlist and close the gap it leaves.
+	RemoveVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, position int) error
+
+	// MoveVideo move video from position from to position to, shifting the videos in between.
+	MoveVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, from int, to int) error
+
+	// Touch mark playlist as updated now, its videos changed.
+	Touch(ctx context.Context, tx pkg.Tx, id int64) error
+
+	// GetItems get videos of playlist in order, and how many there are.
+	GetItems(ctx context.Context, playlistID int64, page, limit int) ([]*entities.PlaylistItem, int, error)
+
+	BeginTransaction(ctx context.Context) (pkg.Tx, error)
+}
+
+type playlistRepository struct {
+	db pkg.Database
+}
+
+func NewPlaylistRepository(db pkg.Database) PlaylistRepository {
+	return &playlistRepository{
+		db: db,
+	}
+}
+
+const playlistColumns = `p.id, p.account_id, p.name, p.visibility, p.created_at, p.updated_at,
+	(SELECT COUNT(*) FROM playlist_videos pv WHERE pv.playlist_id = p.id)`
+
+func scanPlaylist(row pkg.Row) (*entities.Playlist, error) {
+	playlist := new(entities.Playlist)
+
+	if err := row.Scan(&playlist.ID, &playlist.AccountID, &playlist.Name, &playlist.Visibility,
+		&playlist.CreatedAt, &playlist.UpdatedAt, &playlist.VideoCount); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// Create implements PlaylistRepository.
+func (p *playlistRepository) Create(ctx context.Context, payload *entities.Playlist) (int64, error) {
+	query := `INSERT INTO playlists (account_id, name, visibility) VALUES (?, ?, ?)`
+
+	rs, err := p.db.ExecWithResult(ctx, query, payload.AccountID, payload.Name, payload.Visibility)
+
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertId, err := rs.LastInsertId()
+
+	if err != nil || lastInsertId == 0 {
+		return 0, errors.New("db execution failed")
+	}
+
+	return lastInsertId, nil
+}
+
+// GetPlaylist implements PlaylistRepository.
+func (p *playlistRepository) GetPlaylist(ctx context.Context, id int64) *entities.Playlist {
+	query := `SELECT ` + playlistColumns + ` FROM playlists p
+	JOIN accounts a ON p.account_id = a.id
+	WHERE p.id = ? AND a.deleted_at IS NULL`
+
+	playlist, err := scanPlaylist(p.db.QueryRow(ctx, query, id))
+
+	if err != nil {
+		return nil
+	}
+
+	return playlist
+}
+
+// GetPlaylistForUpdate implements PlaylistRepository.
+func (p *playlistRepository) GetPlaylistForUpdate(ctx context.Context, tx pkg.Tx, id int64) *entities.Playlist {
+	query := `SELECT ` + playlistColumns + ` FROM playlists p
+	JOIN accounts a ON p.account_id = a.id
+	WHERE p.id = ? AND a.deleted_at IS NULL FOR UPDATE`
+
+	playlist, err := scanPlaylist(tx.QueryRow(ctx, query, id))
+
+	if err != nil {
+		return nil
+	}
+
+	return playlist
+}
+
+// GetPlaylistsByAccount implements PlaylistRepository.
+func (p *playlistRepository) GetPlaylistsByAccount(ctx context.Context, accountID int64, includeAll bool, page, limit int) ([]*entities.Playlist, int, error) {
+	where := ` WHERE p.account_id = ? AND (? OR p.visibility = ?)`
+	args := []any{accountID, includeAll, entities.PlaylistPublic}
+
+	var totalItems int
+	if err := p.db.QueryRow(ctx, `SELECT COUNT(*) FROM playlists p`+where, args...).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + playlistColumns + ` FROM playlists p` + where + ` ORDER BY p.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := p.db.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var playlists []*entities.Playlist
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		playlists = append(playlists, playlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return playlists, totalItems, nil
+}
+
+// Update implements PlaylistRepository.
+func (p *playlistRepository) Update(ctx context.Context, id int64, name string, visibility entities.PlaylistVisibility) error {
+	query := `UPDATE playlists SET name = ?, visibility = ? WHERE id = ?`
+
+	return p.db.Exec(ctx, query, name, visibility, id)
+}
+
+// Delete implements PlaylistRepository.
+func (p *playlistRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM playlists WHERE id = ?`
+
+	return p.db.Exec(ctx, query, id)
+}
+
+// CountVideos implements PlaylistRepository.
+func (p *playlistRepository) CountVideos(ctx context.Context, tx pkg.Tx, playlistID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM playlist_videos WHERE playlist_id = ?`
+
+	var count int
+	if err := tx.QueryRow(ctx, query, playlistID).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetVideoPosition implements PlaylistRepository.
+func (p *playlistRepository) GetVideoPosition(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64) (int, error) {
+	query := `SELECT position FROM playlist_videos WHERE playlist_id = ? AND video_id = ?`
+
+	var position int
+	if err := tx.QueryRow(ctx, query, playlistID, videoID).Scan(&position); err != nil {
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// AddVideo implements PlaylistRepository.
+func (p *playlistRepository) AddVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, position int) error {
+	query := `INSERT INTO playlist_videos (playlist_id, video_id, position) VALUES (?, ?, ?)`
+
+	return tx.Exec(ctx, query, playlistID, videoID, position)
+}
+
+// RemoveVideo implements PlaylistRepository.
+func (p *playlistRepository) RemoveVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, position int) error {
+	if err := tx.Exec(ctx, `DELETE FROM playlist_videos WHERE playlist_id = ? AND video_id = ?`, playlistID, videoID); err != nil {
+		return err
+	}
+
+	query := `UPDATE playlist_videos SET position = position - 1 WHERE playlist_id = ? AND position > ?`
+
+	return tx.Exec(ctx, query, playlistID, position)
+}
+
+// MoveVideo implements PlaylistRepository.
+func (p *playlistRepository) MoveVideo(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64, from int, to int) error {
+	if from == to {
+		return nil
+	}
+
+	// videos between the two positions make room on one side and take the freed position on the other
+	shift := `UPDATE playlist_videos SET position = position + 1 WHERE playlist_id = ? AND position >= ? AND position < ?`
+	args := []any{playlistID, to, from}
+
+	if to > from {
+		shift = `UPDATE playlist_videos SET position = position - 1 WHERE playlist_id = ? AND position > ? AND position <= ?`
+		args = []any{playlistID, from, to}
+	}
+
+	if err := tx.Exec(ctx, shift, args...); err != nil {
+		return err
+	}
+
+	query := `UPDATE playlist_videos SET position = ? WHERE playlist_id = ? AND video_id = ?`
+
+	return tx.Exec(ctx, query, to, playlistID, videoID)
+}
+
+// Touch implements PlaylistRepository.
+func (p *playlistRepository) Touch(ctx context.Context, tx pkg.Tx, id int64) error {
+	return tx.Exec(ctx, `UPDATE playlists SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, id)
+}
+
+// GetItems implements PlaylistRepository.
+func (p *playlistRepository) GetItems(ctx context.Context, playlistID int64, page, limit int) ([]*entities.PlaylistItem, int, error) {
+	var totalItems int
+	if err := p.db.QueryRow(ctx, `SELECT COUNT(*) FROM playlist_videos WHERE playlist_id = ?`, playlistID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT video_id, position, added_at FROM playlist_videos WHERE playlist_id = ? ORDER BY position ASC LIMIT ? OFFSET ?`
+
+	rows, err := p.db.Query(ctx, query, playlistID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var items []*entities.PlaylistItem
+	for rows.Next() {
+		item := &entities.PlaylistItem{}
+		if err := rows.Scan(&item.VideoID, &item.Position, &item.AddedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return items, totalItems, nil
+}
+
+// BeginTransaction implements PlaylistRepository.
+func (p *playlistRepository) BeginTransaction(ctx context.Context) (pkg.Tx, error) {
+	return p.db.Begin(ctx)
+}
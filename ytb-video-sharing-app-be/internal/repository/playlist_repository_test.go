@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type playlistConfig struct {
+	testConfig
+	repo PlaylistRepository
+}
+
+func SetupPlaylistConfig(t *testing.T) *playlistConfig {
+	testConf := SetupTest(t)
+
+	return &playlistConfig{
+		testConfig: *testConf,
+		repo:       NewPlaylistRepository(testConf.db),
+	}
+}
+
+func TestCreatePlaylist(t *testing.T) {
+	t.Run("Should create playlist and return its id", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		playlist := &entities.Playlist{AccountID: 1, Name: "Favourites", Visibility: entities.PlaylistPrivate}
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO playlists"), int64(1), "Favourites", entities.PlaylistPrivate).
+			Return(&MockSQLResult{LastInsertID: 7, RowAffected: 1}, nil)
+
+		id, err := cfg.repo.Create(ctx, playlist)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), id)
+	})
+
+	t.Run("Should return error if insert fails", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		id, err := cfg.repo.Create(ctx, &entities.Playlist{AccountID: 1, Name: "Favourites"})
+
+		assert.Equal(t, expectedErr, err)
+		assert.Equal(t, int64(0), id)
+	})
+}
+
+func TestGetPlaylist(t *testing.T) {
+	t.Run("Should return playlist with its video count", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(7)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 7
+			*args[1].(*int64) = 1
+			*args[2].(*string) = "Favourites"
+			*args[3].(*entities.PlaylistVisibility) = entities.PlaylistPublic
+			*args[6].(*int) = 3
+			return nil
+		})
+
+		playlist := cfg.repo.GetPlaylist(ctx, 7)
+
+		assert.NotNil(t, playlist)
+		assert.Equal(t, "Favourites", playlist.Name)
+		assert.Equal(t, entities.PlaylistPublic, playlist.Visibility)
+		assert.Equal(t, 3, playlist.VideoCount)
+	})
+
+	t.Run("Should return nil if playlist does not exist", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(7)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+		assert.Nil(t, cfg.repo.GetPlaylist(ctx, 7))
+	})
+}
+
+func TestGetPlaylistsByAccount(t *testing.T) {
+	t.Run("Should only count and list public playlists for other viewers", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), false, entities.PlaylistPublic).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), false, entities.PlaylistPublic, 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 7
+			*args[3].(*entities.PlaylistVisibility) = entities.PlaylistPublic
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		playlists, total, err := cfg.repo.GetPlaylistsByAccount(ctx, 1, false, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, playlists, 1)
+	})
+
+	t.Run("Should return error if count query fails", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), true, entities.PlaylistPublic).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		playlists, total, err := cfg.repo.GetPlaylistsByAccount(ctx, 1, true, 1, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, playlists)
+		assert.Equal(t, 0, total)
+	})
+}
+
+func TestRemovePlaylistVideo(t *testing.T) {
+	t.Run("Should delete video and move up the videos after it", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("DELETE FROM playlist_videos"), int64(7), int64(3)).Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("position = position - 1 .* position > \\?"), int64(7), 2).Return(nil),
+		)
+
+		assert.NoError(t, cfg.repo.RemoveVideo(ctx, cfg.tx, 7, 3, 2))
+	})
+
+	t.Run("Should not shift positions if delete fails", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), int64(7), int64(3)).Return(expectedErr)
+
+		assert.Equal(t, expectedErr, cfg.repo.RemoveVideo(ctx, cfg.tx, 7, 3, 2))
+	})
+}
+
+func TestMovePlaylistVideo(t *testing.T) {
+	t.Run("Should do nothing if position does not change", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+
+		assert.NoError(t, cfg.repo.MoveVideo(context.Background(), cfg.tx, 7, 3, 2, 2))
+	})
+
+	t.Run("Should push videos down when moving video up", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("position = position \\+ 1"), int64(7), 1, 4).Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("SET position = \\? WHERE"), 1, int64(7), int64(3)).Return(nil),
+		)
+
+		assert.NoError(t, cfg.repo.MoveVideo(ctx, cfg.tx, 7, 3, 4, 1))
+	})
+
+	t.Run("Should pull videos up when moving video down", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("position = position - 1"), int64(7), 1, 4).Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("SET position = \\? WHERE"), 4, int64(7), int64(3)).Return(nil),
+		)
+
+		assert.NoError(t, cfg.repo.MoveVideo(ctx, cfg.tx, 7, 3, 1, 4))
+	})
+}
+
+func TestGetPlaylistItems(t *testing.T) {
+	t.Run("Should return items in order and total items", func(t *testing.T) {
+		cfg := SetupPlaylistConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(7)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 3
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("ORDER BY position ASC"), int64(7), 2, 2).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 5
+			*args[1].(*int) = 3
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		items, total, err := cfg.repo.GetItems(ctx, 7, 2, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.Len(t, items, 1)
+		assert.Equal(t, int64(5), items[0].VideoID)
+		assert.Equal(t, 3, items[0].Position)
+	})
+}
@@ -3,7 +3,6 @@ package repository
 
 import (
 	"context"
-	"time"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/pkg"
 )
@@ -12,14 +11,32 @@ type RefreshTokenRepository interface {
 	// Save refresh token.
 	Save(ctx context.Context, tx pkg.Tx, payload *entities.RefreshToken) error
 
-	// GetRefreshToken Get refresh token.
-	GetRefreshToken(ctx context.Context, accountID int64, refreshTokenStr string) *entities.RefreshToken
+	// GetRefreshToken Get refresh token by its hash.
+	GetRefreshToken(ctx context.Context, accountID int64, tokenHash string) *entities.RefreshToken
 
-	// DeleteRefreshToken Delete refresh token.
-	DeleteRefreshToken(ctx context.Context, accountID int64, refreshToken string) error
+	// GetRefreshTokenForUpdate get refresh token by its hash and lock the row within transaction.
+	GetRefreshTokenForUpdate(ctx context.Context, tx pkg.Tx, accountID int64, tokenHash string) *entities.RefreshToken
 
-	// Update update refresh token
-	Update(ctx context.Context, tx pkg.Tx, id int64, newToken string, expiresAt time.Time) error
+	// DeleteRefreshToken Delete refresh token together with the rotated tokens of its family.
+	DeleteRefreshToken(ctx context.Context, accountID int64, tokenHash string) error
+
+	// MarkUsed mark refresh token as rotated, it is kept to detect reuse.
+	MarkUsed(ctx context.Context, tx pkg.Tx, id int64) error
+
+	// DeleteFamily delete every refresh token of the family.
+	DeleteFamily(ctx context.Context, familyID string) error
+
+	// GetActiveSessions get the current token of every session (family) of account that is not expired.
+	GetActiveSessions(ctx context.Context, accountID int64) ([]*entities.RefreshToken, error)
+
+	// DeleteSession delete every refresh token of the session (family) owned by account.
+	DeleteSession(ctx context.Context, accountID int64, familyID string) error
+
+	// DeleteOtherRefreshTokens delete every refresh token of account except the kept one within transaction.
+	DeleteOtherRefreshTokens(ctx context.Context, tx pkg.Tx, accountID int64, keepTokenHash string) error
+
+	// DeleteAllRefreshTokens delete every refresh token of account within transaction.
+	DeleteAllRefreshTokens(ctx context.Context, tx pkg.Tx, accountID int64) error
 }
 
 type refreshTokenRepository struct {
@@ -32,16 +49,41 @@ func NewRefreshTokenRepository(db pkg.Database) RefreshTokenRepository {
 	}
 }
 
-// GetRefreshToken implements RefreshTokenRepository.
-func (r *refreshTokenRepository) GetRefreshToken(ctx context.Context, accountID int64, refreshTokenStr string) *entities.RefreshToken {
-	query := `SELECT * FROM refresh_token WHERE account_id = ? AND token = ?`
+const refreshTokenColumns = "id, account_id, token_hash, family_id, device_label, user_agent, ip_address, expires_at, used_at, last_used_at, created_at, updated_at"
 
+func scanRefreshToken(row pkg.Row) (*entities.RefreshToken, error) {
 	refreshToken := new(entities.RefreshToken)
-	row := r.db.QueryRow(ctx, query, accountID, refreshTokenStr)
 
 	if err := row.Scan(&refreshToken.ID, &refreshToken.AccountID,
-		&refreshToken.Token, &refreshToken.ExpiresAt,
-		&refreshToken.CreatedAt, &refreshToken.UpdatedAt); err != nil {
+		&refreshToken.TokenHash, &refreshToken.FamilyID, &refreshToken.DeviceLabel,
+		&refreshToken.UserAgent, &refreshToken.IPAddress, &refreshToken.ExpiresAt,
+		&refreshToken.UsedAt, &refreshToken.LastUsedAt, &refreshToken.CreatedAt, &refreshToken.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	return refreshToken, nil
+}
+
+// GetRefreshToken implements RefreshTokenRepository.
+func (r *refreshTokenRepository) GetRefreshToken(ctx context.Context, accountID int64, tokenHash string) *entities.RefreshToken {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_token WHERE account_id = ? AND token_hash = ?`
+
+	refreshToken, err := scanRefreshToken(r.db.QueryRow(ctx, query, accountID, tokenHash))
+
+	if err != nil {
+		return nil
+	}
+
+	return refreshToken
+}
+
+// GetRefreshTokenForUpdate implements RefreshTokenRepository.
+func (r *refreshTokenRepository) GetRefreshTokenForUpdate(ctx context.Context, tx pkg.Tx, accountID int64, tokenHash string) *entities.RefreshToken {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_token WHERE account_id = ? AND token_hash = ? FOR UPDATE`
+
+	refreshToken, err := scanRefreshToken(tx.QueryRow(ctx, query, accountID, tokenHash))
+
+	if err != nil {
 		return nil
 	}
 
@@ -50,22 +92,82 @@ func (r *refreshTokenRepository) GetRefreshToken(ctx context.Context, accountID
 
 // Save implements RefreshTokenRepository.
 func (r *refreshTokenRepository) Save(ctx context.Context, tx pkg.Tx, payload *entities.RefreshToken) error {
-	query := `INSERT INTO refresh_token (account_id, token, expires_at)
-				VALUES(?, ?, ?)`
+	query := `INSERT INTO refresh_token (account_id, token_hash, family_id, device_label, user_agent, ip_address, expires_at)
+				VALUES(?, ?, ?, ?, ?, ?, ?)`
 
-	return tx.Exec(ctx, query, payload.AccountID, payload.Token, payload.ExpiresAt)
+	return tx.Exec(ctx, query, payload.AccountID, payload.TokenHash, payload.FamilyID,
+		payload.DeviceLabel, payload.UserAgent, payload.IPAddress, payload.ExpiresAt)
 }
 
 // DeleteRefreshToken implements RefreshTokenRepository
-func (r *refreshTokenRepository) DeleteRefreshToken(ctx context.Context, accountID int64, refreshToken string) error {
-	query := "DELETE FROM refresh_token WHERE account_id = ? AND token = ?"
+func (r *refreshTokenRepository) DeleteRefreshToken(ctx context.Context, accountID int64, tokenHash string) error {
+	// a rotated token can not be used to log out
+	query := `DELETE rt FROM refresh_token rt
+		JOIN refresh_token cur ON cur.family_id = rt.family_id
+		WHERE cur.account_id = ? AND cur.token_hash = ? AND cur.used_at IS NULL`
+
+	return r.db.Exec(ctx, query, accountID, tokenHash)
+}
+
+// MarkUsed implements RefreshTokenRepository
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, tx pkg.Tx, id int64) error {
+	query := `UPDATE refresh_token SET used_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	return tx.Exec(ctx, query, id)
+}
+
+// DeleteFamily implements RefreshTokenRepository
+func (r *refreshTokenRepository) DeleteFamily(ctx context.Context, familyID string) error {
+	query := `DELETE FROM refresh_token WHERE family_id = ?`
+
+	return r.db.Exec(ctx, query, familyID)
+}
+
+// DeleteOtherRefreshTokens implements RefreshTokenRepository
+func (r *refreshTokenRepository) DeleteOtherRefreshTokens(ctx context.Context, tx pkg.Tx, accountID int64, keepTokenHash string) error {
+	query := `DELETE FROM refresh_token WHERE account_id = ? AND token_hash <> ?`
+
+	return tx.Exec(ctx, query, accountID, keepTokenHash)
+}
+
+// DeleteAllRefreshTokens implements RefreshTokenRepository
+func (r *refreshTokenRepository) DeleteAllRefreshTokens(ctx context.Context, tx pkg.Tx, accountID int64) error {
+	query := `DELETE FROM refresh_token WHERE account_id = ?`
+
+	return tx.Exec(ctx, query, accountID)
+}
+
+// GetActiveSessions implements RefreshTokenRepository
+func (r *refreshTokenRepository) GetActiveSessions(ctx context.Context, accountID int64) ([]*entities.RefreshToken, error) {
+	query := `SELECT ` + refreshTokenColumns + ` FROM refresh_token
+		WHERE account_id = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_used_at DESC`
+
+	rows, err := r.db.Query(ctx, query, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entities.RefreshToken
+	for rows.Next() {
+		session, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return r.db.Exec(ctx, query, accountID, refreshToken)
+	return sessions, nil
 }
 
-// Update implements RefreshTokenRepository
-func (r *refreshTokenRepository) Update(ctx context.Context, tx pkg.Tx, id int64, newToken string, expiresAt time.Time) error {
-	query := `UPDATE refresh_token SET expires_at = ?, token = ? WHERE id = ?`
+// DeleteSession implements RefreshTokenRepository
+func (r *refreshTokenRepository) DeleteSession(ctx context.Context, accountID int64, familyID string) error {
+	query := `DELETE FROM refresh_token WHERE account_id = ? AND family_id = ?`
 
-	return tx.Exec(ctx, query, expiresAt, newToken, id)
+	return r.db.Exec(ctx, query, accountID, familyID)
 }
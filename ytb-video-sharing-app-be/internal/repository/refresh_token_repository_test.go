@@ -2,14 +2,40 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"testing"
 	"time"
 	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
 )
 
+func refreshTokenScanArgs() []any {
+	args := make([]any, 12)
+	for i := range args {
+		args[i] = gomock.Any()
+	}
+
+	return args
+}
+
+func fillRefreshToken(args []interface{}, refreshToken *entities.RefreshToken) {
+	*args[0].(*int64) = refreshToken.ID
+	*args[1].(*int64) = refreshToken.AccountID
+	*args[2].(*string) = refreshToken.TokenHash
+	*args[3].(*string) = refreshToken.FamilyID
+	*args[4].(*string) = refreshToken.DeviceLabel
+	*args[5].(*string) = refreshToken.UserAgent
+	*args[6].(*string) = refreshToken.IPAddress
+	*args[7].(*time.Time) = refreshToken.ExpiresAt
+	*args[8].(*sql.NullTime) = refreshToken.UsedAt
+	*args[9].(*time.Time) = refreshToken.LastUsedAt
+	*args[10].(*time.Time) = refreshToken.CreatedAt
+	*args[11].(*time.Time) = refreshToken.UpdatedAt
+}
+
 type refreshTokenConfig struct {
 	testConfig
 	repo RefreshTokenRepository
@@ -32,12 +58,14 @@ func TestSave(t *testing.T) {
 		ctx := context.Background()
 		refreshToken := &entities.RefreshToken{
 			AccountID: 1,
-			Token:     "test_token",
+			TokenHash: "test_token_hash",
+			FamilyID:  "family",
 			ExpiresAt: time.Now().Add(time.Hour),
 		}
 
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), refreshToken.AccountID, refreshToken.Token, refreshToken.ExpiresAt).
+			Exec(ctx, gomock.Any(), refreshToken.AccountID, refreshToken.TokenHash, refreshToken.FamilyID,
+				refreshToken.DeviceLabel, refreshToken.UserAgent, refreshToken.IPAddress, refreshToken.ExpiresAt).
 			Return(nil)
 
 		err := cfg.repo.Save(ctx, cfg.tx, refreshToken)
@@ -53,7 +81,7 @@ func TestSave(t *testing.T) {
 		err := errors.New("wrong params")
 
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(err)
 
 		err = cfg.repo.Save(ctx, cfg.tx, &entities.RefreshToken{})
@@ -71,7 +99,8 @@ func TestGetRefreshToken(t *testing.T) {
 		expectedToken := &entities.RefreshToken{
 			ID:        1,
 			AccountID: 1,
-			Token:     "test_token",
+			TokenHash: "test_token_hash",
+			FamilyID:  "family",
 			ExpiresAt: time.Now().Add(time.Hour),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -82,18 +111,13 @@ func TestGetRefreshToken(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(refreshTokenScanArgs()...).
 			DoAndReturn(func(args ...interface{}) error {
-				*args[0].(*int64) = expectedToken.ID
-				*args[1].(*int64) = expectedToken.AccountID
-				*args[2].(*string) = expectedToken.Token
-				*args[3].(*time.Time) = expectedToken.ExpiresAt
-				*args[4].(*time.Time) = expectedToken.CreatedAt
-				*args[5].(*time.Time) = expectedToken.UpdatedAt
+				fillRefreshToken(args, expectedToken)
 				return nil
 			})
 
-		rs := cfg.repo.GetRefreshToken(ctx, expectedToken.AccountID, expectedToken.Token)
+		rs := cfg.repo.GetRefreshToken(ctx, expectedToken.AccountID, expectedToken.TokenHash)
 
 		assert.NotNil(t, rs)
 		assert.Equal(t, expectedToken, rs)
@@ -107,10 +131,10 @@ func TestGetRefreshToken(t *testing.T) {
 			Return(cfg.row)
 
 		cfg.row.EXPECT().
-			Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Scan(refreshTokenScanArgs()...).
 			Return(errors.New("sql: no rows in result set"))
 
-		rs := cfg.repo.GetRefreshToken(ctx, 1, "test_token")
+		rs := cfg.repo.GetRefreshToken(ctx, 1, "test_token_hash")
 
 		assert.Nil(t, rs)
 	})
@@ -121,9 +145,9 @@ func TestGetRefreshToken(t *testing.T) {
 
 		ctx := context.Background()
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(cfg.row)
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("scan error"))
+		cfg.row.EXPECT().Scan(refreshTokenScanArgs()...).Return(errors.New("scan error"))
 
-		result := cfg.repo.GetRefreshToken(ctx, 1, "test_token")
+		result := cfg.repo.GetRefreshToken(ctx, 1, "test_token_hash")
 		assert.Nil(t, result)
 	})
 }
@@ -138,7 +162,7 @@ func TestDeleteRefreshToken(t *testing.T) {
 			Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
 
-		err := cfg.repo.DeleteRefreshToken(ctx, 1, "test_token")
+		err := cfg.repo.DeleteRefreshToken(ctx, 1, "test_token_hash")
 
 		assert.NoError(t, err)
 	})
@@ -153,7 +177,7 @@ func TestDeleteRefreshToken(t *testing.T) {
 			Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(expectedErr)
 
-		err := cfg.repo.DeleteRefreshToken(ctx, 1, "wrong_token")
+		err := cfg.repo.DeleteRefreshToken(ctx, 1, "wrong_token_hash")
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 	})
@@ -168,68 +192,235 @@ func TestDeleteRefreshToken(t *testing.T) {
 			Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(expectedErr)
 
-		err := cfg.repo.DeleteRefreshToken(ctx, 1, "test_token")
+		err := cfg.repo.DeleteRefreshToken(ctx, 1, "test_token_hash")
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 	})
 }
 
-func TestUpdateRefreshToken(t *testing.T) {
-	t.Run("Should update an existing token", func(t *testing.T) {
+func TestGetRefreshTokenForUpdate(t *testing.T) {
+	t.Run("Should return locked token if found", func(t *testing.T) {
 		cfg := SetupRefreshTokenConfig(t)
 		defer cfg.TearDownTest()
+
 		ctx := context.Background()
+		expectedToken := &entities.RefreshToken{
+			ID:        1,
+			AccountID: 1,
+			TokenHash: "test_token_hash",
+			FamilyID:  "family",
+			ExpiresAt: time.Now().Add(time.Hour),
+			UsedAt:    sql.NullTime{Time: time.Now(), Valid: true},
+		}
+
+		cfg.tx.EXPECT().
+			QueryRow(ctx, gomock.Any(), expectedToken.AccountID, expectedToken.TokenHash).
+			Return(cfg.row)
 
-		id := int64(1)
-		newToken := "new_token"
-		expiresAt := time.Now().Add(time.Hour)
+		cfg.row.EXPECT().
+			Scan(refreshTokenScanArgs()...).
+			DoAndReturn(func(args ...interface{}) error {
+				fillRefreshToken(args, expectedToken)
+				return nil
+			})
+
+		rs := cfg.repo.GetRefreshTokenForUpdate(ctx, cfg.tx, expectedToken.AccountID, expectedToken.TokenHash)
+
+		assert.Equal(t, expectedToken, rs)
+	})
+
+	t.Run("Should return nil if token not found", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.tx.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), "test_token_hash").Return(cfg.row)
+		cfg.row.EXPECT().Scan(refreshTokenScanArgs()...).Return(sql.ErrNoRows)
+
+		rs := cfg.repo.GetRefreshTokenForUpdate(ctx, cfg.tx, 1, "test_token_hash")
+
+		assert.Nil(t, rs)
+	})
+}
+
+func TestMarkRefreshTokenUsed(t *testing.T) {
+	t.Run("Should mark token as used", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
 
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), expiresAt, newToken, id).
+			Exec(ctx, gomock.Any(), int64(1)).
 			Return(nil)
 
-		err := cfg.repo.Update(ctx, cfg.tx, id, newToken, expiresAt)
+		err := cfg.repo.MarkUsed(ctx, cfg.tx, 1)
 
 		assert.NoError(t, err)
 	})
 
-	t.Run("Should return error if no rows are affected (account_id or token incorrect)", func(t *testing.T) {
+	t.Run("Should return error if database execution fails", func(t *testing.T) {
 		cfg := SetupRefreshTokenConfig(t)
 		defer cfg.TearDownTest()
 		ctx := context.Background()
 
-		id := int64(999)
-		newToken := "new_token"
-		expiresAt := time.Now().Add(time.Hour)
-		expectedErr := errors.New("no rows affected")
-
+		expectedErr := errors.New("database execution failed")
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), expiresAt, newToken, id).
+			Exec(ctx, gomock.Any(), int64(1)).
 			Return(expectedErr)
 
-		err := cfg.repo.Update(ctx, cfg.tx, id, newToken, expiresAt)
-		assert.Error(t, err)
+		err := cfg.repo.MarkUsed(ctx, cfg.tx, 1)
+
 		assert.Equal(t, expectedErr, err)
 	})
+}
+
+func TestDeleteRefreshTokenFamily(t *testing.T) {
+	t.Run("Should delete every token of family", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().
+			Exec(ctx, gomock.Any(), "family").
+			Return(nil)
+
+		err := cfg.repo.DeleteFamily(ctx, "family")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeleteOtherRefreshTokens(t *testing.T) {
+	t.Run("Should delete every other token of account", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.tx.EXPECT().
+			Exec(ctx, gomock.Any(), int64(1), "current_token_hash").
+			Return(nil)
+
+		err := cfg.repo.DeleteOtherRefreshTokens(ctx, cfg.tx, 1, "current_token_hash")
+
+		assert.NoError(t, err)
+	})
 
 	t.Run("Should return error if database execution fails", func(t *testing.T) {
 		cfg := SetupRefreshTokenConfig(t)
 		defer cfg.TearDownTest()
 		ctx := context.Background()
 
-		id := int64(1)
-		newToken := "new_token"
-		expiresAt := time.Now().Add(time.Hour)
 		expectedErr := errors.New("database execution failed")
-
 		cfg.tx.EXPECT().
-			Exec(ctx, gomock.Any(), expiresAt, newToken, id).
+			Exec(ctx, gomock.Any(), int64(1), "current_token_hash").
 			Return(expectedErr)
 
-		err := cfg.repo.Update(ctx, cfg.tx, id, newToken, expiresAt)
+		err := cfg.repo.DeleteOtherRefreshTokens(ctx, cfg.tx, 1, "current_token_hash")
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestDeleteAllRefreshTokens(t *testing.T) {
+	t.Run("Should delete every token of account", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.tx.EXPECT().
+			Exec(ctx, gomock.Any(), int64(1)).
+			Return(nil)
+
+		err := cfg.repo.DeleteAllRefreshTokens(ctx, cfg.tx, 1)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetActiveSessions(t *testing.T) {
+	t.Run("Should return current token of every session", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedSessions := []*entities.RefreshToken{
+			{ID: 3, AccountID: 1, FamilyID: "family-1", DeviceLabel: "Chrome on Windows", IPAddress: "10.0.0.1"},
+			{ID: 5, AccountID: 1, FamilyID: "family-2", DeviceLabel: "Safari on iOS", IPAddress: "10.0.0.2"},
+		}
+		remaining := append([]*entities.RefreshToken{}, expectedSessions...)
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedSessions))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(refreshTokenScanArgs()...).DoAndReturn(func(args ...interface{}) error {
+			fillRefreshToken(args, remaining[0])
+			remaining = remaining[1:]
+			return nil
+		}).Times(len(expectedSessions))
+		cfg.rows.EXPECT().Close().Times(1)
+
+		sessions, err := cfg.repo.GetActiveSessions(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedSessions, sessions)
+	})
+
+	t.Run("Should return error if iteration is interrupted", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("connection reset")
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(expectedErr).Times(1)
+		cfg.rows.EXPECT().Close().Times(1)
+
+		sessions, err := cfg.repo.GetActiveSessions(ctx, 1)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, sessions)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1)).Return(nil, expectedErr)
+
+		sessions, err := cfg.repo.GetActiveSessions(ctx, 1)
 
-		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, sessions)
+	})
+}
+
+func TestDeleteSession(t *testing.T) {
+	t.Run("Should delete every token of session", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "family").Return(nil)
+
+		err := cfg.repo.DeleteSession(ctx, 1, "family")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return error if session does not belong to account", func(t *testing.T) {
+		cfg := SetupRefreshTokenConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1), "family").Return(pkg.ErrNoRowsAffected)
+
+		err := cfg.repo.DeleteSession(ctx, 1, "family")
+
+		assert.ErrorIs(t, err, pkg.ErrNoRowsAffected)
 	})
 }
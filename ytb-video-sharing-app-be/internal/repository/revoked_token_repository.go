@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type RevokedTokenRepository interface {
+	// Save revoked id, a later expiry wins when id is already revoked.
+	Save(ctx context.Context, id string, expiresAt time.Time) error
+
+	// Exists check whether one of the ids is revoked and not expired yet.
+	Exists(ctx context.Context, ids []string) (bool, error)
+
+	// DeleteExpired delete revoked ids that are expired.
+	DeleteExpired(ctx context.Context) error
+}
+
+type revokedTokenRepository struct {
+	db pkg.Database
+}
+
+func NewRevokedTokenRepository(db pkg.Database) RevokedTokenRepository {
+	return &revokedTokenRepository{
+		db: db,
+	}
+}
+
+// Save implements RevokedTokenRepository.
+func (r *revokedTokenRepository) Save(ctx context.Context, id string, expiresAt time.Time) error {
+	query := `INSERT INTO revoked_tokens (id, expires_at) VALUES(?, ?)
+		ON DUPLICATE KEY UPDATE expires_at = GREATEST(expires_at, VALUES(expires_at))`
+
+	// revoking twice affects no rows, which is not an error here
+	_, err := r.db.ExecWithResult(ctx, query, id, expiresAt)
+
+	return err
+}
+
+// Exists implements RevokedTokenRepository.
+func (r *revokedTokenRepository) Exists(ctx context.Context, ids []string) (bool, error) {
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens
+		WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `) AND expires_at > CURRENT_TIMESTAMP)`
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// DeleteExpired implements RevokedTokenRepository.
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= CURRENT_TIMESTAMP`
+
+	_, err := r.db.ExecWithResult(ctx, query)
+
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type revokedTokenConfig struct {
+	testConfig
+	repo RevokedTokenRepository
+}
+
+func SetupRevokedTokenConfig(t *testing.T) *revokedTokenConfig {
+	testConf := SetupTest(t)
+
+	return &revokedTokenConfig{
+		testConfig: *testConf,
+		repo:       NewRevokedTokenRepository(testConf.db),
+	}
+}
+
+func TestSaveRevokedToken(t *testing.T) {
+	t.Run("Should save revoked id", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expiresAt := time.Now().Add(time.Hour)
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "jti:1", expiresAt).
+			Return(&MockSQLResult{RowAffected: 1}, nil)
+
+		err := cfg.repo.Save(ctx, "jti:1", expiresAt)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should not fail if id is already revoked", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expiresAt := time.Now().Add(time.Hour)
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any(), "jti:1", expiresAt).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		err := cfg.repo.Save(ctx, "jti:1", expiresAt)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestRevokedTokenExists(t *testing.T) {
+	t.Run("Should return true if one of ids is revoked", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "jti:1", "sid:1").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*bool) = true
+			return nil
+		})
+
+		exists, err := cfg.repo.Exists(ctx, []string{"jti:1", "sid:1"})
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Should not query if there is no id", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		exists, err := cfg.repo.Exists(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "jti:1").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		exists, err := cfg.repo.Exists(ctx, []string{"jti:1"})
+
+		assert.Equal(t, expectedErr, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestDeleteExpiredRevokedTokens(t *testing.T) {
+	t.Run("Should delete expired ids", func(t *testing.T) {
+		cfg := SetupRevokedTokenConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().
+			ExecWithResult(ctx, gomock.Any()).
+			Return(&MockSQLResult{RowAffected: 3}, nil)
+
+		err := cfg.repo.DeleteExpired(ctx)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type TagRepository interface {
+	// AddVideoTags tag video with names in transaction, creating tags that do not exist yet. Names must be normalized already.
+	AddVideoTags(ctx context.Context, tx pkg.Tx, videoID int64, names []string) error
+
+	// GetTagsByVideos get tag names of videos by video id, in alphabetical order.
+	GetTagsByVideos(ctx context.Context, videoIDs []int64) (map[int64][]string, error)
+
+	// ListTags get tags starting with prefix carried by at least one visible video, the most used first.
+	ListTags(ctx context.Context, prefix string, limit int) ([]*entities.TagUsage, error)
+}
+
+type tagRepository struct {
+	db pkg.Database
+}
+
+func NewTagRepository(db pkg.Database) TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+// AddVideoTags implements TagRepository.
+func (t *tagRepository) AddVideoTags(ctx context.Context, tx pkg.Tx, videoID int64, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(names)+1)
+	for _, name := range names {
+		args = append(args, name)
+	}
+
+	// tags used before are left as they are
+	query := `INSERT IGNORE INTO tags (name) VALUES (?)` + strings.Repeat(", (?)", len(names)-1)
+
+	if err := tx.Exec(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query = `INSERT IGNORE INTO video_tags (video_id, tag_id)
+		SELECT ?, id FROM tags WHERE name IN (?` + strings.Repeat(", ?", len(names)-1) + `)`
+
+	return tx.Exec(ctx, query, append([]any{videoID}, args...)...)
+}
+
+// GetTagsByVideos implements TagRepository.
+func (t *tagRepository) GetTagsByVideos(ctx context.Context, videoIDs []int64) (map[int64][]string, error) {
+	if len(videoIDs) == 0 {
+		return map[int64][]string{}, nil
+	}
+
+	args := make([]any, 0, len(videoIDs))
+	for _, id := range videoIDs {
+		args = append(args, id)
+	}
+
+	query := `SELECT vt.video_id, t.name FROM video_tags vt
+		JOIN tags t ON t.id = vt.tag_id
+		WHERE vt.video_id IN (?` + strings.Repeat(", ?", len(videoIDs)-1) + `)
+		ORDER BY vt.video_id, t.name`
+
+	rows, err := t.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int64][]string, len(videoIDs))
+	for rows.Next() {
+		var videoID int64
+		var name string
+		if err := rows.Scan(&videoID, &name); err != nil {
+			return nil, err
+		}
+		tags[videoID] = append(tags[videoID], name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// ListTags implements TagRepository.
+func (t *tagRepository) ListTags(ctx context.Context, prefix string, limit int) ([]*entities.TagUsage, error) {
+	query := `SELECT t.name, COUNT(*) AS video_count
+	FROM tags t
+	JOIN video_tags vt ON vt.tag_id = t.id
+	JOIN videos v ON v.id = vt.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE t.name LIKE ? AND v.hidden_at IS NULL AND v.deleted_at IS NULL AND a.deleted_at IS NULL
+	GROUP BY t.id, t.name
+	ORDER BY video_count DESC, t.name ASC LIMIT ?`
+
+	rows, err := t.db.Query(ctx, query, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*entities.TagUsage
+	for rows.Next() {
+		tag := &entities.TagUsage{}
+		if err := rows.Scan(&tag.Name, &tag.VideoCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
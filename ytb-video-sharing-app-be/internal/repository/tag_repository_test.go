@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type tagConfig struct {
+	testConfig
+	repo TagRepository
+}
+
+func SetupTagConfig(t *testing.T) *tagConfig {
+	testConf := SetupTest(t)
+
+	return &tagConfig{
+		testConfig: *testConf,
+		repo:       NewTagRepository(testConf.db),
+	}
+}
+
+func TestAddVideoTags(t *testing.T) {
+	t.Run("Should not query without tags", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+
+		assert.NoError(t, cfg.repo.AddVideoTags(context.Background(), cfg.tx, 3, nil))
+	})
+
+	t.Run("Should create missing tags and link them to video", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("INSERT IGNORE INTO tags"), "music", "live").Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("INSERT IGNORE INTO video_tags"), int64(3), "music", "live").Return(nil),
+		)
+
+		assert.NoError(t, cfg.repo.AddVideoTags(ctx, cfg.tx, 3, []string{"music", "live"}))
+	})
+
+	t.Run("Should return error if creating tags fails", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), "music").Return(expectedErr)
+
+		assert.Equal(t, expectedErr, cfg.repo.AddVideoTags(ctx, cfg.tx, 3, []string{"music"}))
+	})
+}
+
+func TestGetTagsByVideos(t *testing.T) {
+	t.Run("Should not query without videos", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+
+		tags, err := cfg.repo.GetTagsByVideos(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("Should group tag names by video", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		rows := []struct {
+			videoID int64
+			name    string
+		}{{3, "live"}, {3, "music"}, {4, "news"}}
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(3), int64(4), int64(5)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true).Times(len(rows))
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = rows[0].videoID
+			*args[1].(*string) = rows[0].name
+			rows = rows[1:]
+			return nil
+		}).Times(len(rows))
+		cfg.rows.EXPECT().Close()
+
+		tags, err := cfg.repo.GetTagsByVideos(ctx, []int64{3, 4, 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int64][]string{3: {"live", "music"}, 4: {"news"}}, tags)
+	})
+}
+
+func TestListTags(t *testing.T) {
+	t.Run("Should list tags starting with escaped prefix", func(t *testing.T) {
+		cfg := SetupTagConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), `mu\_%`, 10).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*string) = "mu_sic"
+			*args[1].(*int) = 7
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		tags, err := cfg.repo.ListTags(ctx, "mu_", 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.TagUsage{{Name: "mu_sic", VideoCount: 7}}, tags)
+	})
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type VideoCommentRepository interface {
+	// Create save comment, returning its id.
+	Create(ctx context.Context, payload *entities.VideoComment) (int64, error)
+
+	// GetComments get comments of video, the oldest first, and how many there are.
+	// Comments of deleted accounts are left out.
+	GetComments(ctx context.Context, videoID int64, page, limit int) ([]*entities.VideoComment, int, error)
+
+	// Delete remove comment written by account, ErrNoRowsAffected when there is none.
+	Delete(ctx context.Context, accountID, commentID int64) error
+}
+
+type videoCommentRepository struct {
+	db pkg.Database
+}
+
+func NewVideoCommentRepository(db pkg.Database) VideoCommentRepository {
+	return &videoCommentRepository{
+		db: db,
+	}
+}
+
+// Create implements VideoCommentRepository.
+func (v *videoCommentRepository) Create(ctx context.Context, payload *entities.VideoComment) (int64, error) {
+	query := `INSERT INTO video_comments (video_id, account_id, content) VALUES (?, ?, ?)`
+
+	rs, err := v.db.ExecWithResult(ctx, query, payload.VideoID, payload.AccountID, payload.Content)
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertId, err := rs.LastInsertId()
+
+	if err != nil || lastInsertId == 0 {
+		return 0, errors.New("db execution failed")
+	}
+
+	return lastInsertId, nil
+}
+
+// GetComments implements VideoCommentRepository.
+func (v *videoCommentRepository) GetComments(ctx context.Context, videoID int64, page, limit int) ([]*entities.VideoComment, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM video_comments c
+	JOIN accounts a ON c.account_id = a.id
+	WHERE c.video_id = ? AND a.deleted_at IS NULL`
+	if err := v.db.QueryRow(ctx, countQuery, videoID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT c.id, c.video_id, c.account_id, c.content, c.created_at, a.fullname
+	FROM video_comments c
+	JOIN accounts a ON c.account_id = a.id
+	WHERE c.video_id = ? AND a.deleted_at IS NULL
+	ORDER BY c.created_at, c.id LIMIT ? OFFSET ?`
+
+	rows, err := v.db.Query(ctx, query, videoID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var comments []*entities.VideoComment
+	for rows.Next() {
+		comment := &entities.VideoComment{}
+		if err := rows.Scan(&comment.ID, &comment.VideoID, &comment.AccountID, &comment.Content, &comment.CreatedAt, &comment.FullName); err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, totalItems, nil
+}
+
+// Delete implements VideoCommentRepository.
+func (v *videoCommentRepository) Delete(ctx context.Context, accountID, commentID int64) error {
+	query := `DELETE FROM video_comments WHERE id = ? AND account_id = ?`
+
+	return v.db.Exec(ctx, query, commentID, accountID)
+}
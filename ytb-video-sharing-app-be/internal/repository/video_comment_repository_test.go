@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type videoCommentConfig struct {
+	testConfig
+	repo VideoCommentRepository
+}
+
+func SetupVideoCommentConfig(t *testing.T) *videoCommentConfig {
+	testConf := SetupTest(t)
+
+	return &videoCommentConfig{
+		testConfig: *testConf,
+		repo:       NewVideoCommentRepository(testConf.db),
+	}
+}
+
+func TestCreateVideoComment(t *testing.T) {
+	comment := &entities.VideoComment{VideoID: 3, AccountID: 1, Content: "nice one"}
+
+	t.Run("Should save comment and return its id", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO video_comments"), int64(3), int64(1), "nice one").
+			Return(&MockSQLResult{LastInsertID: 9, RowAffected: 1}, nil)
+
+		id, err := cfg.repo.Create(ctx, comment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), id)
+	})
+
+	t.Run("Should return error if insert fails", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		id, err := cfg.repo.Create(ctx, comment)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, id)
+	})
+}
+
+func TestGetVideoComments(t *testing.T) {
+	t.Run("Should return comments of video and total", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expected := &entities.VideoComment{ID: 9, VideoID: 3, AccountID: 1, Content: "nice one", CreatedAt: time.Now(), FullName: "Alice"}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Regex("SELECT COUNT"), int64(3)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("ORDER BY c.created_at, c.id"), int64(3), 10, 10).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = expected.ID
+			*args[1].(*int64) = expected.VideoID
+			*args[2].(*int64) = expected.AccountID
+			*args[3].(*string) = expected.Content
+			*args[4].(*time.Time) = expected.CreatedAt
+			*args[5].(*string) = expected.FullName
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		comments, total, err := cfg.repo.GetComments(ctx, 3, 2, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, []*entities.VideoComment{expected}, comments)
+	})
+
+	t.Run("Should return error if count fails", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(3)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		comments, total, err := cfg.repo.GetComments(ctx, 3, 1, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, total)
+		assert.Nil(t, comments)
+	})
+}
+
+func TestDeleteVideoComment(t *testing.T) {
+	t.Run("Should delete comment of account", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Regex("DELETE FROM video_comments"), int64(9), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.Delete(ctx, 1, 9))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if account did not write comment", func(t *testing.T) {
+		cfg := SetupVideoCommentConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(9), int64(2)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Delete(ctx, 2, 9), pkg.ErrNoRowsAffected)
+	})
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type VideoReportRepository interface {
+	// Create save report, returning its id. ErrDuplicate when the reporter already reported the video.
+	Create(ctx context.Context, payload *entities.VideoReport) (int64, error)
+
+	// GetReport get report by id, nil when it does not exist.
+	GetReport(ctx context.Context, id int64) *entities.VideoReport
+
+	// CountByReporterSince count reports sent by reporter since since.
+	CountByReporterSince(ctx context.Context, reporterID int64, since time.Time) (int, error)
+
+	// CountOpenByVideo count open reports of video, every one is from a different reporter.
+	CountOpenByVideo(ctx context.Context, videoID int64) (int, error)
+
+	// GetReportedVideos get videos with open reports, the most reported first, and how many such videos there are.
+	GetReportedVideos(ctx context.Context, page, limit int) ([]*entities.ReportedVideo, int, error)
+
+	// GetOpenReportsByVideos get open reports of videos, oldest first.
+	GetOpenReportsByVideos(ctx context.Context, videoIDs []int64) ([]*entities.VideoReport, error)
+
+	// Dismiss mark open report as dismissed by moderator, ErrNoRowsAffected when it is not open.
+	Dismiss(ctx context.Context, id int64, moderatorID int64, at time.Time) error
+
+	// ResolveByVideo mark every open report of video with status by moderator, actioned when the video is hidden
+	// and dismissed when it is unhidden, so they no longer count towards hiding it again.
+	ResolveByVideo(ctx context.Context, videoID int64, moderatorID int64, status entities.ReportStatus, at time.Time) error
+}
+
+type videoReportRepository struct {
+	db pkg.Database
+}
+
+func NewVideoReportRepository(db pkg.Database) VideoReportRepository {
+	return &videoReportRepository{
+		db: db,
+	}
+}
+
+const videoReportColumns = "id, video_id, reporter_id, reason, note, status, created_at, resolved_at, resolved_by"
+
+func scanVideoReport(row pkg.Row) (*entities.VideoReport, error) {
+	report := new(entities.VideoReport)
+
+	if err := row.Scan(&report.ID, &report.VideoID, &report.ReporterID, &report.Reason, &report.Note,
+		&report.Status, &report.CreatedAt, &report.ResolvedAt, &report.ResolvedBy); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Create implements VideoReportRepository.
+func (v *videoReportRepository) Create(ctx context.Context, payload *entities.VideoReport) (int64, error) {
+	query := `INSERT INTO video_reports (video_id, reporter_id, reason, note) VALUES (?, ?, ?, ?)`
+
+	rs, err := v.db.ExecWithResult(ctx, query, payload.VideoID, payload.ReporterID, payload.Reason, payload.Note)
+
+	if err != nil {
+		return 0, err
+	}
+
+	lastInsertId, err := rs.LastInsertId()
+
+	if err != nil || lastInsertId == 0 {
+		return 0, errors.New("db execution failed")
+	}
+
+	return lastInsertId, nil
+}
+
+// GetReport implements VideoReportRepository.
+func (v *videoReportRepository) GetReport(ctx context.Context, id int64) *entities.VideoReport {
+	query := `SELECT ` + videoReportColumns + ` FROM video_reports WHERE id = ?`
+
+	report, err := scanVideoReport(v.db.QueryRow(ctx, query, id))
+
+	if err != nil {
+		return nil
+	}
+
+	return report
+}
+
+// CountByReporterSince implements VideoReportRepository.
+func (v *videoReportRepository) CountByReporterSince(ctx context.Context, reporterID int64, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM video_reports WHERE reporter_id = ? AND created_at >= ?`
+
+	var count int
+	if err := v.db.QueryRow(ctx, query, reporterID, since).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountOpenByVideo implements VideoReportRepository.
+func (v *videoReportRepository) CountOpenByVideo(ctx context.Context, videoID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM video_reports WHERE video_id = ? AND status = ?`
+
+	var count int
+	if err := v.db.QueryRow(ctx, query, videoID, entities.ReportStatusOpen).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetReportedVideos implements VideoReportRepository.
+func (v *videoReportRepository) GetReportedVideos(ctx context.Context, page, limit int) ([]*entities.ReportedVideo, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(DISTINCT r.video_id) FROM video_reports r
+	JOIN videos v ON v.id = r.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE r.status = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL`
+	if err := v.db.QueryRow(ctx, countQuery, entities.ReportStatusOpen).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+		r.report_count, r.last_reported_at
+	FROM (
+		SELECT video_id, COUNT(*) AS report_count, MAX(created_at) AS last_reported_at
+		FROM video_reports
+		WHERE status = ?
+		GROUP BY video_id
+	) r
+	JOIN videos v ON v.id = r.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE v.deleted_at IS NULL AND a.deleted_at IS NULL
+	ORDER BY r.report_count DESC, r.last_reported_at DESC LIMIT ? OFFSET ?`
+
+	rows, err := v.db.Query(ctx, query, entities.ReportStatusOpen, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.ReportedVideo
+	for rows.Next() {
+		video := &entities.ReportedVideo{}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl,
+			&video.AccountID, &video.HiddenAt, &video.FullName, &video.ReportCount, &video.LastReportedAt); err != nil {
+			return nil, 0, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return videos, totalItems, nil
+}
+
+// GetOpenReportsByVideos implements VideoReportRepository.
+func (v *videoReportRepository) GetOpenReportsByVideos(ctx context.Context, videoIDs []int64) ([]*entities.VideoReport, error) {
+	if len(videoIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(videoIDs)+1)
+	args = append(args, entities.ReportStatusOpen)
+	for _, id := range videoIDs {
+		args = append(args, id)
+	}
+
+	query := `SELECT ` + videoReportColumns + ` FROM video_reports
+		WHERE status = ? AND video_id IN (?` + strings.Repeat(", ?", len(videoIDs)-1) + `)
+		ORDER BY id ASC`
+
+	rows, err := v.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*entities.VideoReport
+	for rows.Next() {
+		report, err := scanVideoReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// Dismiss implements VideoReportRepository.
+func (v *videoReportRepository) Dismiss(ctx context.Context, id int64, moderatorID int64, at time.Time) error {
+	query := `UPDATE video_reports SET status = ?, resolved_at = ?, resolved_by = ? WHERE id = ? AND status = ?`
+
+	return v.db.Exec(ctx, query, entities.ReportStatusDismissed, at, moderatorID, id, entities.ReportStatusOpen)
+}
+
+// ResolveByVideo implements VideoReportRepository.
+func (v *videoReportRepository) ResolveByVideo(ctx context.Context, videoID int64, moderatorID int64, status entities.ReportStatus, at time.Time) error {
+	query := `UPDATE video_reports SET status = ?, resolved_at = ?, resolved_by = ? WHERE video_id = ? AND status = ?`
+
+	_, err := v.db.ExecWithResult(ctx, query, status, at, moderatorID, videoID, entities.ReportStatusOpen)
+
+	return err
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type videoReportConfig struct {
+	testConfig
+	repo VideoReportRepository
+}
+
+func SetupVideoReportConfig(t *testing.T) *videoReportConfig {
+	testConf := SetupTest(t)
+
+	return &videoReportConfig{
+		testConfig: *testConf,
+		repo:       NewVideoReportRepository(testConf.db),
+	}
+}
+
+func TestCreateVideoReport(t *testing.T) {
+	report := &entities.VideoReport{VideoID: 3, ReporterID: 1, Reason: entities.ReportReasonSpam, Note: "link farm"}
+
+	t.Run("Should save report and return its id", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), int64(3), int64(1), entities.ReportReasonSpam, "link farm").
+			Return(&MockSQLResult{LastInsertID: 9, RowAffected: 1}, nil)
+
+		id, err := cfg.repo.Create(ctx, report)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(9), id)
+	})
+
+	t.Run("Should return ErrDuplicate if reporter already reported the video", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, pkg.ErrDuplicate)
+
+		id, err := cfg.repo.Create(ctx, report)
+
+		assert.ErrorIs(t, err, pkg.ErrDuplicate)
+		assert.Zero(t, id)
+	})
+}
+
+func TestGetVideoReport(t *testing.T) {
+	t.Run("Should return report", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expected := &entities.VideoReport{
+			ID:         9,
+			VideoID:    3,
+			ReporterID: 1,
+			Reason:     entities.ReportReasonBroken,
+			Status:     entities.ReportStatusOpen,
+			CreatedAt:  time.Now(),
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(9)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = expected.ID
+			*args[1].(*int64) = expected.VideoID
+			*args[2].(*int64) = expected.ReporterID
+			*args[3].(*entities.ReportReason) = expected.Reason
+			*args[4].(*string) = expected.Note
+			*args[5].(*entities.ReportStatus) = expected.Status
+			*args[6].(*time.Time) = expected.CreatedAt
+			*args[7].(*sql.NullTime) = expected.ResolvedAt
+			*args[8].(*sql.NullInt64) = expected.ResolvedBy
+			return nil
+		})
+
+		assert.Equal(t, expected, cfg.repo.GetReport(ctx, 9))
+	})
+
+	t.Run("Should return nil if not found", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(9)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(sql.ErrNoRows)
+
+		assert.Nil(t, cfg.repo.GetReport(ctx, 9))
+	})
+}
+
+func TestCountVideoReports(t *testing.T) {
+	t.Run("Should count reports of reporter since time", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+		since := time.Now().Add(-time.Hour)
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), since).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 4
+			return nil
+		})
+
+		count, err := cfg.repo.CountByReporterSince(ctx, 1, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("Should count open reports of video", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(3), entities.ReportStatusOpen).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 5
+			return nil
+		})
+
+		count, err := cfg.repo.CountOpenByVideo(ctx, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+	})
+
+	t.Run("Should return error if count fails", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(3), entities.ReportStatusOpen).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		count, err := cfg.repo.CountOpenByVideo(ctx, 3)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Zero(t, count)
+	})
+}
+
+func TestGetReportedVideos(t *testing.T) {
+	t.Run("Should return reported videos and total items", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		lastReportedAt := time.Now()
+		expected := []*entities.ReportedVideo{
+			{Video: entities.Video{ID: 3, Title: "spam", AccountID: 2, FullName: "User Two"}, ReportCount: 4, LastReportedAt: lastReportedAt},
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), entities.ReportStatusOpen).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), entities.ReportStatusOpen, 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		scanArgs := make([]any, 12)
+		for i := range scanArgs {
+			scanArgs[i] = gomock.Any()
+		}
+		cfg.rows.EXPECT().Scan(scanArgs...).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 3
+			*args[1].(*string) = "spam"
+			*args[7].(*int64) = 2
+			*args[9].(*string) = "User Two"
+			*args[10].(*int) = 4
+			*args[11].(*time.Time) = lastReportedAt
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		videos, total, err := cfg.repo.GetReportedVideos(ctx, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, expected, videos)
+	})
+}
+
+func TestGetOpenReportsByVideos(t *testing.T) {
+	t.Run("Should not query without videos", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+
+		reports, err := cfg.repo.GetOpenReportsByVideos(context.Background(), nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, reports)
+	})
+
+	t.Run("Should return open reports of videos", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expected := &entities.VideoReport{ID: 9, VideoID: 3, ReporterID: 1, Reason: entities.ReportReasonSpam, Status: entities.ReportStatusOpen}
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), entities.ReportStatusOpen, int64(3), int64(4)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = expected.ID
+			*args[1].(*int64) = expected.VideoID
+			*args[2].(*int64) = expected.ReporterID
+			*args[3].(*entities.ReportReason) = expected.Reason
+			*args[4].(*string) = expected.Note
+			*args[5].(*entities.ReportStatus) = expected.Status
+			*args[6].(*time.Time) = expected.CreatedAt
+			*args[7].(*sql.NullTime) = expected.ResolvedAt
+			*args[8].(*sql.NullInt64) = expected.ResolvedBy
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		reports, err := cfg.repo.GetOpenReportsByVideos(ctx, []int64{3, 4})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.VideoReport{expected}, reports)
+	})
+}
+
+func TestResolveVideoReports(t *testing.T) {
+	at := time.Now()
+
+	t.Run("Should dismiss open report", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), entities.ReportStatusDismissed, at, int64(5), int64(9), entities.ReportStatusOpen).Return(nil)
+
+		assert.NoError(t, cfg.repo.Dismiss(ctx, 9, 5, at))
+	})
+
+	t.Run("Should return ErrNoRowsAffected if report is not open", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.Dismiss(ctx, 9, 5, at), pkg.ErrNoRowsAffected)
+	})
+
+	t.Run("Should resolve open reports of video even when there is none", func(t *testing.T) {
+		cfg := SetupVideoReportConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), entities.ReportStatusActioned, at, int64(5), int64(3), entities.ReportStatusOpen).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		assert.NoError(t, cfg.repo.ResolveByVideo(ctx, 3, 5, entities.ReportStatusActioned, at))
+	})
+}
@@ -3,14 +3,46 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/pkg"
 )
 
 type VideoRepository interface {
-	CreateVideo(ctx context.Context, payload *entities.Video) (*entities.Video, error)
+	// CreateVideo create video in transaction, returning its id.
+	CreateVideo(ctx context.Context, tx pkg.Tx, payload *entities.Video) (int64, error)
 	GetVideo(ctx context.Context, videoID int64) (*entities.Video, error)
-	GetListVideos(ctx context.Context, page, limit int) ([]*entities.Video, int, error)
+	// GetListVideos get videos, hidden ones are only listed for their owner viewerID (0 for anonymous viewers).
+	// Only videos tagged with tag are listed unless it is empty.
+	// Listed videos carry their bookmark count and whether viewerID bookmarked them, as do the ones below.
+	GetListVideos(ctx context.Context, viewerID int64, tag string, page, limit int) ([]*entities.Video, int, error)
+	GetFeedVideos(ctx context.Context, accountID int64, cursor int64, limit int) ([]*entities.Video, error)
+	// GetListVideosByAccount get videos of account, hidden ones are only listed when viewerID is the account.
+	GetListVideosByAccount(ctx context.Context, accountID int64, viewerID int64, page, limit int) ([]*entities.Video, int, error)
+
+	// GetVideosByIDs get videos by id in no particular order, hidden ones only when viewerID is their owner.
+	GetVideosByIDs(ctx context.Context, videoIDs []int64, viewerID int64) ([]*entities.Video, error)
+
+	// HideVideo hide video from everyone but its owner, ErrNoRowsAffected when it is hidden already.
+	HideVideo(ctx context.Context, videoID int64, at time.Time) error
+
+	// UnhideVideo make hidden video visible again, ErrNoRowsAffected when it is not hidden.
+	UnhideVideo(ctx context.Context, videoID int64) error
+
+	// GetDeletedVideo get soft deleted video, reads above leave soft deleted videos and videos of soft deleted accounts out.
+	GetDeletedVideo(ctx context.Context, videoID int64) (*entities.Video, error)
+
+	// DeleteVideo soft delete video, ErrNoRowsAffected when it is deleted already.
+	DeleteVideo(ctx context.Context, videoID int64, deletedBy int64, at time.Time) error
+
+	// RestoreVideo restore soft deleted video, ErrNoRowsAffected when it is not deleted.
+	RestoreVideo(ctx context.Context, videoID int64) error
+
+	// PurgeDeletedVideos delete for good videos soft deleted before before, returning how many were deleted.
+	PurgeDeletedVideos(ctx context.Context, before time.Time) (int64, error)
+
+	BeginTransaction(ctx context.Context) (pkg.Tx, error)
 }
 
 type videoRepository struct {
@@ -24,33 +56,38 @@ func NewVideoRepository(db pkg.Database) VideoRepository {
 }
 
 // CreateVideo implements VideoRepository.
-func (v *videoRepository) CreateVideo(ctx context.Context, payload *entities.Video) (*entities.Video, error) {
+func (v *videoRepository) CreateVideo(ctx context.Context, tx pkg.Tx, payload *entities.Video) (int64, error) {
 	query := `INSERT INTO videos (title, description, upvote, downvote, thumbnail, video_url, account_id)
 			VALUES (?, ?, ?, ?, ?, ?, ?)`
 
-	rs, err := v.db.ExecWithResult(ctx, query, payload.Title, payload.Description, payload.UpVote, payload.DownVote, payload.Thumbnail, payload.VideoUrl, payload.AccountID)
-
-	if err != nil {
-		return nil, err
+	if err := tx.Exec(ctx, query, payload.Title, payload.Description, payload.UpVote, payload.DownVote, payload.Thumbnail, payload.VideoUrl, payload.AccountID); err != nil {
+		return 0, err
 	}
 
-	lastInsertId, err := rs.LastInsertId()
+	// the transaction holds on to one connection, so this is the id inserted above
+	var lastInsertId int64
+	if err := tx.QueryRow(ctx, `SELECT LAST_INSERT_ID()`).Scan(&lastInsertId); err != nil {
+		return 0, err
+	}
 
-	if err != nil || lastInsertId == 0 {
-		return nil, errors.New("db execution failed")
+	if lastInsertId == 0 {
+		return 0, errors.New("db execution failed")
 	}
 
-	return v.GetVideo(ctx, lastInsertId)
+	return lastInsertId, nil
 }
 
 // GetVideo implements VideoRepository.
 func (v *videoRepository) GetVideo(ctx context.Context, videoID int64) (*entities.Video, error) {
-	query := `SELECT * FROM videos WHERE id = ?`
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at
+	FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	WHERE v.id = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL`
 
 	video := &entities.Video{}
 
 	if err := v.db.QueryRow(ctx, query, videoID).
-		Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID); err != nil {
+		Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt); err != nil {
 		return nil, err
 	}
 
@@ -58,19 +95,26 @@ func (v *videoRepository) GetVideo(ctx context.Context, videoID int64) (*entitie
 }
 
 // GetListVideos implements VideoRepository.
-func (v *videoRepository) GetListVideos(ctx context.Context, page, limit int) ([]*entities.Video, int, error) {
+func (v *videoRepository) GetListVideos(ctx context.Context, viewerID int64, tag string, page, limit int) ([]*entities.Video, int, error) {
+	tagCondition := ` AND (? = '' OR v.id IN (SELECT vt.video_id FROM video_tags vt JOIN tags t ON t.id = vt.tag_id WHERE t.name = ?))`
+
 	var totalItems int
-	countQuery := `SELECT COUNT(*) FROM videos`
-	if err := v.db.QueryRow(ctx, countQuery).Scan(&totalItems); err != nil {
+	countQuery := `SELECT COUNT(*) FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	WHERE v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)` + tagCondition
+	if err := v.db.QueryRow(ctx, countQuery, viewerID, tag, tag).Scan(&totalItems); err != nil {
 		return nil, 0, err
 	}
 
-	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, a.fullname
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id), mb.video_id IS NOT NULL
 	FROM videos v
 	JOIN accounts a ON v.account_id = a.id
+	LEFT JOIN bookmarks mb ON mb.video_id = v.id AND mb.account_id = ?
+	WHERE v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)` + tagCondition + `
 	ORDER BY v.id ASC LIMIT ? OFFSET ?`
 
-	rows, err := v.db.Query(ctx, query, limit, (page-1)*limit)
+	rows, err := v.db.Query(ctx, query, viewerID, viewerID, tag, tag, limit, (page-1)*limit)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -79,11 +123,199 @@ func (v *videoRepository) GetListVideos(ctx context.Context, page, limit int) ([
 	var videos []*entities.Video
 	for rows.Next() {
 		video := &entities.Video{}
-		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.FullName); err != nil {
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount, &video.IsBookmarked); err != nil {
 			return nil, 0, err
 		}
 		videos = append(videos, video)
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
 	return videos, totalItems, nil
 }
+
+// GetFeedVideos implements VideoRepository.
+//
+// Videos are returned newest first. A cursor of 0 starts from the latest video,
+// otherwise only videos with an id lower than the cursor are returned.
+func (v *videoRepository) GetFeedVideos(ctx context.Context, accountID int64, cursor int64, limit int) ([]*entities.Video, error) {
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id), mb.video_id IS NOT NULL
+	FROM videos v
+	JOIN follows f ON f.following_id = v.account_id
+	JOIN accounts a ON v.account_id = a.id
+	LEFT JOIN bookmarks mb ON mb.video_id = v.id AND mb.account_id = ?
+	WHERE f.follower_id = ? AND v.hidden_at IS NULL AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (? = 0 OR v.id < ?)
+	ORDER BY v.id DESC LIMIT ?`
+
+	rows, err := v.db.Query(ctx, query, accountID, accountID, cursor, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.Video
+	for rows.Next() {
+		video := &entities.Video{}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount, &video.IsBookmarked); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+// GetListVideosByAccount implements VideoRepository.
+func (v *videoRepository) GetListVideosByAccount(ctx context.Context, accountID int64, viewerID int64, page, limit int) ([]*entities.Video, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	WHERE v.account_id = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)`
+	if err := v.db.QueryRow(ctx, countQuery, accountID, viewerID).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id), mb.video_id IS NOT NULL
+	FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	LEFT JOIN bookmarks mb ON mb.video_id = v.id AND mb.account_id = ?
+	WHERE v.account_id = ? AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)
+	ORDER BY v.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := v.db.Query(ctx, query, viewerID, accountID, viewerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.Video
+	for rows.Next() {
+		video := &entities.Video{}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount, &video.IsBookmarked); err != nil {
+			return nil, 0, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return videos, totalItems, nil
+}
+
+// GetVideosByIDs implements VideoRepository.
+func (v *videoRepository) GetVideosByIDs(ctx context.Context, videoIDs []int64, viewerID int64) ([]*entities.Video, error) {
+	if len(videoIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(videoIDs)+2)
+	args = append(args, viewerID)
+	for _, id := range videoIDs {
+		args = append(args, id)
+	}
+	args = append(args, viewerID)
+
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id), mb.video_id IS NOT NULL
+	FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	LEFT JOIN bookmarks mb ON mb.video_id = v.id AND mb.account_id = ?
+	WHERE v.id IN (?` + strings.Repeat(", ?", len(videoIDs)-1) + `) AND v.deleted_at IS NULL AND a.deleted_at IS NULL AND (v.hidden_at IS NULL OR v.account_id = ?)`
+
+	rows, err := v.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.Video
+	for rows.Next() {
+		video := &entities.Video{}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount, &video.IsBookmarked); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return videos, nil
+}
+
+// HideVideo implements VideoRepository.
+func (v *videoRepository) HideVideo(ctx context.Context, videoID int64, at time.Time) error {
+	query := `UPDATE videos SET hidden_at = ? WHERE id = ? AND hidden_at IS NULL AND deleted_at IS NULL`
+
+	return v.db.Exec(ctx, query, at, videoID)
+}
+
+// UnhideVideo implements VideoRepository.
+func (v *videoRepository) UnhideVideo(ctx context.Context, videoID int64) error {
+	query := `UPDATE videos SET hidden_at = NULL WHERE id = ? AND hidden_at IS NOT NULL AND deleted_at IS NULL`
+
+	return v.db.Exec(ctx, query, videoID)
+}
+
+// GetDeletedVideo implements VideoRepository.
+func (v *videoRepository) GetDeletedVideo(ctx context.Context, videoID int64) (*entities.Video, error) {
+	query := `SELECT id, title, description, upvote, downvote, thumbnail, video_url, account_id, hidden_at, deleted_at, deleted_by
+	FROM videos WHERE id = ? AND deleted_at IS NOT NULL`
+
+	video := &entities.Video{}
+
+	if err := v.db.QueryRow(ctx, query, videoID).
+		Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID,
+			&video.HiddenAt, &video.DeletedAt, &video.DeletedBy); err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+// DeleteVideo implements VideoRepository.
+func (v *videoRepository) DeleteVideo(ctx context.Context, videoID int64, deletedBy int64, at time.Time) error {
+	query := `UPDATE videos SET deleted_at = ?, deleted_by = ? WHERE id = ? AND deleted_at IS NULL`
+
+	return v.db.Exec(ctx, query, at, deletedBy, videoID)
+}
+
+// RestoreVideo implements VideoRepository.
+func (v *videoRepository) RestoreVideo(ctx context.Context, videoID int64) error {
+	query := `UPDATE videos SET deleted_at = NULL, deleted_by = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+
+	return v.db.Exec(ctx, query, videoID)
+}
+
+// PurgeDeletedVideos implements VideoRepository.
+func (v *videoRepository) PurgeDeletedVideos(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM videos WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	rs, err := v.db.ExecWithResult(ctx, query, before)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return rs.RowsAffected()
+}
+
+// BeginTransaction implements VideoRepository.
+func (v *videoRepository) BeginTransaction(ctx context.Context) (pkg.Tx, error) {
+	return v.db.Begin(ctx)
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"testing"
+	"time"
 	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -25,47 +28,35 @@ func SetupVideoConfig(t *testing.T) *videoConfig {
 }
 
 func TestCreateVideo(t *testing.T) {
+	video := &entities.Video{
+		Title:       "test",
+		Description: "Test Video",
+		UpVote:      10,
+		DownVote:    2,
+		Thumbnail:   "https://thumbnail.url",
+		VideoUrl:    "https://video.url",
+		AccountID:   1,
+	}
+
 	t.Run("Should create a video successfully", func(t *testing.T) {
 		cfg := SetupVideoConfig(t)
 		defer cfg.TearDownTest()
 
 		ctx := context.Background()
-		video := &entities.Video{
-			ID:          1,
-			Title:       "test",
-			Description: "Test Video",
-			UpVote:      10,
-			DownVote:    2,
-			Thumbnail:   "https://thumbnail.url",
-			VideoUrl:    "https://video.url",
-			AccountID:   1,
-		}
-
-		mockSqlResult := &MockSQLResult{
-			LastInsertID: video.ID,
-			RowAffected:  1,
-		}
-		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(mockSqlResult, nil)
-		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), mockSqlResult.LastInsertID).Return(cfg.row)
-
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
-			DoAndReturn(func(args ...interface{}) error {
-				*args[0].(*int64) = video.ID
-				*args[1].(*string) = video.Title
-				*args[2].(*string) = video.Description
-				*args[3].(*int64) = video.UpVote
-				*args[4].(*int64) = video.DownVote
-				*args[5].(*string) = video.Thumbnail
-				*args[6].(*string) = video.VideoUrl
-				*args[7].(*int64) = video.AccountID
 
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("INSERT INTO videos"), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(nil),
+			cfg.tx.EXPECT().QueryRow(ctx, gomock.Regex("LAST_INSERT_ID")).Return(cfg.row),
+			cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+				*args[0].(*int64) = 1
 				return nil
-			})
+			}),
+		)
 
-		rs, err := cfg.repo.CreateVideo(ctx, video)
+		id, err := cfg.repo.CreateVideo(ctx, cfg.tx, video)
 
 		assert.NoError(t, err)
-		assert.Equal(t, video, rs)
+		assert.Equal(t, int64(1), id)
 	})
 
 	t.Run("Should return error when DB execution fails", func(t *testing.T) {
@@ -73,20 +64,11 @@ func TestCreateVideo(t *testing.T) {
 		defer cfg.TearDownTest()
 
 		ctx := context.Background()
-		video := &entities.Video{
-			Title:       "test",
-			Description: "Test Video",
-			UpVote:      10,
-			DownVote:    2,
-			Thumbnail:   "https://thumbnail.url",
-			VideoUrl:    "https://video.url",
-			AccountID:   1,
-		}
 
 		expectedErr := errors.New("db execution failed")
-		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(nil, expectedErr)
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(expectedErr)
 
-		_, err := cfg.repo.CreateVideo(ctx, video)
+		_, err := cfg.repo.CreateVideo(ctx, cfg.tx, video)
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 	})
@@ -96,21 +78,13 @@ func TestCreateVideo(t *testing.T) {
 		defer cfg.TearDownTest()
 
 		ctx := context.Background()
-		video := &entities.Video{
-			Title:       "test",
-			Description: "Test Video",
-			UpVote:      10,
-			DownVote:    2,
-			Thumbnail:   "https://thumbnail.url",
-			VideoUrl:    "https://video.url",
-			AccountID:   1,
-		}
 
 		expectedErr := errors.New("db execution failed")
-		mockSqlResult := &MockSQLResult{}
-		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(mockSqlResult, nil)
+		cfg.tx.EXPECT().Exec(ctx, gomock.Any(), video.Title, video.Description, video.UpVote, video.DownVote, video.Thumbnail, video.VideoUrl, video.AccountID).Return(nil)
+		cfg.tx.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(nil)
 
-		_, err := cfg.repo.CreateVideo(ctx, video)
+		_, err := cfg.repo.CreateVideo(ctx, cfg.tx, video)
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr.Error(), err.Error())
 	})
@@ -135,7 +109,7 @@ func TestGetVideo(t *testing.T) {
 
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), expectedVideo.ID).Return(cfg.row)
 
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			*args[0].(*int64) = expectedVideo.ID
 			*args[1].(*string) = expectedVideo.Title
 			*args[2].(*string) = expectedVideo.Description
@@ -159,7 +133,7 @@ func TestGetVideo(t *testing.T) {
 		ctx := context.Background()
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any()).Return(cfg.row)
 
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("no rows"))
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("no rows"))
 
 		video, err := cfg.repo.GetVideo(ctx, 1)
 		assert.Nil(t, video)
@@ -174,7 +148,7 @@ func TestGetVideo(t *testing.T) {
 		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), gomock.Any()).Return(cfg.row)
 
 		err := errors.New("scan error")
-		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+		cfg.row.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
 
 		result, errRes := cfg.repo.GetVideo(ctx, 1)
 
@@ -195,17 +169,18 @@ func TestGetListVideos(t *testing.T) {
 			{ID: 2, Title: "test 2", Description: "Video 2", UpVote: 3, DownVote: 0, Thumbnail: "thumb2.jpg", VideoUrl: "url2", AccountID: 2, FullName: "User Two"},
 		}
 
-		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(0), "", "").Return(cfg.row)
 		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			*args[0].(*int) = len(expectedVideos)
 			return nil
 		})
 
-		cfg.db.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(cfg.rows, nil)
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(0), int64(0), "", "", 2, 0).Return(cfg.rows, nil)
 
 		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedVideos))
 		cfg.rows.EXPECT().Next().Return(false).Times(1)
-		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			video := expectedVideos[0]
 			expectedVideos = expectedVideos[1:]
 			*args[0].(*int64) = video.ID
@@ -216,13 +191,13 @@ func TestGetListVideos(t *testing.T) {
 			*args[5].(*string) = video.Thumbnail
 			*args[6].(*string) = video.VideoUrl
 			*args[7].(*int64) = video.AccountID
-			*args[8].(*string) = video.FullName
+			*args[9].(*string) = video.FullName
 			return nil
 		}).Times(len(expectedVideos))
 
 		cfg.rows.EXPECT().Close().Times(1)
 
-		videos, total, err := cfg.repo.GetListVideos(ctx, 1, 2)
+		videos, total, err := cfg.repo.GetListVideos(ctx, 0, "", 1, 2)
 		assert.NoError(t, err)
 		assert.Equal(t, 2, total)
 		assert.Len(t, videos, 2)
@@ -235,10 +210,10 @@ func TestGetListVideos(t *testing.T) {
 		ctx := context.Background()
 
 		err := errors.New("no rows")
-		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(0), "", "").Return(cfg.row)
 		cfg.row.EXPECT().Scan(gomock.Any()).Return(err)
 
-		videos, total, errRes := cfg.repo.GetListVideos(ctx, 1, 2)
+		videos, total, errRes := cfg.repo.GetListVideos(ctx, 0, "", 1, 2)
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errRes)
@@ -256,16 +231,16 @@ func TestGetListVideos(t *testing.T) {
 			{ID: 2, Title: "test 2", Description: "Video 2", UpVote: 3, DownVote: 0, Thumbnail: "thumb2.jpg", VideoUrl: "url2", AccountID: 2, FullName: "User Two"},
 		}
 
-		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(0), "", "").Return(cfg.row)
 		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			*args[0].(*int) = len(expectedVideos)
 			return nil
 		})
 
 		err := errors.New("db execute failed")
-		cfg.db.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, err)
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(0), int64(0), "", "", 2, 0).Return(nil, err)
 
-		videos, total, errRes := cfg.repo.GetListVideos(ctx, 1, 2)
+		videos, total, errRes := cfg.repo.GetListVideos(ctx, 0, "", 1, 2)
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errRes)
@@ -283,17 +258,17 @@ func TestGetListVideos(t *testing.T) {
 			{ID: 2, Title: "test 2", Description: "Video 2", UpVote: 3, DownVote: 0, Thumbnail: "thumb2.jpg", VideoUrl: "url2", AccountID: 2, FullName: "User Two"},
 		}
 
-		cfg.db.EXPECT().QueryRow(ctx, gomock.Any()).Return(cfg.row)
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(0), "", "").Return(cfg.row)
 		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			*args[0].(*int) = len(expectedVideos)
 			return nil
 		})
 
-		cfg.db.EXPECT().Query(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(cfg.rows, nil)
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(0), int64(0), "", "", 2, 0).Return(cfg.rows, nil)
 
 		err := errors.New("scan error")
 		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedVideos))
-		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
 			video := expectedVideos[0]
 			expectedVideos = expectedVideos[1:]
 			*args[0].(*int64) = video.ID
@@ -304,14 +279,14 @@ func TestGetListVideos(t *testing.T) {
 			*args[5].(*string) = video.Thumbnail
 			*args[6].(*string) = video.VideoUrl
 			*args[7].(*int64) = video.AccountID
-			*args[8].(*string) = video.FullName
+			*args[9].(*string) = video.FullName
 			return nil
 		}).Times(len(expectedVideos) - 1)
-		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(err).Times(1)
 
 		cfg.rows.EXPECT().Close().Times(1)
 
-		videos, total, errRes := cfg.repo.GetListVideos(ctx, 1, 2)
+		videos, total, errRes := cfg.repo.GetListVideos(ctx, 0, "", 1, 2)
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errRes)
@@ -319,3 +294,267 @@ func TestGetListVideos(t *testing.T) {
 		assert.Equal(t, 0, total)
 	})
 }
+
+func TestGetFeedVideos(t *testing.T) {
+	t.Run("Should return videos of followed accounts", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedVideos := []*entities.Video{
+			{ID: 5, Title: "test 5", Description: "Video 5", UpVote: 5, DownVote: 1, Thumbnail: "thumb5.jpg", VideoUrl: "url5", AccountID: 2, FullName: "User Two"},
+			{ID: 3, Title: "test 3", Description: "Video 3", UpVote: 3, DownVote: 0, Thumbnail: "thumb3.jpg", VideoUrl: "url3", AccountID: 3, FullName: "User Three"},
+		}
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), int64(1), int64(10), int64(10), 3).Return(cfg.rows, nil)
+
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedVideos))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			video := expectedVideos[0]
+			expectedVideos = expectedVideos[1:]
+			*args[0].(*int64) = video.ID
+			*args[1].(*string) = video.Title
+			*args[2].(*string) = video.Description
+			*args[3].(*int64) = video.UpVote
+			*args[4].(*int64) = video.DownVote
+			*args[5].(*string) = video.Thumbnail
+			*args[6].(*string) = video.VideoUrl
+			*args[7].(*int64) = video.AccountID
+			*args[9].(*string) = video.FullName
+			return nil
+		}).Times(len(expectedVideos))
+
+		cfg.rows.EXPECT().Close().Times(1)
+
+		videos, err := cfg.repo.GetFeedVideos(ctx, 1, 10, 3)
+		assert.NoError(t, err)
+		assert.Len(t, videos, 2)
+		assert.Equal(t, int64(5), videos[0].ID)
+	})
+
+	t.Run("Should return error because db execute failed", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		err := errors.New("db execute failed")
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(1), int64(1), int64(0), int64(0), 3).Return(nil, err)
+
+		videos, errRes := cfg.repo.GetFeedVideos(ctx, 1, 0, 3)
+
+		assert.Equal(t, err, errRes)
+		assert.Nil(t, videos)
+	})
+}
+
+func TestGetListVideosByAccount(t *testing.T) {
+	t.Run("Should return videos shared by account and total items", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		expectedVideos := []*entities.Video{
+			{ID: 2, Title: "test 2", Description: "Video 2", UpVote: 3, DownVote: 0, Thumbnail: "thumb2.jpg", VideoUrl: "url2", AccountID: 1, FullName: "User One"},
+			{ID: 1, Title: "test 1", Description: "Video 1", UpVote: 5, DownVote: 1, Thumbnail: "thumb1.jpg", VideoUrl: "url1", AccountID: 1, FullName: "User One"},
+		}
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), int64(2)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = len(expectedVideos)
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), int64(2), int64(1), int64(2), 2, 0).Return(cfg.rows, nil)
+
+		cfg.rows.EXPECT().Next().Return(true).Times(len(expectedVideos))
+		cfg.rows.EXPECT().Next().Return(false).Times(1)
+		cfg.rows.EXPECT().Err().Return(nil).Times(1)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			video := expectedVideos[0]
+			expectedVideos = expectedVideos[1:]
+			*args[0].(*int64) = video.ID
+			*args[1].(*string) = video.Title
+			*args[2].(*string) = video.Description
+			*args[3].(*int64) = video.UpVote
+			*args[4].(*int64) = video.DownVote
+			*args[5].(*string) = video.Thumbnail
+			*args[6].(*string) = video.VideoUrl
+			*args[7].(*int64) = video.AccountID
+			*args[9].(*string) = video.FullName
+			return nil
+		}).Times(len(expectedVideos))
+
+		cfg.rows.EXPECT().Close().Times(1)
+
+		videos, total, err := cfg.repo.GetListVideosByAccount(ctx, 1, 2, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Len(t, videos, 2)
+	})
+
+	t.Run("Should return error if count query fails", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		err := errors.New("no rows")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1), int64(2)).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(err)
+
+		videos, total, errRes := cfg.repo.GetListVideosByAccount(ctx, 1, 2, 1, 2)
+
+		assert.Equal(t, err, errRes)
+		assert.Nil(t, videos)
+		assert.Equal(t, 0, total)
+	})
+}
+
+func TestGetVideosByIDs(t *testing.T) {
+	t.Run("Should not query without ids", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		videos, err := cfg.repo.GetVideosByIDs(context.Background(), nil, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, videos)
+	})
+
+	t.Run("Should return videos visible to viewer", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("v.id IN \\(\\?, \\?\\)"), int64(1), int64(3), int64(4), int64(1)).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 4
+			*args[1].(*string) = "test 4"
+			*args[10].(*int64) = 2
+			*args[11].(*bool) = true
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		videos, err := cfg.repo.GetVideosByIDs(ctx, []int64{3, 4}, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, videos, 1)
+		assert.Equal(t, int64(4), videos[0].ID)
+		assert.Equal(t, int64(2), videos[0].BookmarkCount)
+		assert.True(t, videos[0].IsBookmarked)
+	})
+}
+
+func TestHideAndUnhideVideo(t *testing.T) {
+	t.Run("Should hide visible video", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		at := time.Now()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), at, int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.HideVideo(ctx, 1, at))
+	})
+
+	t.Run("Should return ErrNoRowsAffected when unhiding video that is not hidden", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.UnhideVideo(ctx, 1), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestGetDeletedVideo(t *testing.T) {
+	t.Run("Should return soft deleted video with who deleted it", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		deletedAt := time.Now()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), int64(1)).Return(cfg.row)
+		scanArgs := make([]any, 11)
+		for i := range scanArgs {
+			scanArgs[i] = gomock.Any()
+		}
+		cfg.row.EXPECT().Scan(scanArgs...).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 1
+			*args[7].(*int64) = 2
+			*args[9].(*sql.NullTime) = sql.NullTime{Time: deletedAt, Valid: true}
+			*args[10].(*sql.NullInt64) = sql.NullInt64{Int64: 5, Valid: true}
+			return nil
+		})
+
+		video, err := cfg.repo.GetDeletedVideo(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &entities.Video{
+			ID:        1,
+			AccountID: 2,
+			DeletedAt: sql.NullTime{Time: deletedAt, Valid: true},
+			DeletedBy: sql.NullInt64{Int64: 5, Valid: true},
+		}, video)
+	})
+}
+
+func TestDeleteAndRestoreVideo(t *testing.T) {
+	t.Run("Should soft delete video", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		at := time.Now()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), at, int64(2), int64(1)).Return(nil)
+
+		assert.NoError(t, cfg.repo.DeleteVideo(ctx, 1, 2, at))
+	})
+
+	t.Run("Should return ErrNoRowsAffected when restoring video that is not deleted", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		cfg.db.EXPECT().Exec(ctx, gomock.Any(), int64(1)).Return(pkg.ErrNoRowsAffected)
+
+		assert.ErrorIs(t, cfg.repo.RestoreVideo(ctx, 1), pkg.ErrNoRowsAffected)
+	})
+}
+
+func TestPurgeDeletedVideos(t *testing.T) {
+	t.Run("Should return how many videos were purged", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		ctx := context.Background()
+		before := time.Now().Add(-720 * time.Hour)
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), before).Return(&MockSQLResult{RowAffected: 4}, nil)
+
+		purged, err := cfg.repo.PurgeDeletedVideos(ctx, before)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), purged)
+	})
+}
+
+func TestBeginVideoTransaction(t *testing.T) {
+	t.Run("Should begin a transaction successfully", func(t *testing.T) {
+		cfg := SetupVideoConfig(t)
+		defer cfg.TearDownTest()
+
+		cfg.db.EXPECT().Begin(gomock.Any()).Return(cfg.tx, nil)
+
+		tx, err := cfg.repo.BeginTransaction(context.Background())
+
+		assert.NoError(t, err)
+		assert.NotNil(t, tx)
+	})
+}
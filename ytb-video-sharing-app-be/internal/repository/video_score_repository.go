@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// videoScoreBatchSize is how many scores are saved per insert.
+const videoScoreBatchSize = 500
+
+type VideoScoreRepository interface {
+	// GetVideoStats get votes, comments, views since viewedSince and share time of videos shared since, listed to everyone.
+	// Videos whose share time is unknown are only returned for zero since.
+	GetVideoStats(ctx context.Context, since time.Time, viewedSince time.Time) ([]*entities.VideoStats, error)
+
+	// DeleteScores delete scores of window, before they are saved again.
+	DeleteScores(ctx context.Context, tx pkg.Tx, window entities.TrendingWindow) error
+
+	// SaveScores save scores of window computed at computedAt.
+	SaveScores(ctx context.Context, tx pkg.Tx, window entities.TrendingWindow, scores []*entities.VideoScore, computedAt time.Time) error
+
+	// GetTrendingVideos get videos scored for window, the highest score first, and how many there are.
+	// Videos hidden or deleted since their score was computed are left out. Listed videos carry their bookmark
+	// count and whether viewerID bookmarked them.
+	GetTrendingVideos(ctx context.Context, window entities.TrendingWindow, viewerID int64, page, limit int) ([]*entities.Video, int, error)
+
+	BeginTransaction(ctx context.Context) (pkg.Tx, error)
+}
+
+type videoScoreRepository struct {
+	db pkg.Database
+}
+
+func NewVideoScoreRepository(db pkg.Database) VideoScoreRepository {
+	return &videoScoreRepository{
+		db: db,
+	}
+}
+
+// GetVideoStats implements VideoScoreRepository.
+func (v *videoScoreRepository) GetVideoStats(ctx context.Context, since time.Time, viewedSince time.Time) ([]*entities.VideoStats, error) {
+	query := `SELECT v.id, v.upvote, v.downvote, v.created_at,
+	(SELECT COUNT(*) FROM video_comments c WHERE c.video_id = v.id),
+	(SELECT COUNT(*) FROM video_views w WHERE w.video_id = v.id AND w.viewed_at >= ?)
+	FROM videos v
+	JOIN accounts a ON v.account_id = a.id
+	WHERE v.hidden_at IS NULL AND v.deleted_at IS NULL AND a.deleted_at IS NULL`
+
+	args := []any{viewedSince}
+	if !since.IsZero() {
+		query += ` AND v.created_at >= ?`
+		args = append(args, since)
+	}
+
+	rows, err := v.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*entities.VideoStats
+	for rows.Next() {
+		stat := &entities.VideoStats{}
+		if err := rows.Scan(&stat.VideoID, &stat.UpVote, &stat.DownVote, &stat.CreatedAt, &stat.CommentCount, &stat.ViewCount); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// DeleteScores implements VideoScoreRepository.
+func (v *videoScoreRepository) DeleteScores(ctx context.Context, tx pkg.Tx, window entities.TrendingWindow) error {
+	return tx.Exec(ctx, `DELETE FROM video_scores WHERE time_window = ?`, window)
+}
+
+// SaveScores implements VideoScoreRepository.
+func (v *videoScoreRepository) SaveScores(ctx context.Context, tx pkg.Tx, window entities.TrendingWindow, scores []*entities.VideoScore, computedAt time.Time) error {
+	for start := 0; start < len(scores); start += videoScoreBatchSize {
+		batch := scores[start:min(start+videoScoreBatchSize, len(scores))]
+
+		args := make([]any, 0, len(batch)*4)
+		for _, score := range batch {
+			args = append(args, window, score.VideoID, score.Score, computedAt)
+		}
+
+		query := `INSERT INTO video_scores (time_window, video_id, score, computed_at) VALUES (?, ?, ?, ?)` +
+			strings.Repeat(", (?, ?, ?, ?)", len(batch)-1)
+
+		if err := tx.Exec(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetTrendingVideos implements VideoScoreRepository.
+func (v *videoScoreRepository) GetTrendingVideos(ctx context.Context, window entities.TrendingWindow, viewerID int64, page, limit int) ([]*entities.Video, int, error) {
+	var totalItems int
+	countQuery := `SELECT COUNT(*) FROM video_scores s
+	JOIN videos v ON v.id = s.video_id
+	JOIN accounts a ON v.account_id = a.id
+	WHERE s.time_window = ? AND v.hidden_at IS NULL AND v.deleted_at IS NULL AND a.deleted_at IS NULL`
+	if err := v.db.QueryRow(ctx, countQuery, window).Scan(&totalItems); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT v.id, v.title, v.description, v.upvote, v.downvote, v.thumbnail, v.video_url, v.account_id, v.hidden_at, a.fullname,
+	(SELECT COUNT(*) FROM bookmarks bc WHERE bc.video_id = v.id), mb.video_id IS NOT NULL
+	FROM video_scores s
+	JOIN videos v ON v.id = s.video_id
+	JOIN accounts a ON v.account_id = a.id
+	LEFT JOIN bookmarks mb ON mb.video_id = v.id AND mb.account_id = ?
+	WHERE s.time_window = ? AND v.hidden_at IS NULL AND v.deleted_at IS NULL AND a.deleted_at IS NULL
+	ORDER BY s.score DESC, v.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := v.db.Query(ctx, query, viewerID, window, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var videos []*entities.Video
+	for rows.Next() {
+		video := &entities.Video{}
+		if err := rows.Scan(&video.ID, &video.Title, &video.Description, &video.UpVote, &video.DownVote, &video.Thumbnail, &video.VideoUrl, &video.AccountID, &video.HiddenAt, &video.FullName,
+			&video.BookmarkCount, &video.IsBookmarked); err != nil {
+			return nil, 0, err
+		}
+		videos = append(videos, video)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return videos, totalItems, nil
+}
+
+// BeginTransaction implements VideoScoreRepository.
+func (v *videoScoreRepository) BeginTransaction(ctx context.Context) (pkg.Tx, error) {
+	return v.db.Begin(ctx)
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type videoScoreConfig struct {
+	testConfig
+	repo VideoScoreRepository
+}
+
+func SetupVideoScoreConfig(t *testing.T) *videoScoreConfig {
+	testConf := SetupTest(t)
+
+	return &videoScoreConfig{
+		testConfig: *testConf,
+		repo:       NewVideoScoreRepository(testConf.db),
+	}
+}
+
+func TestGetVideoStats(t *testing.T) {
+	t.Run("Should return votes, comments and views of videos shared since", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		createdAt := since.Add(time.Hour)
+
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("v.created_at >= \\?"), since, since).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 3
+			*args[1].(*int64) = 10
+			*args[2].(*int64) = 2
+			*args[3].(*sql.NullTime) = sql.NullTime{Time: createdAt, Valid: true}
+			*args[4].(*int64) = 4
+			*args[5].(*int64) = 25
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		stats, err := cfg.repo.GetVideoStats(ctx, since, since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.VideoStats{{VideoID: 3, UpVote: 10, DownVote: 2, CreatedAt: sql.NullTime{Time: createdAt, Valid: true}, CommentCount: 4, ViewCount: 25}}, stats)
+	})
+
+	t.Run("Should return videos of unknown share time for all time", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		viewedSince := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		cfg.db.EXPECT().Query(ctx, gomock.Not(gomock.Regex("v.created_at >=")), viewedSince).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 3
+			*args[1].(*int64) = 10
+			*args[2].(*int64) = 2
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		stats, err := cfg.repo.GetVideoStats(ctx, time.Time{}, viewedSince)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []*entities.VideoStats{{VideoID: 3, UpVote: 10, DownVote: 2}}, stats)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().Query(ctx, gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		stats, err := cfg.repo.GetVideoStats(ctx, time.Time{}, time.Now())
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, stats)
+	})
+}
+
+func TestSaveScores(t *testing.T) {
+	t.Run("Should not insert without scores", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+
+		assert.NoError(t, cfg.repo.SaveScores(context.Background(), cfg.tx, entities.TrendingDay, nil, time.Now()))
+	})
+
+	t.Run("Should insert scores in batches", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		computedAt := time.Now()
+		scores := make([]*entities.VideoScore, videoScoreBatchSize+1)
+		for i := range scores {
+			scores[i] = &entities.VideoScore{VideoID: int64(i + 1), Score: float64(i)}
+		}
+
+		batchArgs := func(n int) []any {
+			args := make([]any, n*4)
+			for i := range args {
+				args[i] = gomock.Any()
+			}
+			return args
+		}
+
+		gomock.InOrder(
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("INSERT INTO video_scores"), batchArgs(videoScoreBatchSize)...).Return(nil),
+			cfg.tx.EXPECT().Exec(ctx, gomock.Regex("INSERT INTO video_scores"), entities.TrendingWeek, int64(videoScoreBatchSize+1), float64(videoScoreBatchSize), computedAt).Return(nil),
+		)
+
+		assert.NoError(t, cfg.repo.SaveScores(ctx, cfg.tx, entities.TrendingWeek, scores, computedAt))
+	})
+}
+
+func TestGetTrendingVideos(t *testing.T) {
+	t.Run("Should return videos of window by score", func(t *testing.T) {
+		cfg := SetupVideoScoreConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), entities.TrendingDay).Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int) = 1
+			return nil
+		})
+
+		cfg.db.EXPECT().Query(ctx, gomock.Regex("ORDER BY s.score DESC"), int64(1), entities.TrendingDay, 10, 0).Return(cfg.rows, nil)
+		cfg.rows.EXPECT().Next().Return(true)
+		cfg.rows.EXPECT().Next().Return(false)
+		cfg.rows.EXPECT().Err().Return(nil)
+		cfg.rows.EXPECT().Scan(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*int64) = 3
+			return nil
+		})
+		cfg.rows.EXPECT().Close()
+
+		videos, total, err := cfg.repo.GetTrendingVideos(ctx, entities.TrendingDay, 1, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, videos, 1)
+		assert.Equal(t, int64(3), videos[0].ID)
+	})
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type VideoViewRepository interface {
+	// Record save a view of video by account unless the account viewed it since, returning whether it was saved.
+	Record(ctx context.Context, videoID, accountID int64, since time.Time) (bool, error)
+}
+
+type videoViewRepository struct {
+	db pkg.Database
+}
+
+func NewVideoViewRepository(db pkg.Database) VideoViewRepository {
+	return &videoViewRepository{
+		db: db,
+	}
+}
+
+// Record implements VideoViewRepository.
+func (v *videoViewRepository) Record(ctx context.Context, videoID, accountID int64, since time.Time) (bool, error) {
+	query := `INSERT INTO video_views (video_id, account_id)
+	SELECT ?, ? FROM DUAL
+	WHERE NOT EXISTS (SELECT 1 FROM video_views WHERE account_id = ? AND video_id = ? AND viewed_at >= ?)`
+
+	rs, err := v.db.ExecWithResult(ctx, query, videoID, accountID, accountID, videoID, since)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := rs.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
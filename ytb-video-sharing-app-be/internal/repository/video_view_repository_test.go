@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type videoViewConfig struct {
+	testConfig
+	repo VideoViewRepository
+}
+
+func SetupVideoViewConfig(t *testing.T) *videoViewConfig {
+	testConf := SetupTest(t)
+
+	return &videoViewConfig{
+		testConfig: *testConf,
+		repo:       NewVideoViewRepository(testConf.db),
+	}
+}
+
+func TestRecordVideoView(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Should save view", func(t *testing.T) {
+		cfg := SetupVideoViewConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO video_views"), int64(3), int64(1), int64(1), int64(3), since).
+			Return(&MockSQLResult{RowAffected: 1}, nil)
+
+		recorded, err := cfg.repo.Record(ctx, 3, 1, since)
+
+		assert.NoError(t, err)
+		assert.True(t, recorded)
+	})
+
+	t.Run("Should not save view if account viewed video since", func(t *testing.T) {
+		cfg := SetupVideoViewConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("NOT EXISTS"), int64(3), int64(1), int64(1), int64(3), since).
+			Return(&MockSQLResult{RowAffected: 0}, nil)
+
+		recorded, err := cfg.repo.Record(ctx, 3, 1, since)
+
+		assert.NoError(t, err)
+		assert.False(t, recorded)
+	})
+
+	t.Run("Should return error if insert fails", func(t *testing.T) {
+		cfg := SetupVideoViewConfig(t)
+		defer cfg.TearDownTest()
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		recorded, err := cfg.repo.Record(ctx, 3, 1, since)
+
+		assert.Equal(t, expectedErr, err)
+		assert.False(t, recorded)
+	})
+}
@@ -2,8 +2,10 @@ package routes
 
 import (
 	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/internal/handler"
 	"ytb-video-sharing-app-be/internal/middleware"
+	"ytb-video-sharing-app-be/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,12 +18,38 @@ func NewRouter(
 	router *gin.Engine,
 	accountHandler *handler.AccountHandler,
 	videoHandler *handler.VideoHandler,
+	followHandler *handler.FollowHandler,
+	passwordResetHandler *handler.PasswordResetHandler,
+	jwksHandler *handler.JWKSHandler,
+	mfaHandler *handler.MFAHandler,
+	personalAccessTokenHandler *handler.PersonalAccessTokenHandler,
+	adminHandler *handler.AdminHandler,
+	videoReportHandler *handler.VideoReportHandler,
+	auditHandler *handler.AuditHandler,
+	playlistHandler *handler.PlaylistHandler,
+	bookmarkHandler *handler.BookmarkHandler,
+	trendingHandler *handler.TrendingHandler,
+	videoCommentHandler *handler.VideoCommentHandler,
 	middleware *middleware.JwtAuthenticationMiddleware,
 ) *Router {
+	router.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
+
 	apiV1Group := router.Group("/api/v1")
 
 	registerAccountEndpoint(accountHandler, apiV1Group, middleware)
 	registerVideoEndpoint(videoHandler, apiV1Group, middleware)
+	registerFollowEndpoint(followHandler, apiV1Group, middleware)
+	registerFeedEndpoint(videoHandler, apiV1Group, middleware)
+	registerPasswordResetEndpoint(passwordResetHandler, apiV1Group)
+	registerMFAEndpoint(mfaHandler, apiV1Group, middleware)
+	registerPersonalAccessTokenEndpoint(personalAccessTokenHandler, apiV1Group, middleware)
+	registerAdminEndpoint(adminHandler, apiV1Group, middleware)
+	registerModerationEndpoint(videoReportHandler, apiV1Group, middleware)
+	registerAuditEndpoint(auditHandler, apiV1Group, middleware)
+	registerPlaylistEndpoint(playlistHandler, apiV1Group, middleware)
+	registerBookmarkEndpoint(bookmarkHandler, apiV1Group, middleware)
+	registerTrendingEndpoint(trendingHandler, apiV1Group, middleware)
+	registerVideoCommentEndpoint(videoCommentHandler, apiV1Group, middleware)
 
 	return &Router{
 		Router: router,
@@ -33,14 +61,131 @@ func registerAccountEndpoint(accountHandler *handler.AccountHandler, group *gin.
 
 	accountGroup.POST("/register", middleware.ValidateRequest[dto.CreateAccountRequest](), accountHandler.Register)
 	accountGroup.POST("/login", middleware.ValidateRequest[dto.LoginRequest](), accountHandler.Login)
+	accountGroup.POST("/login/mfa", middleware.ValidateRequest[dto.LoginMFARequest](), accountHandler.LoginMFA)
+	accountGroup.GET("/oidc/authorize", accountHandler.OIDCAuthorize)
+	accountGroup.POST("/oidc/callback", middleware.ValidateRequest[dto.OIDCCallbackRequest](), accountHandler.OIDCCallback)
 	accountGroup.POST("/logout/:accountID", middleware.JWTAuthMiddleware(params), accountHandler.Logout)
 	accountGroup.POST("/refresh-token", middleware.JWTRefreshTokenMiddleware(params), accountHandler.RefreshToken)
-	accountGroup.GET("/check-token", middleware.JWTAuthMiddleware(params), accountHandler.CheckToken)
+	accountGroup.GET("/check-token", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeNotificationsRead)), accountHandler.CheckToken)
+	accountGroup.GET("/:id", accountHandler.GetProfile)
+	accountGroup.PATCH("/me", middleware.JWTAuthMiddleware(params), middleware.ValidateRequest[dto.UpdateProfileRequest](), accountHandler.UpdateProfile)
+	accountGroup.DELETE("/me", middleware.JWTAuthMiddleware(params), middleware.ValidateRequest[dto.DeleteMyAccountRequest](), accountHandler.DeleteAccount)
+	accountGroup.POST("/restore", middleware.ValidateRequest[dto.RestoreAccountRequest](), accountHandler.RestoreAccount)
+	accountGroup.PUT("/me/avatar", middleware.JWTAuthMiddleware(params), accountHandler.UpdateAvatar)
+	accountGroup.POST("/me/password", middleware.JWTAuthMiddleware(params), middleware.ValidateRequest[dto.ChangePasswordRequest](), accountHandler.ChangePassword)
+	accountGroup.GET("/me/sessions", middleware.JWTAuthMiddleware(params), accountHandler.ListSessions)
+	accountGroup.DELETE("/me/sessions/:id", middleware.JWTAuthMiddleware(params), accountHandler.RevokeSession)
+	accountGroup.POST("/verify-email", middleware.ValidateRequest[dto.VerifyEmailRequest](), accountHandler.VerifyEmail)
+	accountGroup.POST("/verify-email/resend", middleware.JWTAuthMiddleware(params), accountHandler.ResendVerificationEmail)
 }
 
 func registerVideoEndpoint(videoHandler *handler.VideoHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
 	videoGroup := group.Group("/videos")
 
-	videoGroup.POST("", middleware.JWTAuthMiddleware(params), middleware.ValidateRequest[dto.ShareVideoRequest](), videoHandler.ShareVideo)
-	videoGroup.GET("", videoHandler.GetListVideos)
+	videoGroup.POST("", middleware.JWTAuthMiddleware(params, middleware.WithVerifiedEmail(), middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.ShareVideoRequest](), videoHandler.ShareVideo)
+	videoGroup.GET("", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), videoHandler.GetListVideos)
+	videoGroup.DELETE("/:id", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), videoHandler.DeleteVideo)
+	videoGroup.POST("/:id/restore", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), videoHandler.RestoreVideo)
+	videoGroup.POST("/:id/views", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosRead)), videoHandler.RecordView)
+
+	group.GET("/tags", videoHandler.ListTags)
+
+	group.GET("/accounts/:id/videos", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), videoHandler.GetAccountVideos)
+}
+
+func registerFollowEndpoint(followHandler *handler.FollowHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	accountGroup := group.Group("/accounts")
+
+	accountGroup.POST("/:id/follow", middleware.JWTAuthMiddleware(params), followHandler.Follow)
+	accountGroup.DELETE("/:id/follow", middleware.JWTAuthMiddleware(params), followHandler.Unfollow)
+	accountGroup.GET("/:id/followers", followHandler.GetFollowers)
+	accountGroup.GET("/:id/following", followHandler.GetFollowing)
+}
+
+func registerFeedEndpoint(videoHandler *handler.VideoHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.GET("/feed", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosRead)), videoHandler.GetFeed)
+}
+
+func registerPasswordResetEndpoint(passwordResetHandler *handler.PasswordResetHandler, group *gin.RouterGroup) {
+	passwordGroup := group.Group("/accounts/password")
+
+	passwordGroup.POST("/forgot", middleware.ValidateRequest[dto.ForgotPasswordRequest](), passwordResetHandler.ForgotPassword)
+	passwordGroup.POST("/reset", middleware.ValidateRequest[dto.ResetPasswordRequest](), passwordResetHandler.ResetPassword)
+}
+
+func registerMFAEndpoint(mfaHandler *handler.MFAHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	mfaGroup := group.Group("/accounts/me/mfa", middleware.JWTAuthMiddleware(params))
+
+	mfaGroup.POST("/enroll", mfaHandler.Enroll)
+	mfaGroup.POST("/verify", middleware.ValidateRequest[dto.VerifyMFARequest](), mfaHandler.Verify)
+	mfaGroup.POST("/disable", middleware.ValidateRequest[dto.DisableMFARequest](), mfaHandler.Disable)
+}
+
+func registerPersonalAccessTokenEndpoint(personalAccessTokenHandler *handler.PersonalAccessTokenHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	tokenGroup := group.Group("/accounts/me/tokens", middleware.JWTAuthMiddleware(params))
+
+	tokenGroup.POST("", middleware.ValidateRequest[dto.CreatePersonalAccessTokenRequest](), personalAccessTokenHandler.Create)
+	tokenGroup.GET("", personalAccessTokenHandler.List)
+	tokenGroup.DELETE("/:id", personalAccessTokenHandler.Revoke)
+}
+
+func registerAdminEndpoint(adminHandler *handler.AdminHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	adminGroup := group.Group("/admin", middleware.JWTAuthMiddleware(params), middleware.RequireRole(entities.RoleAdmin))
+
+	adminGroup.GET("/accounts", middleware.RequirePermission(utils.PermissionManageAccounts), adminHandler.ListAccounts)
+	adminGroup.DELETE("/accounts/:id", middleware.RequirePermission(utils.PermissionManageAccounts), adminHandler.DeleteAccount)
+	adminGroup.POST("/accounts/:id/restore", middleware.RequirePermission(utils.PermissionRestoreAccounts), adminHandler.RestoreAccount)
+	adminGroup.POST("/accounts/:id/logout", middleware.RequirePermission(utils.PermissionManageAccounts), adminHandler.ForceLogout)
+	adminGroup.POST("/accounts/:id/suspend", middleware.RequirePermission(utils.PermissionSuspendAccounts), middleware.ValidateRequest[dto.SuspendAccountRequest](), adminHandler.Suspend)
+	adminGroup.DELETE("/accounts/:id/suspend", middleware.RequirePermission(utils.PermissionSuspendAccounts), adminHandler.Unsuspend)
+	adminGroup.PUT("/accounts/:id/role", middleware.RequirePermission(utils.PermissionManageRoles), middleware.ValidateRequest[dto.UpdateRoleRequest](), adminHandler.UpdateRole)
+}
+
+func registerModerationEndpoint(videoReportHandler *handler.VideoReportHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.POST("/videos/:id/report", middleware.JWTAuthMiddleware(params), middleware.ValidateRequest[dto.ReportVideoRequest](), videoReportHandler.Report)
+
+	moderationGroup := group.Group("/moderation", middleware.JWTAuthMiddleware(params), middleware.RequirePermission(utils.PermissionModerateVideos))
+
+	moderationGroup.GET("/reports", videoReportHandler.GetQueue)
+	moderationGroup.POST("/reports/:id/dismiss", videoReportHandler.DismissReport)
+	moderationGroup.POST("/videos/:id/hide", videoReportHandler.HideVideo)
+	moderationGroup.DELETE("/videos/:id/hide", videoReportHandler.UnhideVideo)
+}
+
+func registerAuditEndpoint(auditHandler *handler.AuditHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.GET("/accounts/me/security-activity", middleware.JWTAuthMiddleware(params), auditHandler.ListMyActivity)
+	group.GET("/admin/audit-events", middleware.JWTAuthMiddleware(params), middleware.RequireRole(entities.RoleAdmin), middleware.RequirePermission(utils.PermissionViewAuditLogs), auditHandler.ListEvents)
+}
+
+func registerPlaylistEndpoint(playlistHandler *handler.PlaylistHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	playlistGroup := group.Group("/playlists")
+
+	playlistGroup.POST("", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.CreatePlaylistRequest](), playlistHandler.Create)
+	playlistGroup.GET("/:id", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), playlistHandler.GetPlaylist)
+	playlistGroup.PATCH("/:id", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.UpdatePlaylistRequest](), playlistHandler.Update)
+	playlistGroup.DELETE("/:id", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), playlistHandler.Delete)
+	playlistGroup.GET("/:id/videos", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), playlistHandler.GetVideos)
+	playlistGroup.POST("/:id/videos", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.AddPlaylistVideoRequest](), playlistHandler.AddVideo)
+	playlistGroup.DELETE("/:id/videos/:videoID", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), playlistHandler.RemoveVideo)
+	playlistGroup.PUT("/:id/videos/:videoID/position", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.MovePlaylistVideoRequest](), playlistHandler.MoveVideo)
+	playlistGroup.POST("/:id/watch", middleware.JWTAuthMiddleware(params), playlistHandler.Watch)
+	playlistGroup.DELETE("/:id/watch", middleware.JWTAuthMiddleware(params), playlistHandler.Unwatch)
+
+	group.GET("/accounts/:id/playlists", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), playlistHandler.GetAccountPlaylists)
+}
+
+func registerBookmarkEndpoint(bookmarkHandler *handler.BookmarkHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.POST("/videos/:id/bookmark", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), bookmarkHandler.Bookmark)
+	group.DELETE("/videos/:id/bookmark", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), bookmarkHandler.DeleteBookmark)
+	group.GET("/accounts/me/bookmarks", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosRead)), bookmarkHandler.GetBookmarkedVideos)
+}
+
+func registerTrendingEndpoint(trendingHandler *handler.TrendingHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.GET("/videos/trending", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), trendingHandler.GetTrendingVideos)
+}
+
+func registerVideoCommentEndpoint(videoCommentHandler *handler.VideoCommentHandler, group *gin.RouterGroup, params *middleware.JwtAuthenticationMiddleware) {
+	group.POST("/videos/:id/comments", middleware.JWTAuthMiddleware(params, middleware.WithVerifiedEmail(), middleware.WithScope(utils.ScopeVideosWrite)), middleware.ValidateRequest[dto.CommentVideoRequest](), videoCommentHandler.Comment)
+	group.GET("/videos/:id/comments", middleware.JWTAuthMiddleware(params, middleware.WithOptionalAuth(), middleware.WithScope(utils.ScopeVideosRead)), videoCommentHandler.GetComments)
+	group.DELETE("/comments/:id", middleware.JWTAuthMiddleware(params, middleware.WithScope(utils.ScopeVideosWrite)), videoCommentHandler.DeleteComment)
 }
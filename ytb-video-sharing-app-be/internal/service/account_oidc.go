@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// OIDCLoginStateLifetime is how long the user has to log in at the identity provider.
+const OIDCLoginStateLifetime = 10 * time.Minute
+
+const invalidOIDCLoginState = "Login state is invalid or expired"
+
+// StartOIDCLogin implements AccountService.
+//
+// State, nonce and PKCE code verifier are kept server side, only the state and the code challenge leave through the browser.
+// Hash of the state is handed back as StateBinding, so a callback only succeeds in the browser that started the login.
+func (a *accountService) StartOIDCLogin(ctx context.Context) (*dto.OIDCAuthorizeResponse, *dto.ErrorResponse) {
+	if a.identityProvider == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "OIDC login is not configured"}
+	}
+
+	state, err := utils.GenerateRandomToken()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	nonce, err := utils.GenerateRandomToken()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	codeVerifier, err := utils.GenerateRandomToken()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	authorizationURL, err := a.identityProvider.AuthCodeURL(ctx, state, nonce, utils.PKCEChallenge(codeVerifier))
+
+	if err != nil {
+		log.Println("error when building authorization url: ", err)
+		return nil, &dto.ErrorResponse{Code: http.StatusBadGateway, Message: "Identity provider is unavailable"}
+	}
+
+	// logins that were never finished pile up otherwise
+	if err = a.oidcLoginStateRepository.DeleteExpired(ctx); err != nil {
+		log.Println("error when deleting expired oidc login states: ", err)
+	}
+
+	if err = a.oidcLoginStateRepository.Save(ctx, &entities.OIDCLoginState{
+		StateHash:    utils.HashToken(state),
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(OIDCLoginStateLifetime),
+	}); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.OIDCAuthorizeResponse{AuthorizationURL: authorizationURL, StateBinding: utils.HashToken(state)}, nil
+}
+
+// LoginWithOIDC implements AccountService.
+func (a *accountService) LoginWithOIDC(ctx context.Context, code string, state string, stateBinding string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.MFAChallengeResponse, *dto.ErrorResponse) {
+	if a.identityProvider == nil {
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "OIDC login is not configured"}
+	}
+
+	stateHash := utils.HashToken(state)
+
+	// code and state of someone else's login must not log this browser in
+	if subtle.ConstantTimeCompare([]byte(stateBinding), []byte(stateHash)) != 1 {
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidOIDCLoginState}
+	}
+
+	loginState := a.oidcLoginStateRepository.GetState(ctx, stateHash)
+
+	if loginState == nil || loginState.ExpiresAt.Before(time.Now()) {
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidOIDCLoginState}
+	}
+
+	// state is single use, a concurrent callback with the same state loses here
+	if err := a.oidcLoginStateRepository.Delete(ctx, stateHash); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidOIDCLoginState}
+		}
+
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	identity, err := a.identityProvider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+
+	if err != nil {
+		log.Println("error when exchanging authorization code: ", err)
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusUnauthorized, Message: "Login with identity provider failed"}
+	}
+
+	account, errRe := a.resolveIdentity(ctx, identity)
+
+	if errRe != nil {
+		return nil, nil, errRe
+	}
+
+	if challenge, errRe := a.mfaChallenge(ctx, account); challenge != nil || errRe != nil {
+		return nil, challenge, errRe
+	}
+
+	res, errRe := a.createSession(ctx, account, meta, "oidc")
+
+	return res, nil, errRe
+}
+
+// resolveIdentity returns account linked to identity, linking it by verified email or creating a new account first.
+func (a *accountService) resolveIdentity(ctx context.Context, identity *pkg.ExternalIdentity) (*entities.Account, *dto.ErrorResponse) {
+	if linked := a.accountIdentityRepository.GetIdentity(ctx, identity.Provider, identity.Subject); linked != nil {
+		account := a.accountRepository.GetAccountByID(ctx, linked.AccountID)
+
+		if account == nil {
+			return nil, &dto.ErrorResponse{Code: http.StatusUnauthorized, Message: "Account is not found"}
+		}
+
+		return account, nil
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Email of identity provider account is not verified"}
+	}
+
+	// start transaction
+	tx, err := a.accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	account := a.accountRepository.GetAccountByEmailX(ctx, tx, identity.Email)
+
+	if account != nil && !account.EmailVerifiedAt.Valid {
+		// whoever registered the unverified email may not be its owner, linking would let them in
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "An account with this email exists, verify its email before logging in with identity provider"}
+	}
+
+	if account == nil {
+		// the email may still belong to a soft deleted account, it has to be restored instead
+		taken, err := a.accountRepository.IsEmailTaken(ctx, identity.Email)
+
+		if err != nil {
+			return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+		}
+
+		if taken {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "An account with this email is deleted, restore it before logging in with identity provider"}
+		}
+
+		if account, err = a.createIdentityAccount(ctx, tx, identity); err != nil {
+			return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+		}
+	}
+
+	if err = a.accountIdentityRepository.Save(ctx, tx, &entities.AccountIdentity{
+		AccountID: account.ID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+	}); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	bootstrapAdmin(ctx, a.accountRepository, account.Email)
+
+	return account, nil
+}
+
+// createIdentityAccount creates account of a new identity, its email is verified by the provider already.
+func (a *accountService) createIdentityAccount(ctx context.Context, tx pkg.Tx, identity *pkg.ExternalIdentity) (*entities.Account, error) {
+	fullName := identity.Name
+	if fullName == "" {
+		fullName, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	avatarURL := identity.Picture
+	if len(avatarURL) > 500 {
+		avatarURL = ""
+	}
+
+	if err := a.accountRepository.CreateAccount(ctx, tx, &entities.Account{
+		Email:           identity.Email,
+		FullName:        fullName,
+		AvatarURL:       avatarURL,
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}); err != nil {
+		return nil, err
+	}
+
+	account := a.accountRepository.GetAccountByEmailX(ctx, tx, identity.Email)
+
+	if account == nil {
+		return nil, errors.New("created account is not found")
+	}
+
+	// nobody knows this password, the user can still set one with the forgot password flow
+	randomPassword, err := utils.GenerateRandomToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(randomPassword)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = a.accountPasswordRepository.CreateAccountPassword(ctx, tx, &entities.AccountPassword{
+		ID:       account.ID,
+		Password: hashedPassword,
+	}); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
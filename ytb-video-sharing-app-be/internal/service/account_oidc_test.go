@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakes embed the repository interfaces, calling a method the test does not expect panics.
+
+type fakeTx struct {
+	committed bool
+}
+
+func (f *fakeTx) Exec(ctx context.Context, sql string, args ...any) error       { return nil }
+func (f *fakeTx) QueryRow(ctx context.Context, sql string, args ...any) pkg.Row { return nil }
+func (f *fakeTx) Rollback(ctx context.Context) error                            { return nil }
+
+func (f *fakeTx) Commit(ctx context.Context) error {
+	f.committed = true
+	return nil
+}
+
+type fakeAccountRepository struct {
+	repository.AccountRepository
+	accounts      map[int64]*entities.Account
+	deletedEmails map[string]bool
+	created       []*entities.Account
+	tx            *fakeTx
+}
+
+func (f *fakeAccountRepository) GetAccountByID(ctx context.Context, id int64) *entities.Account {
+	return f.accounts[id]
+}
+
+func (f *fakeAccountRepository) GetAccountByEmailX(ctx context.Context, tx pkg.Tx, email string) *entities.Account {
+	for _, account := range f.accounts {
+		if account.Email == email {
+			return account
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeAccountRepository) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	return f.deletedEmails[email] || f.GetAccountByEmailX(ctx, nil, email) != nil, nil
+}
+
+func (f *fakeAccountRepository) CreateAccount(ctx context.Context, tx pkg.Tx, payload *entities.Account) error {
+	account := *payload
+	account.ID = int64(len(f.accounts) + 1)
+
+	f.accounts[account.ID] = &account
+	f.created = append(f.created, &account)
+
+	return nil
+}
+
+func (f *fakeAccountRepository) PromoteFirstAdmin(ctx context.Context, email string) error {
+	return pkg.ErrNoRowsAffected
+}
+
+func (f *fakeAccountRepository) BeginTransaction(ctx context.Context) (pkg.Tx, error) {
+	return f.tx, nil
+}
+
+type fakeAccountPasswordRepository struct {
+	repository.AccountPasswordRepository
+	created []*entities.AccountPassword
+}
+
+func (f *fakeAccountPasswordRepository) CreateAccountPassword(ctx context.Context, tx pkg.Tx, payload *entities.AccountPassword) error {
+	f.created = append(f.created, payload)
+	return nil
+}
+
+type fakeAccountIdentityRepository struct {
+	identities []*entities.AccountIdentity
+	saved      []*entities.AccountIdentity
+}
+
+func (f *fakeAccountIdentityRepository) GetIdentity(ctx context.Context, provider string, subject string) *entities.AccountIdentity {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeAccountIdentityRepository) Save(ctx context.Context, tx pkg.Tx, payload *entities.AccountIdentity) error {
+	f.saved = append(f.saved, payload)
+	return nil
+}
+
+type fakeOIDCLoginStateRepository struct {
+	states map[string]*entities.OIDCLoginState
+}
+
+func (f *fakeOIDCLoginStateRepository) Save(ctx context.Context, payload *entities.OIDCLoginState) error {
+	f.states[payload.StateHash] = payload
+	return nil
+}
+
+func (f *fakeOIDCLoginStateRepository) GetState(ctx context.Context, stateHash string) *entities.OIDCLoginState {
+	return f.states[stateHash]
+}
+
+func (f *fakeOIDCLoginStateRepository) Delete(ctx context.Context, stateHash string) error {
+	if _, ok := f.states[stateHash]; !ok {
+		return pkg.ErrNoRowsAffected
+	}
+
+	delete(f.states, stateHash)
+	return nil
+}
+
+func (f *fakeOIDCLoginStateRepository) DeleteExpired(ctx context.Context) error {
+	return nil
+}
+
+type fakeIdentityProvider struct {
+	exchanged bool
+}
+
+func (f *fakeIdentityProvider) Name() string { return "google" }
+
+func (f *fakeIdentityProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	return "https://idp.example.com/authorize?" + url.Values{"state": {state}, "code_challenge": {codeChallenge}}.Encode(), nil
+}
+
+func (f *fakeIdentityProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*pkg.ExternalIdentity, error) {
+	f.exchanged = true
+	return nil, errors.New("invalid grant")
+}
+
+type oidcConfig struct {
+	service          *accountService
+	accounts         *fakeAccountRepository
+	passwords        *fakeAccountPasswordRepository
+	identities       *fakeAccountIdentityRepository
+	loginStates      *fakeOIDCLoginStateRepository
+	identityProvider *fakeIdentityProvider
+}
+
+func setupOIDCConfig(t *testing.T, accounts ...*entities.Account) *oidcConfig {
+	t.Setenv("ADMIN_BOOTSTRAP_EMAIL", "")
+
+	cfg := &oidcConfig{
+		accounts:         &fakeAccountRepository{accounts: map[int64]*entities.Account{}, deletedEmails: map[string]bool{}, tx: &fakeTx{}},
+		passwords:        &fakeAccountPasswordRepository{},
+		identities:       &fakeAccountIdentityRepository{},
+		loginStates:      &fakeOIDCLoginStateRepository{states: map[string]*entities.OIDCLoginState{}},
+		identityProvider: &fakeIdentityProvider{},
+	}
+
+	for _, account := range accounts {
+		cfg.accounts.accounts[account.ID] = account
+	}
+
+	cfg.service = &accountService{
+		accountRepository:         cfg.accounts,
+		accountPasswordRepository: cfg.passwords,
+		identityProvider:          cfg.identityProvider,
+		accountIdentityRepository: cfg.identities,
+		oidcLoginStateRepository:  cfg.loginStates,
+	}
+
+	return cfg
+}
+
+func verifiedIdentity() *pkg.ExternalIdentity {
+	return &pkg.ExternalIdentity{Provider: "google", Subject: "sub-1", Email: "test@example.com", EmailVerified: true, Name: "Test User"}
+}
+
+func TestResolveIdentity(t *testing.T) {
+	verifiedAt := sql.NullTime{Time: time.Now(), Valid: true}
+
+	t.Run("Should return account the identity is linked to", func(t *testing.T) {
+		cfg := setupOIDCConfig(t, &entities.Account{ID: 7, Email: "old@example.com"})
+		cfg.identities.identities = []*entities.AccountIdentity{{AccountID: 7, Provider: "google", Subject: "sub-1"}}
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), verifiedIdentity())
+
+		assert.Nil(t, errRe)
+		assert.Equal(t, int64(7), account.ID)
+		assert.Empty(t, cfg.identities.saved)
+		assert.Empty(t, cfg.accounts.created)
+	})
+
+	t.Run("Should reject identity linked to an account that is gone", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		cfg.identities.identities = []*entities.AccountIdentity{{AccountID: 7, Provider: "google", Subject: "sub-1"}}
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), verifiedIdentity())
+
+		assert.Nil(t, account)
+		assert.Equal(t, http.StatusUnauthorized, errRe.Code)
+	})
+
+	t.Run("Should reject new identity with unverified email", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		identity := verifiedIdentity()
+		identity.EmailVerified = false
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), identity)
+
+		assert.Nil(t, account)
+		assert.Equal(t, http.StatusBadRequest, errRe.Code)
+		assert.Empty(t, cfg.identities.saved)
+	})
+
+	t.Run("Should link identity to account with the same verified email", func(t *testing.T) {
+		cfg := setupOIDCConfig(t, &entities.Account{ID: 7, Email: "test@example.com", EmailVerifiedAt: verifiedAt})
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), verifiedIdentity())
+
+		require.Nil(t, errRe)
+		assert.Equal(t, int64(7), account.ID)
+		assert.Empty(t, cfg.accounts.created)
+		require.Len(t, cfg.identities.saved, 1)
+		assert.Equal(t, &entities.AccountIdentity{AccountID: 7, Provider: "google", Subject: "sub-1", Email: "test@example.com"}, cfg.identities.saved[0])
+		assert.True(t, cfg.accounts.tx.committed)
+	})
+
+	t.Run("Should not link identity to account with the same unverified email", func(t *testing.T) {
+		cfg := setupOIDCConfig(t, &entities.Account{ID: 7, Email: "test@example.com"})
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), verifiedIdentity())
+
+		assert.Nil(t, account)
+		assert.Equal(t, http.StatusConflict, errRe.Code)
+		assert.Empty(t, cfg.identities.saved)
+		assert.False(t, cfg.accounts.tx.committed)
+	})
+
+	t.Run("Should not create account with email of a deleted account", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		cfg.accounts.deletedEmails["test@example.com"] = true
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), verifiedIdentity())
+
+		assert.Nil(t, account)
+		assert.Equal(t, http.StatusConflict, errRe.Code)
+		assert.Empty(t, cfg.accounts.created)
+		assert.Empty(t, cfg.identities.saved)
+	})
+
+	t.Run("Should create verified account with a password for a new identity", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		identity := verifiedIdentity()
+		identity.Picture = "https://idp.example.com/avatar.png"
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), identity)
+
+		require.Nil(t, errRe)
+		require.Len(t, cfg.accounts.created, 1)
+		assert.Equal(t, cfg.accounts.created[0], account)
+		assert.Equal(t, "test@example.com", account.Email)
+		assert.Equal(t, "Test User", account.FullName)
+		assert.Equal(t, identity.Picture, account.AvatarURL)
+		assert.True(t, account.EmailVerifiedAt.Valid)
+
+		require.Len(t, cfg.passwords.created, 1)
+		assert.Equal(t, account.ID, cfg.passwords.created[0].ID)
+		assert.NotEmpty(t, cfg.passwords.created[0].Password)
+
+		require.Len(t, cfg.identities.saved, 1)
+		assert.Equal(t, account.ID, cfg.identities.saved[0].AccountID)
+		assert.True(t, cfg.accounts.tx.committed)
+	})
+
+	t.Run("Should fall back to email for name and drop avatar URL that does not fit", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		identity := verifiedIdentity()
+		identity.Name = ""
+		identity.Picture = "https://idp.example.com/" + strings.Repeat("a", 500)
+
+		account, errRe := cfg.service.resolveIdentity(context.Background(), identity)
+
+		require.Nil(t, errRe)
+		assert.Equal(t, "test", account.FullName)
+		assert.Empty(t, account.AvatarURL)
+	})
+}
+
+func TestOIDCLoginStateBinding(t *testing.T) {
+	startLogin := func(t *testing.T, cfg *oidcConfig) (string, string) {
+		res, errRe := cfg.service.StartOIDCLogin(context.Background())
+		require.Nil(t, errRe)
+
+		authorizationURL, err := url.Parse(res.AuthorizationURL)
+		require.NoError(t, err)
+
+		return authorizationURL.Query().Get("state"), res.StateBinding
+	}
+
+	t.Run("Should bind login to hash of its state", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+
+		state, stateBinding := startLogin(t, cfg)
+
+		assert.NotEmpty(t, state)
+		assert.Equal(t, utils.HashToken(state), stateBinding)
+		assert.Contains(t, cfg.loginStates.states, stateBinding)
+	})
+
+	t.Run("Should reject callback from another browser and keep the state", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		state, _ := startLogin(t, cfg)
+		_, otherBinding := startLogin(t, cfg)
+
+		for _, binding := range []string{"", otherBinding} {
+			res, challenge, errRe := cfg.service.LoginWithOIDC(context.Background(), "code", state, binding, nil)
+
+			assert.Nil(t, res)
+			assert.Nil(t, challenge)
+			assert.Equal(t, http.StatusBadRequest, errRe.Code)
+		}
+
+		assert.False(t, cfg.identityProvider.exchanged)
+		assert.Contains(t, cfg.loginStates.states, utils.HashToken(state))
+	})
+
+	t.Run("Should accept callback from the browser that started the login", func(t *testing.T) {
+		cfg := setupOIDCConfig(t)
+		state, stateBinding := startLogin(t, cfg)
+
+		// the fake provider rejects every code, reaching it means the state was accepted
+		_, _, errRe := cfg.service.LoginWithOIDC(context.Background(), "code", state, stateBinding, nil)
+
+		assert.Equal(t, http.StatusUnauthorized, errRe.Code)
+		assert.True(t, cfg.identityProvider.exchanged)
+		assert.NotContains(t, cfg.loginStates.states, stateBinding)
+	})
+}
@@ -1,51 +1,127 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 	"ytb-video-sharing-app-be/internal/dto"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
 	"ytb-video-sharing-app-be/utils"
+
+	"github.com/google/uuid"
 )
 
 type AccountService interface {
-	CreateAccount(context.Context, *entities.Account, *entities.AccountPassword) (*dto.CreateAccountResponse, *dto.ErrorResponse)
+	CreateAccount(context.Context, *entities.Account, *entities.AccountPassword, *dto.SessionMetadata) (*dto.CreateAccountResponse, *dto.ErrorResponse)
+
+	// Login returns a challenge instead of tokens when 2FA is enabled, it is exchanged by LoginMFA.
+	Login(ctx context.Context, email string, password string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.MFAChallengeResponse, *dto.ErrorResponse)
+
+	LoginMFA(ctx context.Context, mfaToken string, code string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.ErrorResponse)
+
+	StartOIDCLogin(ctx context.Context) (*dto.OIDCAuthorizeResponse, *dto.ErrorResponse)
+
+	// LoginWithOIDC returns a challenge instead of tokens when 2FA is enabled, like Login.
+	// stateBinding is the StateBinding of the login start, kept by the browser, it must match state.
+	LoginWithOIDC(ctx context.Context, code string, state string, stateBinding string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.MFAChallengeResponse, *dto.ErrorResponse)
+
+	Logout(ctx context.Context, accountID int64, refreshToken string, accessTokenClaims *utils.UserClaims) (*dto.LogoutResponse, *dto.ErrorResponse)
+
+	RefreshToken(ctx context.Context, accountID int64, refreshToken string, meta *dto.SessionMetadata) (*dto.RefreshTokenResponse, *dto.ErrorResponse)
+
+	ListSessions(ctx context.Context, accountID int64, currentSessionID string) ([]dto.SessionResponse, *dto.ErrorResponse)
+
+	RevokeSession(ctx context.Context, accountID int64, sessionID string) (*dto.RevokeSessionResponse, *dto.ErrorResponse)
+
+	GetProfile(ctx context.Context, accountID int64) (*dto.ProfileResponse, *dto.ErrorResponse)
+
+	UpdateProfile(ctx context.Context, accountID int64, payload *dto.UpdateProfileRequest) (*dto.AccountResponse, *dto.ErrorResponse)
+
+	UpdateAvatar(ctx context.Context, accountID int64, image []byte) (*dto.AccountResponse, *dto.ErrorResponse)
 
-	Login(ctx context.Context, email string, password string) (*dto.LoginResponse, *dto.ErrorResponse)
+	ChangePassword(ctx context.Context, accountID int64, currentRefreshToken string, payload *dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, *dto.ErrorResponse)
 
-	Logout(ctx context.Context, accountID int64, refreshToken string) (*dto.LogoutResponse, *dto.ErrorResponse)
+	VerifyEmail(ctx context.Context, token string) (*dto.VerifyEmailResponse, *dto.ErrorResponse)
 
-	RefreshToken(ctx context.Context, accountID int64, refreshToken string) (*dto.RefreshTokenResponse, *dto.ErrorResponse)
+	ResendVerificationEmail(ctx context.Context, accountID int64) (*dto.ResendVerificationEmailResponse, *dto.ErrorResponse)
+
+	// DeleteAccount soft deletes own account and logs out all of its sessions, it is restorable until purged.
+	DeleteAccount(ctx context.Context, accountID int64, payload *dto.DeleteMyAccountRequest) (*dto.DeleteAccountResponse, *dto.ErrorResponse)
+
+	// RestoreAccount restores account its owner deleted, an account deleted by an admin is only restored by an admin.
+	RestoreAccount(ctx context.Context, payload *dto.RestoreAccountRequest, meta *dto.SessionMetadata) (*dto.RestoreAccountResponse, *dto.ErrorResponse)
 }
 
 type accountService struct {
-	accountRepository         repository.AccountRepository
-	accountPasswordRepository repository.AccountPasswordRepository
-	refreshTokenRepository    repository.RefreshTokenRepository
-	keyManager                *utils.KeyManager
+	accountRepository             repository.AccountRepository
+	accountPasswordRepository     repository.AccountPasswordRepository
+	refreshTokenRepository        repository.RefreshTokenRepository
+	mfaRepository                 repository.MFARepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	keyManager                    *utils.KeyManager
+	blobStore                     pkg.BlobStore
+	mailer                        pkg.Mailer
+	auditLogger                   pkg.AuditLogger
+	revocationList                pkg.RevocationList
+	loginThrottle                 pkg.LoginThrottle
+	identityProvider              pkg.IdentityProvider
+	accountIdentityRepository     repository.AccountIdentityRepository
+	oidcLoginStateRepository      repository.OIDCLoginStateRepository
 }
 
 func NewAccountService(acaccountRepository repository.AccountRepository,
 	accountPasswordRepository repository.AccountPasswordRepository,
 	keyManager *utils.KeyManager,
-	refreshTokenRepository repository.RefreshTokenRepository) AccountService {
+	refreshTokenRepository repository.RefreshTokenRepository,
+	mfaRepository repository.MFARepository,
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository,
+	blobStore pkg.BlobStore,
+	mailer pkg.Mailer,
+	auditLogger pkg.AuditLogger,
+	revocationList pkg.RevocationList,
+	loginThrottle pkg.LoginThrottle,
+	identityProvider pkg.IdentityProvider,
+	accountIdentityRepository repository.AccountIdentityRepository,
+	oidcLoginStateRepository repository.OIDCLoginStateRepository) AccountService {
 	return &accountService{
-		accountRepository:         acaccountRepository,
-		accountPasswordRepository: accountPasswordRepository,
-		keyManager:                keyManager,
-		refreshTokenRepository:    refreshTokenRepository,
+		accountRepository:             acaccountRepository,
+		accountPasswordRepository:     accountPasswordRepository,
+		keyManager:                    keyManager,
+		refreshTokenRepository:        refreshTokenRepository,
+		mfaRepository:                 mfaRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		blobStore:                     blobStore,
+		mailer:                        mailer,
+		auditLogger:                   auditLogger,
+		revocationList:                revocationList,
+		loginThrottle:                 loginThrottle,
+		identityProvider:              identityProvider,
+		accountIdentityRepository:     accountIdentityRepository,
+		oidcLoginStateRepository:      oidcLoginStateRepository,
 	}
 }
 
 // CreateAccount implements AccountService.
-func (a *accountService) CreateAccount(ctx context.Context, accountPayload *entities.Account, accountPasswordPayload *entities.AccountPassword) (*dto.CreateAccountResponse, *dto.ErrorResponse) {
-	// check email
-	if a.accountRepository.GetAccountByEmail(ctx, accountPayload.Email) != nil {
+func (a *accountService) CreateAccount(ctx context.Context, accountPayload *entities.Account, accountPasswordPayload *entities.AccountPassword, meta *dto.SessionMetadata) (*dto.CreateAccountResponse, *dto.ErrorResponse) {
+	// check email, a soft deleted account keeps its email until purged
+	taken, err := a.accountRepository.IsEmailTaken(ctx, accountPayload.Email)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if taken {
 		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Duplicate email, please try again!"}
 	}
 
@@ -85,7 +161,8 @@ func (a *accountService) CreateAccount(ctx context.Context, accountPayload *enti
 	}
 
 	// generate access token and refresh token
-	accessToken, refreshToken, errRe := a.generateTokens(account)
+	sessionID := uuid.NewString()
+	accessToken, refreshToken, errRe := a.generateTokens(account, sessionID)
 
 	if errRe != nil {
 		fmt.Println("error:", errRe)
@@ -94,9 +171,13 @@ func (a *accountService) CreateAccount(ctx context.Context, accountPayload *enti
 
 	// save refresh token into db
 	if err = a.refreshTokenRepository.Save(ctx, tx, &entities.RefreshToken{
-		AccountID: account.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Duration(a.getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")) * time.Hour * 24),
+		AccountID:   account.ID,
+		TokenHash:   utils.HashToken(refreshToken),
+		FamilyID:    sessionID,
+		DeviceLabel: deviceLabel(meta, ""),
+		UserAgent:   meta.UserAgent,
+		IPAddress:   meta.IPAddress,
+		ExpiresAt:   time.Now().Add(time.Duration(getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")) * time.Hour * 24),
 	}); err != nil {
 		return nil, &dto.ErrorResponse{Message: utils.INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
@@ -106,35 +187,183 @@ func (a *accountService) CreateAccount(ctx context.Context, accountPayload *enti
 		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
 	}
 
+	logAudit(ctx, a.auditLogger, "account_registered", account.ID, pkg.AuditTargetAccount, account.ID, "")
+
+	a.sendVerificationEmail(account)
+
 	return &dto.CreateAccountResponse{
 		AccessToken:     accessToken,
 		RefreshToken:    refreshToken,
-		AccountResponse: dto.AccountResponse(*account),
+		SessionID:       sessionID,
+		AccountResponse: toAccountResponse(account),
 	}, nil
 }
 
 // Login implements AccountService.
-func (a *accountService) Login(ctx context.Context, email string, password string) (*dto.LoginResponse, *dto.ErrorResponse) {
+//
+// Failed attempts are throttled per account and per IP, a throttled attempt is rejected before the password is checked.
+func (a *accountService) Login(ctx context.Context, email string, password string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.MFAChallengeResponse, *dto.ErrorResponse) {
+	if errRe := a.checkLoginThrottle(ctx, email, meta.IPAddress); errRe != nil {
+		return nil, nil, errRe
+	}
+
+	loginFail := &dto.ErrorResponse{Code: http.StatusBadRequest, Message: utils.LOGIN_FAIL}
+
 	// load account and account password
 	account := a.accountRepository.GetAccountByEmail(ctx, email)
 
 	if account == nil {
-		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: utils.LOGIN_FAIL}
+		return nil, nil, a.loginFailed(ctx, 0, email, meta.IPAddress, loginFail)
 	}
 
 	accountPassword := a.accountPasswordRepository.GetAccountPasswordByID(ctx, account.ID)
 
 	if accountPassword == nil {
-		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: utils.LOGIN_FAIL}
+		return nil, nil, a.loginFailed(ctx, account.ID, email, meta.IPAddress, loginFail)
 	}
 
 	// check matching password
 	if !utils.CheckPassword(accountPassword.Password, password) {
-		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: utils.LOGIN_FAIL}
+		return nil, nil, a.loginFailed(ctx, account.ID, email, meta.IPAddress, loginFail)
+	}
+
+	// only told once the password is right, so suspension does not reveal the account exists
+	if errRe := suspendedError(account); errRe != nil {
+		return nil, nil, errRe
+	}
+
+	// no session is created until the second factor is checked, failures are kept meanwhile
+	// so logging in again with the password can not reset guessing of codes
+	if challenge, errRe := a.mfaChallenge(ctx, account); challenge != nil || errRe != nil {
+		return nil, challenge, errRe
+	}
+
+	a.loginSucceeded(ctx, email)
+
+	res, errRe := a.createSession(ctx, account, meta, "password")
+
+	return res, nil, errRe
+}
+
+// LoginMFA implements AccountService.
+func (a *accountService) LoginMFA(ctx context.Context, mfaToken string, code string, meta *dto.SessionMetadata) (*dto.LoginResponse, *dto.ErrorResponse) {
+	claims, errRe := utils.ValidateToken(mfaToken, a.keyManager, utils.TokenUseMFAChallenge)
+
+	if errRe != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusUnauthorized, Message: "MFA token is invalid or expired"}
+	}
+
+	revoked, err := a.revocationList.IsRevoked(ctx, utils.TokenRevocationKey(claims.ID))
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if revoked {
+		return nil, &dto.ErrorResponse{Code: http.StatusUnauthorized, Message: "MFA token is invalid or expired"}
+	}
+
+	account := a.accountRepository.GetAccountByID(ctx, claims.AccountID)
+	mfa := a.mfaRepository.GetMFA(ctx, claims.AccountID)
+
+	// 2FA disabled since the challenge was issued means logging in again with password only
+	if account == nil || mfa == nil || !mfa.EnabledAt.Valid {
+		return nil, &dto.ErrorResponse{Code: http.StatusUnauthorized, Message: "MFA token is invalid or expired"}
+	}
+
+	if errRe = a.checkLoginThrottle(ctx, account.Email, meta.IPAddress); errRe != nil {
+		return nil, errRe
+	}
+
+	if errRe = checkSecondFactor(ctx, a.mfaRepository, a.auditLogger, mfa, code); errRe != nil {
+		if errRe.Code == http.StatusBadRequest {
+			return nil, a.loginFailed(ctx, account.ID, account.Email, meta.IPAddress, errRe)
+		}
+
+		return nil, errRe
+	}
+
+	a.loginSucceeded(ctx, account.Email)
+
+	// challenge is single use
+	if err = a.revocationList.Revoke(ctx, utils.TokenRevocationKey(claims.ID), claims.ExpiresAt.Time); err != nil {
+		log.Println("error when revoking mfa token: ", err)
+	}
+
+	return a.createSession(ctx, account, meta, "mfa")
+}
+
+// mfaChallenge returns the challenge exchanged by LoginMFA, nil when 2FA is not enabled.
+func (a *accountService) mfaChallenge(ctx context.Context, account *entities.Account) (*dto.MFAChallengeResponse, *dto.ErrorResponse) {
+	mfa := a.mfaRepository.GetMFA(ctx, account.ID)
+
+	if mfa == nil || !mfa.EnabledAt.Valid {
+		return nil, nil
+	}
+
+	lifetime := mfaChallengeLifetime()
+	mfaToken, errRe := utils.GenerateMFAChallengeToken(account, a.keyManager, lifetime)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	return &dto.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    mfaToken,
+		ExpiresIn:   int64(lifetime.Seconds()),
+	}, nil
+}
+
+func (a *accountService) checkLoginThrottle(ctx context.Context, email string, ip string) *dto.ErrorResponse {
+	wait, err := a.loginThrottle.Check(ctx, email, ip)
+
+	if err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if wait > 0 {
+		return &dto.ErrorResponse{Code: http.StatusTooManyRequests, Message: utils.TOO_MANY_LOGIN_FAILS, RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// loginFailed records the failure, errRe is replaced by 429 once the client has to wait before the next attempt.
+// accountID is 0 when no account has email.
+func (a *accountService) loginFailed(ctx context.Context, accountID int64, email string, ip string, errRe *dto.ErrorResponse) *dto.ErrorResponse {
+	logAudit(ctx, a.auditLogger, "login_failed", 0, pkg.AuditTargetAccount, accountID, email)
+
+	wait, err := a.loginThrottle.Fail(ctx, email, ip)
+
+	if err != nil {
+		log.Println("error when recording failed login: ", err)
+		return errRe
+	}
+
+	if wait > 0 {
+		return &dto.ErrorResponse{Code: http.StatusTooManyRequests, Message: utils.TOO_MANY_LOGIN_FAILS, RetryAfter: wait}
+	}
+
+	return errRe
+}
+
+func (a *accountService) loginSucceeded(ctx context.Context, email string) {
+	if err := a.loginThrottle.Succeed(ctx, email); err != nil {
+		log.Println("error when resetting failed logins: ", err)
+	}
+}
+
+// createSession issues tokens of a new session, its refresh token starts a new family.
+// method is how the account logged in, it is recorded in the audit log.
+func (a *accountService) createSession(ctx context.Context, account *entities.Account, meta *dto.SessionMetadata, method string) (*dto.LoginResponse, *dto.ErrorResponse) {
+	if errRe := suspendedError(account); errRe != nil {
+		return nil, errRe
 	}
 
 	// generate access token and refresh token
-	accessToken, refreshToken, err := a.generateTokens(account)
+	sessionID := uuid.NewString()
+	accessToken, refreshToken, err := a.generateTokens(account, sessionID)
 	if err != nil {
 		return nil, &dto.ErrorResponse{Code: err.Code, Message: err.Message}
 	}
@@ -149,9 +378,13 @@ func (a *accountService) Login(ctx context.Context, email string, password strin
 
 	// save refresh token into db
 	if errCommon = a.refreshTokenRepository.Save(ctx, tx, &entities.RefreshToken{
-		AccountID: account.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Duration(a.getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")) * time.Hour * 24),
+		AccountID:   account.ID,
+		TokenHash:   utils.HashToken(refreshToken),
+		FamilyID:    sessionID,
+		DeviceLabel: deviceLabel(meta, ""),
+		UserAgent:   meta.UserAgent,
+		IPAddress:   meta.IPAddress,
+		ExpiresAt:   time.Now().Add(time.Duration(getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")) * time.Hour * 24),
 	}); errCommon != nil {
 		return nil, &dto.ErrorResponse{Message: utils.INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
@@ -161,43 +394,52 @@ func (a *accountService) Login(ctx context.Context, email string, password strin
 		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
 	}
 
+	logAudit(ctx, a.auditLogger, "login_succeeded", account.ID, pkg.AuditTargetAccount, account.ID, method)
+
 	return &dto.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		AccountResponse: dto.AccountResponse{
-			ID:        account.ID,
-			Email:     account.Email,
-			FullName:  account.FullName,
-			AvatarURL: account.AvatarURL,
-		},
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		SessionID:       sessionID,
+		AccountResponse: toAccountResponse(account),
 	}, nil
 }
 
-func (a *accountService) Logout(ctx context.Context, accountID int64, refreshToken string) (*dto.LogoutResponse, *dto.ErrorResponse) {
-	if err := a.refreshTokenRepository.DeleteRefreshToken(ctx, accountID, refreshToken); err != nil {
+func (a *accountService) Logout(ctx context.Context, accountID int64, refreshToken string, accessTokenClaims *utils.UserClaims) (*dto.LogoutResponse, *dto.ErrorResponse) {
+	session := a.refreshTokenRepository.GetRefreshToken(ctx, accountID, utils.HashToken(refreshToken))
+
+	if err := a.refreshTokenRepository.DeleteRefreshToken(ctx, accountID, utils.HashToken(refreshToken)); err != nil {
 		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Refresh token is invalid"}
 	}
 
-	return &dto.LogoutResponse{}, nil
-}
-
-func (a *accountService) RefreshToken(ctx context.Context, accountID int64, refreshTokenStr string) (*dto.RefreshTokenResponse, *dto.ErrorResponse) {
-	oldRefreshToken := a.refreshTokenRepository.GetRefreshToken(ctx, accountID, refreshTokenStr)
+	// access token used to log out must not outlive the session
+	if accessTokenClaims.ExpiresAt != nil {
+		if err := a.revocationList.Revoke(ctx, utils.TokenRevocationKey(accessTokenClaims.ID), accessTokenClaims.ExpiresAt.Time); err != nil {
+			log.Println("error when revoking access token: ", err)
+		}
+	}
 
-	if oldRefreshToken == nil {
-		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Refresh token is invalid"}
+	if session != nil {
+		revokeSessions(ctx, a.revocationList, []*entities.RefreshToken{session}, "")
 	}
 
+	logAudit(ctx, a.auditLogger, "logout", accountID, pkg.AuditTargetAccount, accountID, "")
+
+	return &dto.LogoutResponse{}, nil
+}
+
+// RefreshToken rotates refresh token.
+//
+// The presented token is kept as used in its family, presenting it again means it was stolen,
+// so the whole family is revoked.
+func (a *accountService) RefreshToken(ctx context.Context, accountID int64, refreshTokenStr string, meta *dto.SessionMetadata) (*dto.RefreshTokenResponse, *dto.ErrorResponse) {
 	account := a.accountRepository.GetAccountByID(ctx, accountID)
 
 	if account == nil {
 		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Account is not found"}
 	}
 
-	accessToken, newRefreshToken, err := a.generateTokens(account)
-
-	if err != nil {
-		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	if errRe := suspendedError(account); errRe != nil {
+		return nil, errRe
 	}
 
 	// start transaction
@@ -208,9 +450,44 @@ func (a *accountService) RefreshToken(ctx context.Context, accountID int64, refr
 	}
 	defer tx.Rollback(ctx)
 
-	// update refresh token db
-	if errCommon = a.refreshTokenRepository.Update(ctx, tx, oldRefreshToken.ID, newRefreshToken,
-		time.Now().Add(time.Duration(a.getExpireTime("EXPIRE_TIME_REFRESH_TOKEN"))*time.Hour*24)); errCommon != nil {
+	// lock the row, so two concurrent requests can not both rotate the same token
+	oldRefreshToken := a.refreshTokenRepository.GetRefreshTokenForUpdate(ctx, tx, accountID, utils.HashToken(refreshTokenStr))
+
+	if oldRefreshToken == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Refresh token is invalid"}
+	}
+
+	if oldRefreshToken.UsedAt.Valid {
+		// release the lock before revoking the family
+		tx.Rollback(ctx)
+		a.revokeReusedFamily(ctx, oldRefreshToken)
+
+		return nil, &dto.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: utils.REFRESH_TOKEN_REUSED,
+			Cause:   &ReusedRefreshTokenError{SessionID: oldRefreshToken.FamilyID},
+		}
+	}
+
+	accessToken, newRefreshToken, err := a.generateTokens(account, oldRefreshToken.FamilyID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if errCommon = a.refreshTokenRepository.MarkUsed(ctx, tx, oldRefreshToken.ID); errCommon != nil {
+		return nil, &dto.ErrorResponse{Message: utils.INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
+	}
+
+	if errCommon = a.refreshTokenRepository.Save(ctx, tx, &entities.RefreshToken{
+		AccountID:   accountID,
+		TokenHash:   utils.HashToken(newRefreshToken),
+		FamilyID:    oldRefreshToken.FamilyID,
+		DeviceLabel: deviceLabel(meta, oldRefreshToken.DeviceLabel),
+		UserAgent:   meta.UserAgent,
+		IPAddress:   meta.IPAddress,
+		ExpiresAt:   time.Now().Add(time.Duration(getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")) * time.Hour * 24),
+	}); errCommon != nil {
 		return nil, &dto.ErrorResponse{Message: utils.INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
 
@@ -219,19 +496,483 @@ func (a *accountService) RefreshToken(ctx context.Context, accountID int64, refr
 		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
 	}
 
+	logAudit(ctx, a.auditLogger, "token_refreshed", accountID, pkg.AuditTargetAccount, accountID, fmt.Sprintf("session %s", oldRefreshToken.FamilyID))
+
 	return &dto.RefreshTokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken,
 	}, nil
 }
 
-func (a *accountService) generateTokens(account *entities.Account) (string, string, *dto.ErrorResponse) {
-	expireAccessToken := a.getExpireTime("EXPIRE_TIME_ACCESS_TOKEN")
-	expireRefreshToken := a.getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")
-	return utils.GenerateToken(account, a.keyManager, expireAccessToken, expireRefreshToken)
+// ReusedRefreshTokenError is the cause of the error RefreshToken returns when a refresh token is used twice,
+// the session it was issued for is revoked.
+type ReusedRefreshTokenError struct {
+	SessionID string
+}
+
+func (e *ReusedRefreshTokenError) Error() string {
+	return utils.REFRESH_TOKEN_REUSED
+}
+
+// revokeReusedFamily logs out the session of a refresh token used twice, access tokens already issued for it included.
+func (a *accountService) revokeReusedFamily(ctx context.Context, refreshToken *entities.RefreshToken) {
+	if err := a.refreshTokenRepository.DeleteFamily(ctx, refreshToken.FamilyID); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		log.Println("error when revoking refresh token family: ", err)
+	}
+
+	revokeSessions(ctx, a.revocationList, []*entities.RefreshToken{refreshToken}, "")
+
+	logAudit(ctx, a.auditLogger, "refresh_token_reuse", refreshToken.AccountID, pkg.AuditTargetAccount, refreshToken.AccountID,
+		fmt.Sprintf("token family %s revoked", refreshToken.FamilyID))
+}
+
+// GetProfile implements AccountService.
+func (a *accountService) GetProfile(ctx context.Context, accountID int64) (*dto.ProfileResponse, *dto.ErrorResponse) {
+	account := a.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	stats, err := a.accountRepository.GetAccountStats(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.ProfileResponse{
+		AccountResponse: toAccountResponse(account),
+		VideosShared:    stats.VideosShared,
+		UpvotesReceived: stats.UpvotesReceived,
+		FollowerCount:   stats.FollowerCount,
+		JoinedAt:        account.CreatedAt,
+	}, nil
+}
+
+// UpdateProfile implements AccountService.
+func (a *accountService) UpdateProfile(ctx context.Context, accountID int64, payload *dto.UpdateProfileRequest) (*dto.AccountResponse, *dto.ErrorResponse) {
+	account := a.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	// only overwrite fields that are present in payload
+	if payload.FullName != nil {
+		account.FullName = strings.TrimSpace(*payload.FullName)
+	}
+
+	if payload.Bio != nil {
+		account.Bio = strings.TrimSpace(*payload.Bio)
+	}
+
+	if account.FullName == "" {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Fullname must not be empty"}
+	}
+
+	if err := a.accountRepository.UpdateProfile(ctx, accountID, account.FullName, account.Bio); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := toAccountResponse(account)
+
+	return &res, nil
+}
+
+// UpdateAvatar implements AccountService.
+func (a *accountService) UpdateAvatar(ctx context.Context, accountID int64, image []byte) (*dto.AccountResponse, *dto.ErrorResponse) {
+	account := a.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	resized, ext, err := utils.ResizeImage(image, utils.AVATAR_SIZE)
+
+	if err != nil {
+		if errors.Is(err, utils.ErrUnsupportedImage) || errors.Is(err, utils.ErrImageTooLarge) {
+			return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Image is invalid"}
+	}
+
+	// random suffix so browsers and proxies do not serve the previous avatar from cache
+	key := fmt.Sprintf("avatars/%d-%s.%s", accountID, uuid.NewString(), ext)
+
+	avatarURL, err := a.blobStore.Put(ctx, key, bytes.NewReader(resized))
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = a.accountRepository.UpdateAvatarURL(ctx, accountID, avatarURL); err != nil {
+		// do not leave orphan blob behind
+		a.blobStore.Delete(ctx, key)
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// best effort, a leftover blob is only wasted space
+	if oldKey, ok := avatarBlobKey(account.AvatarURL, avatarURL, key, accountID); ok {
+		if err = a.blobStore.Delete(ctx, oldKey); err != nil {
+			log.Println("error when deleting old avatar: ", err)
+		}
+	}
+
+	account.AvatarURL = avatarURL
+	res := toAccountResponse(account)
+
+	return &res, nil
+}
+
+// avatarBlobKey returns key of the blob behind oldURL, if it is an avatar of the account uploaded to the same store as newURL.
+// Avatars taken from an identity provider live elsewhere and are never deleted.
+func avatarBlobKey(oldURL string, newURL string, newKey string, accountID int64) (string, bool) {
+	publicURL, ok := strings.CutSuffix(newURL, newKey)
+
+	if !ok || oldURL == "" {
+		return "", false
+	}
+
+	oldKey, ok := strings.CutPrefix(oldURL, publicURL)
+
+	if !ok || !strings.HasPrefix(oldKey, fmt.Sprintf("avatars/%d-", accountID)) {
+		return "", false
+	}
+
+	return oldKey, true
+}
+
+// ChangePassword implements AccountService.
+//
+// The session owning currentRefreshToken is kept, every other session of the account is revoked.
+func (a *accountService) ChangePassword(ctx context.Context, accountID int64, currentRefreshToken string, payload *dto.ChangePasswordRequest) (*dto.ChangePasswordResponse, *dto.ErrorResponse) {
+	accountPassword := a.accountPasswordRepository.GetAccountPasswordByID(ctx, accountID)
+
+	if accountPassword == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if !utils.CheckPassword(accountPassword.Password, payload.CurrentPassword) {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Current password is incorrect"}
+	}
+
+	hashedPassword, err := utils.HashPassword(payload.NewPassword)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// remember sessions before their refresh tokens are deleted, to revoke their access tokens too
+	sessions, err := a.refreshTokenRepository.GetActiveSessions(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// personal access tokens keep working regardless of password, so they are revoked too
+	tokens, err := a.personalAccessTokenRepository.GetTokens(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	sessions = append(sessions, personalAccessTokenSessions(tokens)...)
+
+	currentSessionID := ""
+	if current := a.refreshTokenRepository.GetRefreshToken(ctx, accountID, utils.HashToken(currentRefreshToken)); current != nil {
+		currentSessionID = current.FamilyID
+	}
+
+	// start transaction
+	tx, err := a.accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	if err = a.accountPasswordRepository.UpdateAccountPassword(ctx, tx, &entities.AccountPassword{
+		ID:       accountID,
+		Password: hashedPassword,
+	}); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// revoke sessions on other devices
+	if err = a.refreshTokenRepository.DeleteOtherRefreshTokens(ctx, tx, accountID, utils.HashToken(currentRefreshToken)); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = a.personalAccessTokenRepository.RevokeAll(ctx, tx, accountID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	revokeSessions(ctx, a.revocationList, sessions, currentSessionID)
+
+	logAudit(ctx, a.auditLogger, "password_changed", accountID, pkg.AuditTargetAccount, accountID, "")
+
+	revokedSessions := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		if session.FamilyID != currentSessionID {
+			revokedSessions = append(revokedSessions, session.FamilyID)
+		}
+	}
+
+	return &dto.ChangePasswordResponse{RevokedSessions: revokedSessions}, nil
+}
+
+// VerifyEmail implements AccountService.
+func (a *accountService) VerifyEmail(ctx context.Context, token string) (*dto.VerifyEmailResponse, *dto.ErrorResponse) {
+	claims, err := utils.ValidateEmailVerificationToken(token)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Verification token is invalid or expired"}
+	}
+
+	account := a.accountRepository.GetAccountByID(ctx, claims.AccountID)
+
+	// link is bound to the email it was sent to
+	if account == nil || account.Email != claims.Email {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Verification token is invalid or expired"}
+	}
+
+	// clicking the link twice is fine
+	if account.EmailVerifiedAt.Valid {
+		return &dto.VerifyEmailResponse{AccountResponse: toAccountResponse(account)}, nil
+	}
+
+	if err = a.accountRepository.MarkEmailVerified(ctx, account.ID, account.Email); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	account.EmailVerifiedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	bootstrapAdmin(ctx, a.accountRepository, account.Email)
+
+	return &dto.VerifyEmailResponse{AccountResponse: toAccountResponse(account)}, nil
+}
+
+// ResendVerificationEmail implements AccountService.
+func (a *accountService) ResendVerificationEmail(ctx context.Context, accountID int64) (*dto.ResendVerificationEmailResponse, *dto.ErrorResponse) {
+	account := a.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if account.EmailVerifiedAt.Valid {
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Email is already verified"}
+	}
+
+	a.sendVerificationEmail(account)
+
+	return &dto.ResendVerificationEmailResponse{}, nil
+}
+
+// sendVerificationEmail sends verification link in background, failure is only logged since user can ask to resend.
+func (a *accountService) sendVerificationEmail(account *entities.Account) {
+	expireTime := a.getEmailVerificationExpireTime()
+
+	token, err := utils.GenerateEmailVerificationToken(account, expireTime)
+
+	if err != nil {
+		log.Println("error when generating email verification token: ", err)
+		return
+	}
+
+	go func() {
+		if err := a.mailer.Send(context.Background(), &pkg.Mail{
+			To:      account.Email,
+			Subject: "Verify your email",
+			Body: fmt.Sprintf("Hi %s,\n\nUse the link below to verify your email. It expires in %d hours.\n\n%s",
+				account.FullName, int(expireTime.Hours()), verifyEmailLink(token)),
+		}); err != nil {
+			log.Println("error when sending verification mail: ", err)
+		}
+	}()
+}
+
+func (a *accountService) getEmailVerificationExpireTime() time.Duration {
+	expireTime, err := strconv.Atoi(os.Getenv("EXPIRE_TIME_EMAIL_VERIFICATION_TOKEN"))
+	if err != nil || expireTime <= 0 {
+		return 24 * time.Hour // default to 24 hours if not set or invalid
+	}
+	return time.Duration(expireTime) * time.Hour
+}
+
+func verifyEmailLink(token string) string {
+	return os.Getenv("EMAIL_VERIFICATION_URL") + "?token=" + url.QueryEscape(token)
+}
+
+// ListSessions implements AccountService.
+func (a *accountService) ListSessions(ctx context.Context, accountID int64, currentSessionID string) ([]dto.SessionResponse, *dto.ErrorResponse) {
+	sessions, err := a.refreshTokenRepository.GetActiveSessions(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		res = append(res, dto.SessionResponse{
+			ID:          session.FamilyID,
+			DeviceLabel: session.DeviceLabel,
+			UserAgent:   session.UserAgent,
+			IPAddress:   session.IPAddress,
+			LastUsedAt:  session.LastUsedAt,
+			Current:     session.FamilyID == currentSessionID,
+		})
+	}
+
+	return res, nil
+}
+
+// RevokeSession implements AccountService.
+func (a *accountService) RevokeSession(ctx context.Context, accountID int64, sessionID string) (*dto.RevokeSessionResponse, *dto.ErrorResponse) {
+	if err := a.refreshTokenRepository.DeleteSession(ctx, accountID, sessionID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Session is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	revokeSessions(ctx, a.revocationList, []*entities.RefreshToken{{FamilyID: sessionID}}, "")
+
+	logAudit(ctx, a.auditLogger, "session_revoked", accountID, pkg.AuditTargetAccount, accountID, fmt.Sprintf("session %s", sessionID))
+
+	return &dto.RevokeSessionResponse{}, nil
+}
+
+// DeleteAccount implements AccountService.
+func (a *accountService) DeleteAccount(ctx context.Context, accountID int64, payload *dto.DeleteMyAccountRequest) (*dto.DeleteAccountResponse, *dto.ErrorResponse) {
+	accountPassword := a.accountPasswordRepository.GetAccountPasswordByID(ctx, accountID)
+
+	if accountPassword == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if !utils.CheckPassword(accountPassword.Password, payload.Password) {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Password is incorrect"}
+	}
+
+	now := time.Now()
+
+	if err := a.accountRepository.SoftDeleteAccount(ctx, accountID, accountID, now); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if _, errRe := logoutEverywhere(ctx, a.accountRepository, a.refreshTokenRepository, a.personalAccessTokenRepository, a.revocationList, accountID); errRe != nil {
+		return nil, errRe
+	}
+
+	logAudit(ctx, a.auditLogger, "account_deleted", accountID, pkg.AuditTargetAccount, accountID, "")
+
+	restorableUntil := now.Add(softDeleteRetention())
+
+	return &dto.DeleteAccountResponse{RestorableUntil: &restorableUntil}, nil
+}
+
+// RestoreAccount implements AccountService.
+//
+// Attempts are throttled like logins, the password is checked the same way.
+func (a *accountService) RestoreAccount(ctx context.Context, payload *dto.RestoreAccountRequest, meta *dto.SessionMetadata) (*dto.RestoreAccountResponse, *dto.ErrorResponse) {
+	if errRe := a.checkLoginThrottle(ctx, payload.Email, meta.IPAddress); errRe != nil {
+		return nil, errRe
+	}
+
+	loginFail := &dto.ErrorResponse{Code: http.StatusBadRequest, Message: utils.LOGIN_FAIL}
+
+	account := a.accountRepository.GetDeletedAccountByEmail(ctx, payload.Email)
+
+	if account == nil {
+		return nil, a.loginFailed(ctx, 0, payload.Email, meta.IPAddress, loginFail)
+	}
+
+	accountPassword := a.accountPasswordRepository.GetAccountPasswordByID(ctx, account.ID)
+
+	if accountPassword == nil || !utils.CheckPassword(accountPassword.Password, payload.Password) {
+		return nil, a.loginFailed(ctx, account.ID, payload.Email, meta.IPAddress, loginFail)
+	}
+
+	a.loginSucceeded(ctx, payload.Email)
+
+	if account.DeletedBy.Int64 != account.ID {
+		return nil, &dto.ErrorResponse{Code: http.StatusForbidden, Message: "Account was deleted by an admin, contact support to restore it"}
+	}
+
+	if err := a.accountRepository.RestoreAccount(ctx, account.ID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Account is not deleted"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, a.auditLogger, "account_restored", account.ID, pkg.AuditTargetAccount, account.ID, "")
+
+	return &dto.RestoreAccountResponse{}, nil
+}
+
+// deviceLabel prefers label sent by client, then the fallback, then the one derived from user agent.
+func deviceLabel(meta *dto.SessionMetadata, fallback string) string {
+	if meta.DeviceLabel != "" {
+		return meta.DeviceLabel
+	}
+
+	if fallback != "" {
+		return fallback
+	}
+
+	return utils.DeviceLabel(meta.UserAgent)
+}
+
+// suspendedError rejects suspended account, telling until when and why.
+func suspendedError(account *entities.Account) *dto.ErrorResponse {
+	if !account.IsSuspended(time.Now()) {
+		return nil
+	}
+
+	message := utils.ACCOUNT_SUSPENDED
+	if account.SuspendedUntil.Valid {
+		message += " until " + account.SuspendedUntil.Time.Format(time.RFC3339)
+	}
+	if account.SuspensionReason != "" {
+		message += ": " + account.SuspensionReason
+	}
+
+	return &dto.ErrorResponse{Code: http.StatusForbidden, Message: message}
+}
+
+func toAccountResponse(account *entities.Account) dto.AccountResponse {
+	return dto.AccountResponse{
+		ID:            account.ID,
+		Email:         account.Email,
+		FullName:      account.FullName,
+		AvatarURL:     account.AvatarURL,
+		Bio:           account.Bio,
+		EmailVerified: account.EmailVerifiedAt.Valid,
+		Role:          string(account.Role),
+	}
+}
+
+func (a *accountService) generateTokens(account *entities.Account, sessionID string) (string, string, *dto.ErrorResponse) {
+	expireAccessToken := getExpireTime("EXPIRE_TIME_ACCESS_TOKEN")
+	expireRefreshToken := getExpireTime("EXPIRE_TIME_REFRESH_TOKEN")
+	return utils.GenerateToken(account, sessionID, a.keyManager, expireAccessToken, expireRefreshToken)
 }
 
-func (a *accountService) getExpireTime(envVar string) int {
+// getExpireTime reads token lifetime from envVar, in minutes for access tokens and days for refresh tokens.
+func getExpireTime(envVar string) int {
 	expireTime, err := strconv.Atoi(os.Getenv(envVar))
 	if err != nil {
 		return 24 // default to 24 hours or minutes if not set or invalid
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type AdminService interface {
+	// ListAccounts searches accounts, only soft deleted ones when deleted is true.
+	ListAccounts(ctx context.Context, search string, deleted bool, limit int, page int) ([]*dto.AdminAccountResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	UpdateRole(ctx context.Context, actorID int64, accountID int64, role entities.Role) (*dto.AccountResponse, *dto.ErrorResponse)
+
+	// Suspend suspends account and logs out all of its sessions.
+	Suspend(ctx context.Context, actorID int64, accountID int64, payload *dto.SuspendAccountRequest) (*dto.AdminAccountResponse, *dto.ErrorResponse)
+
+	Unsuspend(ctx context.Context, actorID int64, accountID int64) (*dto.AdminAccountResponse, *dto.ErrorResponse)
+
+	// ForceLogout deletes every refresh token of account, revokes access tokens already issued and personal access tokens.
+	ForceLogout(ctx context.Context, actorID int64, accountID int64) (*dto.ForceLogoutResponse, *dto.ErrorResponse)
+
+	// DeleteAccount soft deletes account and logs out all of its sessions, it is restorable until purged.
+	// With permanent it deletes account together with its videos for good, soft deleted or not.
+	DeleteAccount(ctx context.Context, actorID int64, accountID int64, permanent bool) (*dto.DeleteAccountResponse, *dto.ErrorResponse)
+
+	// RestoreAccount restores soft deleted account, whoever deleted it.
+	RestoreAccount(ctx context.Context, actorID int64, accountID int64) (*dto.AdminAccountResponse, *dto.ErrorResponse)
+}
+
+type adminService struct {
+	accountRepository             repository.AccountRepository
+	refreshTokenRepository        repository.RefreshTokenRepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	revocationList                pkg.RevocationList
+	auditLogger                   pkg.AuditLogger
+}
+
+func NewAdminService(accountRepository repository.AccountRepository,
+	refreshTokenRepository repository.RefreshTokenRepository,
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository,
+	revocationList pkg.RevocationList,
+	auditLogger pkg.AuditLogger) AdminService {
+	return &adminService{
+		accountRepository:             accountRepository,
+		refreshTokenRepository:        refreshTokenRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		revocationList:                revocationList,
+		auditLogger:                   auditLogger,
+	}
+}
+
+// ListAccounts implements AdminService.
+func (s *adminService) ListAccounts(ctx context.Context, search string, deleted bool, limit int, page int) ([]*dto.AdminAccountResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	accounts, totalItems, err := s.accountRepository.SearchAccounts(ctx, strings.TrimSpace(search), deleted, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]*dto.AdminAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		res = append(res, toAdminAccountResponse(account))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	return res, totalItems, totalPages, page < totalPages, page > 1, nil
+}
+
+// UpdateRole implements AdminService.
+func (s *adminService) UpdateRole(ctx context.Context, actorID int64, accountID int64, role entities.Role) (*dto.AccountResponse, *dto.ErrorResponse) {
+	// an admin demoting itself could leave no admin at all
+	if actorID == accountID {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "You can not change your own role"}
+	}
+
+	account := s.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if account.Role == role {
+		res := toAccountResponse(account)
+		return &res, nil
+	}
+
+	if err := s.accountRepository.UpdateRole(ctx, accountID, role); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "role_changed", actorID, pkg.AuditTargetAccount, accountID, fmt.Sprintf("%s -> %s", account.Role, role))
+
+	account.Role = role
+	res := toAccountResponse(account)
+
+	return &res, nil
+}
+
+// Suspend implements AdminService.
+func (s *adminService) Suspend(ctx context.Context, actorID int64, accountID int64, payload *dto.SuspendAccountRequest) (*dto.AdminAccountResponse, *dto.ErrorResponse) {
+	account, errRe := s.moderatedAccount(ctx, actorID, accountID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	var until sql.NullTime
+	if payload.DurationHours > 0 {
+		until = sql.NullTime{Time: time.Now().Add(time.Duration(payload.DurationHours) * time.Hour), Valid: true}
+	}
+
+	if err := s.accountRepository.Suspend(ctx, accountID, until, payload.Reason); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if _, errRe = logoutEverywhere(ctx, s.accountRepository, s.refreshTokenRepository, s.personalAccessTokenRepository, s.revocationList, accountID); errRe != nil {
+		return nil, errRe
+	}
+
+	logAudit(ctx, s.auditLogger, "account_suspended", actorID, pkg.AuditTargetAccount, accountID, payload.Reason)
+
+	account.SuspendedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	account.SuspendedUntil = until
+	account.SuspensionReason = payload.Reason
+
+	return toAdminAccountResponse(account), nil
+}
+
+// Unsuspend implements AdminService.
+func (s *adminService) Unsuspend(ctx context.Context, actorID int64, accountID int64) (*dto.AdminAccountResponse, *dto.ErrorResponse) {
+	account := s.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if err := s.accountRepository.Unsuspend(ctx, accountID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Account is not suspended"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "account_unsuspended", actorID, pkg.AuditTargetAccount, accountID, "")
+
+	account.SuspendedAt = sql.NullTime{}
+	account.SuspendedUntil = sql.NullTime{}
+	account.SuspensionReason = ""
+
+	return toAdminAccountResponse(account), nil
+}
+
+// ForceLogout implements AdminService.
+func (s *adminService) ForceLogout(ctx context.Context, actorID int64, accountID int64) (*dto.ForceLogoutResponse, *dto.ErrorResponse) {
+	if _, errRe := s.moderatedAccount(ctx, actorID, accountID); errRe != nil {
+		return nil, errRe
+	}
+
+	revoked, errRe := logoutEverywhere(ctx, s.accountRepository, s.refreshTokenRepository, s.personalAccessTokenRepository, s.revocationList, accountID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	logAudit(ctx, s.auditLogger, "account_force_logout", actorID, pkg.AuditTargetAccount, accountID, fmt.Sprintf("%d sessions", revoked))
+
+	return &dto.ForceLogoutResponse{RevokedSessions: revoked}, nil
+}
+
+// DeleteAccount implements AdminService.
+func (s *adminService) DeleteAccount(ctx context.Context, actorID int64, accountID int64, permanent bool) (*dto.DeleteAccountResponse, *dto.ErrorResponse) {
+	if permanent {
+		return s.purgeAccount(ctx, actorID, accountID)
+	}
+
+	if _, errRe := s.moderatedAccount(ctx, actorID, accountID); errRe != nil {
+		return nil, errRe
+	}
+
+	now := time.Now()
+
+	if err := s.accountRepository.SoftDeleteAccount(ctx, accountID, actorID, now); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if _, errRe := logoutEverywhere(ctx, s.accountRepository, s.refreshTokenRepository, s.personalAccessTokenRepository, s.revocationList, accountID); errRe != nil {
+		return nil, errRe
+	}
+
+	logAudit(ctx, s.auditLogger, "account_deleted", actorID, pkg.AuditTargetAccount, accountID, "")
+
+	restorableUntil := now.Add(softDeleteRetention())
+
+	return &dto.DeleteAccountResponse{RestorableUntil: &restorableUntil}, nil
+}
+
+// purgeAccount deletes account for good, a soft deleted account included.
+func (s *adminService) purgeAccount(ctx context.Context, actorID int64, accountID int64) (*dto.DeleteAccountResponse, *dto.ErrorResponse) {
+	account := s.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		account = s.accountRepository.GetDeletedAccountByID(ctx, accountID)
+	}
+
+	if errRe := checkModeratedAccount(actorID, accountID, account); errRe != nil {
+		return nil, errRe
+	}
+
+	// remember sessions before their refresh tokens are deleted by cascade, to revoke their access tokens too
+	sessions, err := s.refreshTokenRepository.GetActiveSessions(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = s.accountRepository.DeleteAccount(ctx, accountID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	revokeSessions(ctx, s.revocationList, sessions, "")
+
+	logAudit(ctx, s.auditLogger, "account_purged", actorID, pkg.AuditTargetAccount, accountID, "")
+
+	return &dto.DeleteAccountResponse{Permanent: true}, nil
+}
+
+// RestoreAccount implements AdminService.
+func (s *adminService) RestoreAccount(ctx context.Context, actorID int64, accountID int64) (*dto.AdminAccountResponse, *dto.ErrorResponse) {
+	account := s.accountRepository.GetDeletedAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Deleted account is not found"}
+	}
+
+	if err := s.accountRepository.RestoreAccount(ctx, accountID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Account is not deleted"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "account_restored", actorID, pkg.AuditTargetAccount, accountID, "")
+
+	account.DeletedAt = sql.NullTime{}
+	account.DeletedBy = sql.NullInt64{}
+
+	return toAdminAccountResponse(account), nil
+}
+
+// moderatedAccount returns account an admin may suspend, log out or delete, never the admin itself or another admin.
+func (s *adminService) moderatedAccount(ctx context.Context, actorID int64, accountID int64) (*entities.Account, *dto.ErrorResponse) {
+	account := s.accountRepository.GetAccountByID(ctx, accountID)
+
+	if errRe := checkModeratedAccount(actorID, accountID, account); errRe != nil {
+		return nil, errRe
+	}
+
+	return account, nil
+}
+
+// checkModeratedAccount refuses to moderate the admin itself, a missing account or another admin.
+func checkModeratedAccount(actorID int64, accountID int64, account *entities.Account) *dto.ErrorResponse {
+	if actorID == accountID {
+		return &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "You can not moderate your own account"}
+	}
+
+	if account == nil {
+		return &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if account.Role == entities.RoleAdmin {
+		return &dto.ErrorResponse{Code: http.StatusConflict, Message: "Admins can not be moderated, change their role first"}
+	}
+
+	return nil
+}
+
+func toAdminAccountResponse(account *entities.Account) *dto.AdminAccountResponse {
+	res := &dto.AdminAccountResponse{
+		AccountResponse:  toAccountResponse(account),
+		CreatedAt:        account.CreatedAt,
+		Suspended:        account.IsSuspended(time.Now()),
+		SuspensionReason: account.SuspensionReason,
+	}
+
+	if account.SuspendedAt.Valid {
+		res.SuspendedAt = &account.SuspendedAt.Time
+	}
+
+	if account.SuspendedUntil.Valid {
+		res.SuspendedUntil = &account.SuspendedUntil.Time
+	}
+
+	if account.DeletedAt.Valid {
+		res.DeletedAt = &account.DeletedAt.Time
+	}
+
+	return res
+}
+
+// BootstrapAdmin makes the account of ADMIN_BOOTSTRAP_EMAIL admin on startup while there is no admin yet.
+func BootstrapAdmin(accountRepository repository.AccountRepository) error {
+	bootstrapAdmin(context.Background(), accountRepository, "")
+	return nil
+}
+
+// bootstrapAdmin promotes account of ADMIN_BOOTSTRAP_EMAIL, email is the account just verified or created,
+// empty on startup. The account must have a verified email, so whoever registers the address first gains nothing.
+func bootstrapAdmin(ctx context.Context, accountRepository repository.AccountRepository, email string) {
+	adminEmail := strings.TrimSpace(os.Getenv("ADMIN_BOOTSTRAP_EMAIL"))
+
+	if adminEmail == "" || (email != "" && !strings.EqualFold(email, adminEmail)) {
+		return
+	}
+
+	err := accountRepository.PromoteFirstAdmin(ctx, adminEmail)
+
+	if err == nil {
+		log.Println("account of ", adminEmail, " is promoted to admin")
+		return
+	}
+
+	if !errors.Is(err, pkg.ErrNoRowsAffected) {
+		log.Println("error when bootstrapping admin: ", err)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math"
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type AuditService interface {
+	// ListEvents lists audit events matching filter, newest first.
+	ListEvents(ctx context.Context, filter *dto.AuditEventFilter, limit int, page int) ([]*dto.AuditEventResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// ListAccountActivity lists events account performed or was the target of, newest first.
+	// Who else acted on the account and from where is left out.
+	ListAccountActivity(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AuditEventResponse, int, int, bool, bool, *dto.ErrorResponse)
+}
+
+type auditService struct {
+	auditEventRepository repository.AuditEventRepository
+}
+
+func NewAuditService(auditEventRepository repository.AuditEventRepository) AuditService {
+	return &auditService{
+		auditEventRepository: auditEventRepository,
+	}
+}
+
+// ListEvents implements AuditService.
+func (s *auditService) ListEvents(ctx context.Context, filter *dto.AuditEventFilter, limit int, page int) ([]*dto.AuditEventResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	events, totalItems, err := s.auditEventRepository.List(ctx, &entities.AuditEventFilter{
+		ActorID:    filter.ActorID,
+		TargetType: filter.TargetType,
+		TargetID:   filter.TargetID,
+		Action:     filter.Action,
+		From:       filter.From,
+		To:         filter.To,
+	}, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return toAuditEventPage(events, totalItems, limit, page)
+}
+
+// ListAccountActivity implements AuditService.
+func (s *auditService) ListAccountActivity(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AuditEventResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	events, totalItems, err := s.auditEventRepository.ListByAccount(ctx, accountID, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// an admin or moderator acting on the account stays anonymous to it, only anonymous callers like failed
+	// logins keep their ip and user agent so the owner can tell where they came from
+	for _, event := range events {
+		if event.ActorID.Valid && event.ActorID.Int64 != accountID {
+			event.ActorID = sql.NullInt64{}
+			event.IPAddress = ""
+			event.UserAgent = ""
+			event.RequestID = ""
+		}
+	}
+
+	return toAuditEventPage(events, totalItems, limit, page)
+}
+
+func toAuditEventPage(events []*entities.AuditEvent, totalItems int, limit int, page int) ([]*dto.AuditEventResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	res := make([]*dto.AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		res = append(res, toAuditEventResponse(event))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	return res, totalItems, totalPages, page < totalPages, page > 1, nil
+}
+
+func toAuditEventResponse(event *entities.AuditEvent) *dto.AuditEventResponse {
+	res := &dto.AuditEventResponse{
+		ID:         event.ID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		Detail:     event.Detail,
+		IPAddress:  event.IPAddress,
+		UserAgent:  event.UserAgent,
+		RequestID:  event.RequestID,
+		CreatedAt:  event.CreatedAt,
+	}
+
+	if event.ActorID.Valid {
+		res.ActorID = &event.ActorID.Int64
+	}
+
+	if event.TargetID.Valid {
+		res.TargetID = &event.TargetID.Int64
+	}
+
+	return res
+}
+
+// logAudit writes audit event of actor acting on target, along with metadata of the request in ctx.
+// Failures are only logged so they never fail the request.
+func logAudit(ctx context.Context, auditLogger pkg.AuditLogger, action string, actorID int64, targetType string, targetID int64, detail string) {
+	meta := pkg.RequestMetadataFromContext(ctx)
+
+	if err := auditLogger.Log(ctx, &pkg.AuditEvent{
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Detail:     detail,
+		IPAddress:  meta.IPAddress,
+		UserAgent:  meta.UserAgent,
+		RequestID:  meta.RequestID,
+	}); err != nil {
+		log.Println("error when writing audit log: ", err)
+	}
+}
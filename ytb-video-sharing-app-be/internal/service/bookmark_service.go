@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type BookmarkService interface {
+	Bookmark(ctx context.Context, accountID, videoID int64) (*dto.BookmarkResponse, *dto.ErrorResponse)
+
+	DeleteBookmark(ctx context.Context, accountID, videoID int64) (*dto.DeleteBookmarkResponse, *dto.ErrorResponse)
+
+	GetBookmarkedVideos(ctx context.Context, accountID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+}
+
+type bookmarkService struct {
+	bookmarkRepository repository.BookmarkRepository
+	videoRepository    repository.VideoRepository
+	tagRepository      repository.TagRepository
+}
+
+func NewBookmarkService(bookmarkRepository repository.BookmarkRepository, videoRepository repository.VideoRepository, tagRepository repository.TagRepository) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepository: bookmarkRepository,
+		videoRepository:    videoRepository,
+		tagRepository:      tagRepository,
+	}
+}
+
+// Bookmark implements BookmarkService.
+func (b *bookmarkService) Bookmark(ctx context.Context, accountID, videoID int64) (*dto.BookmarkResponse, *dto.ErrorResponse) {
+	videos, err := b.videoRepository.GetVideosByIDs(ctx, []int64{videoID}, accountID)
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if len(videos) == 0 {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	if err := b.bookmarkRepository.Create(ctx, accountID, videoID); err != nil {
+		if errors.Is(err, pkg.ErrDuplicate) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "You already bookmarked this video"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.BookmarkResponse{
+		AccountID: accountID,
+		VideoID:   videoID,
+	}, nil
+}
+
+// DeleteBookmark implements BookmarkService.
+func (b *bookmarkService) DeleteBookmark(ctx context.Context, accountID, videoID int64) (*dto.DeleteBookmarkResponse, *dto.ErrorResponse) {
+	if err := b.bookmarkRepository.Delete(ctx, accountID, videoID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "You have not bookmarked this video"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.DeleteBookmarkResponse{}, nil
+}
+
+// GetBookmarkedVideos implements BookmarkService.
+func (b *bookmarkService) GetBookmarkedVideos(ctx context.Context, accountID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	videos, totalItems, err := b.bookmarkRepository.GetBookmarkedVideos(ctx, accountID, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	if err := attachTags(ctx, b.tagRepository, videos); err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	videoResponses := make([]*dto.VideoResponse, 0)
+	for _, video := range videos {
+		videoResponses = append(videoResponses, toVideoResponse(video))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	isNext := page < totalPages
+	isPrevious := page > 1
+
+	return videoResponses, totalItems, totalPages, isNext, isPrevious, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID, followingID int64) (*dto.FollowResponse, *dto.ErrorResponse)
+
+	Unfollow(ctx context.Context, followerID, followingID int64) (*dto.UnfollowResponse, *dto.ErrorResponse)
+
+	GetFollowers(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AccountResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	GetFollowing(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AccountResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	GetFollowerIDs(ctx context.Context, accountID int64) ([]int64, *dto.ErrorResponse)
+}
+
+type followService struct {
+	followRepository  repository.FollowRepository
+	accountRepository repository.AccountRepository
+}
+
+func NewFollowService(followRepository repository.FollowRepository, accountRepository repository.AccountRepository) FollowService {
+	return &followService{
+		followRepository:  followRepository,
+		accountRepository: accountRepository,
+	}
+}
+
+// Follow implements FollowService.
+func (f *followService) Follow(ctx context.Context, followerID, followingID int64) (*dto.FollowResponse, *dto.ErrorResponse) {
+	if followerID == followingID {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "You cannot follow yourself"}
+	}
+
+	if f.accountRepository.GetAccountByID(ctx, followingID) == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	if err := f.followRepository.Follow(ctx, followerID, followingID); err != nil {
+		if errors.Is(err, pkg.ErrDuplicate) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "You already follow this account"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.FollowResponse{
+		FollowerID:  followerID,
+		FollowingID: followingID,
+	}, nil
+}
+
+// Unfollow implements FollowService.
+func (f *followService) Unfollow(ctx context.Context, followerID, followingID int64) (*dto.UnfollowResponse, *dto.ErrorResponse) {
+	if err := f.followRepository.Unfollow(ctx, followerID, followingID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "You do not follow this account"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.UnfollowResponse{}, nil
+}
+
+// GetFollowers implements FollowService.
+func (f *followService) GetFollowers(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AccountResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	accounts, totalItems, err := f.followRepository.GetFollowers(ctx, accountID, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	return paginateAccounts(accounts, totalItems, limit, page)
+}
+
+// GetFollowing implements FollowService.
+func (f *followService) GetFollowing(ctx context.Context, accountID int64, limit int, page int) ([]*dto.AccountResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	accounts, totalItems, err := f.followRepository.GetFollowing(ctx, accountID, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	return paginateAccounts(accounts, totalItems, limit, page)
+}
+
+// GetFollowerIDs implements FollowService.
+func (f *followService) GetFollowerIDs(ctx context.Context, accountID int64) ([]int64, *dto.ErrorResponse) {
+	ids, err := f.followRepository.GetFollowerIDs(ctx, accountID)
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	return ids, nil
+}
+
+func paginateAccounts(accounts []*entities.Account, totalItems int, limit int, page int) ([]*dto.AccountResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	accountResponses := make([]*dto.AccountResponse, 0)
+	for _, account := range accounts {
+		accountResponse := toAccountResponse(account)
+		accountResponses = append(accountResponses, &accountResponse)
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	isNext := page < totalPages
+	isPrevious := page > 1
+
+	return accountResponses, totalItems, totalPages, isNext, isPrevious, nil
+}
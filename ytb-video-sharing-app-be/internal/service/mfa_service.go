@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes generated when 2FA is enabled
+	recoveryCodeCount = 10
+
+	invalidMFACode = "Authentication code is invalid"
+)
+
+type MFAService interface {
+	Enroll(ctx context.Context, accountID int64) (*dto.EnrollMFAResponse, *dto.ErrorResponse)
+
+	Verify(ctx context.Context, accountID int64, code string) (*dto.VerifyMFAResponse, *dto.ErrorResponse)
+
+	Disable(ctx context.Context, accountID int64, code string) (*dto.DisableMFAResponse, *dto.ErrorResponse)
+}
+
+type mfaService struct {
+	accountRepository repository.AccountRepository
+	mfaRepository     repository.MFARepository
+	auditLogger       pkg.AuditLogger
+}
+
+func NewMFAService(accountRepository repository.AccountRepository,
+	mfaRepository repository.MFARepository,
+	auditLogger pkg.AuditLogger) MFAService {
+	return &mfaService{
+		accountRepository: accountRepository,
+		mfaRepository:     mfaRepository,
+		auditLogger:       auditLogger,
+	}
+}
+
+// Enroll implements MFAService.
+//
+// The secret is pending until a code generated from it is verified, login is not affected before that.
+func (m *mfaService) Enroll(ctx context.Context, accountID int64) (*dto.EnrollMFAResponse, *dto.ErrorResponse) {
+	account := m.accountRepository.GetAccountByID(ctx, accountID)
+
+	if account == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Account is not found"}
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = m.mfaRepository.SaveSecret(ctx, accountID, secret); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Two-factor authentication is already enabled"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.EnrollMFAResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(mfaIssuer(), account.Email, secret),
+	}, nil
+}
+
+// Verify implements MFAService.
+func (m *mfaService) Verify(ctx context.Context, accountID int64, code string) (*dto.VerifyMFAResponse, *dto.ErrorResponse) {
+	mfa := m.mfaRepository.GetMFA(ctx, accountID)
+
+	if mfa == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Two-factor authentication is not enrolled"}
+	}
+
+	if mfa.EnabledAt.Valid {
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Two-factor authentication is already enabled"}
+	}
+
+	if errRe := useTOTPCode(ctx, m.mfaRepository, mfa, code); errRe != nil {
+		return nil, errRe
+	}
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes(recoveryCodeCount)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	codeHashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		codeHashes[i] = utils.HashToken(recoveryCode)
+	}
+
+	// start transaction
+	tx, err := m.accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	if err = m.mfaRepository.Enable(ctx, tx, accountID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = m.mfaRepository.ReplaceRecoveryCodes(ctx, tx, accountID, codeHashes); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, m.auditLogger, "mfa_enabled", accountID, pkg.AuditTargetAccount, accountID, "")
+
+	return &dto.VerifyMFAResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// Disable implements MFAService.
+func (m *mfaService) Disable(ctx context.Context, accountID int64, code string) (*dto.DisableMFAResponse, *dto.ErrorResponse) {
+	mfa := m.mfaRepository.GetMFA(ctx, accountID)
+
+	if mfa == nil || !mfa.EnabledAt.Valid {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Two-factor authentication is not enabled"}
+	}
+
+	if errRe := checkSecondFactor(ctx, m.mfaRepository, m.auditLogger, mfa, code); errRe != nil {
+		return nil, errRe
+	}
+
+	// start transaction
+	tx, err := m.accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	if err = m.mfaRepository.Delete(ctx, tx, accountID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, m.auditLogger, "mfa_disabled", accountID, pkg.AuditTargetAccount, accountID, "")
+
+	return &dto.DisableMFAResponse{}, nil
+}
+
+// checkSecondFactor accepts a TOTP code or an unused recovery code, either is consumed once accepted.
+func checkSecondFactor(ctx context.Context, mfaRepository repository.MFARepository, auditLogger pkg.AuditLogger, mfa *entities.AccountMFA, code string) *dto.ErrorResponse {
+	if _, err := strconv.Atoi(code); err == nil {
+		return useTOTPCode(ctx, mfaRepository, mfa, code)
+	}
+
+	err := mfaRepository.UseRecoveryCode(ctx, mfa.AccountID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+
+	if errors.Is(err, pkg.ErrNoRowsAffected) {
+		return &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidMFACode}
+	}
+
+	if err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, auditLogger, "mfa_recovery_code_used", mfa.AccountID, pkg.AuditTargetAccount, mfa.AccountID, "")
+
+	return nil
+}
+
+// useTOTPCode checks code and marks its step as used, so the same code can not be replayed.
+func useTOTPCode(ctx context.Context, mfaRepository repository.MFARepository, mfa *entities.AccountMFA, code string) *dto.ErrorResponse {
+	step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+
+	if !ok || step <= mfa.LastUsedStep {
+		return &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidMFACode}
+	}
+
+	err := mfaRepository.UseStep(ctx, mfa.AccountID, step)
+
+	if errors.Is(err, pkg.ErrNoRowsAffected) {
+		return &dto.ErrorResponse{Code: http.StatusBadRequest, Message: invalidMFACode}
+	}
+
+	if err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return nil
+}
+
+// mfaIssuer is the name authenticator apps show next to the code.
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "YTB Video Sharing"
+}
+
+// mfaChallengeLifetime is how long the user has to enter the code after the password was checked.
+func mfaChallengeLifetime() time.Duration {
+	expireTime, err := strconv.Atoi(os.Getenv("EXPIRE_TIME_MFA_CHALLENGE"))
+	if err != nil || expireTime <= 0 {
+		return 5 * time.Minute // default to 5 minutes if not set or invalid
+	}
+	return time.Duration(expireTime) * time.Minute
+}
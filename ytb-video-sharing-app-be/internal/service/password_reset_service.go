@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// forgotPasswordMessage is returned whether or not the email exists, so the endpoint cannot be used to enumerate accounts.
+const forgotPasswordMessage = "If the email is registered, a reset link has been sent to it"
+
+type PasswordResetService interface {
+	ForgotPassword(ctx context.Context, email string) (*dto.ForgotPasswordResponse, *dto.ErrorResponse)
+
+	ResetPassword(ctx context.Context, token string, newPassword string) (*dto.ResetPasswordResponse, *dto.ErrorResponse)
+}
+
+type passwordResetService struct {
+	accountRepository             repository.AccountRepository
+	accountPasswordRepository     repository.AccountPasswordRepository
+	refreshTokenRepository        repository.RefreshTokenRepository
+	passwordResetTokenRepository  repository.PasswordResetTokenRepository
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	mailer                        pkg.Mailer
+	revocationList                pkg.RevocationList
+	auditLogger                   pkg.AuditLogger
+}
+
+func NewPasswordResetService(accountRepository repository.AccountRepository,
+	accountPasswordRepository repository.AccountPasswordRepository,
+	refreshTokenRepository repository.RefreshTokenRepository,
+	passwordResetTokenRepository repository.PasswordResetTokenRepository,
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository,
+	mailer pkg.Mailer,
+	revocationList pkg.RevocationList,
+	auditLogger pkg.AuditLogger) PasswordResetService {
+	return &passwordResetService{
+		accountRepository:             accountRepository,
+		accountPasswordRepository:     accountPasswordRepository,
+		refreshTokenRepository:        refreshTokenRepository,
+		passwordResetTokenRepository:  passwordResetTokenRepository,
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		mailer:                        mailer,
+		revocationList:                revocationList,
+		auditLogger:                   auditLogger,
+	}
+}
+
+// ForgotPassword implements PasswordResetService.
+func (p *passwordResetService) ForgotPassword(ctx context.Context, email string) (*dto.ForgotPasswordResponse, *dto.ErrorResponse) {
+	response := &dto.ForgotPasswordResponse{Message: forgotPasswordMessage}
+
+	account := p.accountRepository.GetAccountByEmail(ctx, email)
+
+	if account == nil {
+		return response, nil
+	}
+
+	token, err := utils.GenerateRandomToken()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// only the latest requested link is valid
+	if err = p.passwordResetTokenRepository.DeleteUnusedTokens(ctx, account.ID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	expireTime := p.getExpireTime()
+
+	if err = p.passwordResetTokenRepository.Save(ctx, &entities.PasswordResetToken{
+		AccountID: account.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(expireTime),
+	}); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// send in background, so response time does not tell whether the email exists
+	go func() {
+		if err := p.mailer.Send(context.Background(), &pkg.Mail{
+			To:      account.Email,
+			Subject: "Reset your password",
+			Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. It expires in %d minutes.\n\n%s\n\nIf you did not request this, you can ignore this email.",
+				account.FullName, int(expireTime.Minutes()), resetPasswordLink(token)),
+		}); err != nil {
+			log.Println("error when sending reset password mail: ", err)
+		}
+	}()
+
+	return response, nil
+}
+
+// ResetPassword implements PasswordResetService.
+func (p *passwordResetService) ResetPassword(ctx context.Context, token string, newPassword string) (*dto.ResetPasswordResponse, *dto.ErrorResponse) {
+	hashedPassword, err := utils.HashPassword(newPassword)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// start transaction
+	tx, err := p.accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	// lock the token row, so it cannot be used twice concurrently
+	resetToken := p.passwordResetTokenRepository.GetTokenByHashForUpdate(ctx, tx, utils.HashToken(token))
+
+	if resetToken == nil || resetToken.UsedAt.Valid || resetToken.ExpiresAt.Before(time.Now()) {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Reset token is invalid or expired"}
+	}
+
+	if err = p.accountPasswordRepository.UpdateAccountPassword(ctx, tx, &entities.AccountPassword{
+		ID:       resetToken.AccountID,
+		Password: hashedPassword,
+	}); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = p.passwordResetTokenRepository.MarkUsed(ctx, tx, resetToken.ID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// remember sessions before their refresh tokens are gone, to revoke their access tokens too
+	sessions, err := p.refreshTokenRepository.GetActiveSessions(ctx, resetToken.AccountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	tokens, err := p.personalAccessTokenRepository.GetTokens(ctx, resetToken.AccountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// whoever knew the old password must not stay logged in
+	if err = p.refreshTokenRepository.DeleteAllRefreshTokens(ctx, tx, resetToken.AccountID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = p.personalAccessTokenRepository.RevokeAll(ctx, tx, resetToken.AccountID); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	revokeSessions(ctx, p.revocationList, append(sessions, personalAccessTokenSessions(tokens)...), "")
+
+	logAudit(ctx, p.auditLogger, "password_reset", resetToken.AccountID, pkg.AuditTargetAccount, resetToken.AccountID, "")
+
+	return &dto.ResetPasswordResponse{}, nil
+}
+
+func (p *passwordResetService) getExpireTime() time.Duration {
+	expireTime, err := strconv.Atoi(os.Getenv("EXPIRE_TIME_PASSWORD_RESET_TOKEN"))
+	if err != nil || expireTime <= 0 {
+		return 30 * time.Minute // default to 30 minutes if not set or invalid
+	}
+	return time.Duration(expireTime) * time.Minute
+}
+
+func resetPasswordLink(token string) string {
+	return os.Getenv("PASSWORD_RESET_URL") + "?token=" + url.QueryEscape(token)
+}
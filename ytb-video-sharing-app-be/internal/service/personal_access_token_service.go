@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// maxPersonalAccessTokens is the number of tokens an account may have at the same time.
+const maxPersonalAccessTokens = 50
+
+type PersonalAccessTokenService interface {
+	Create(ctx context.Context, accountID int64, payload *dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, *dto.ErrorResponse)
+
+	List(ctx context.Context, accountID int64) ([]dto.PersonalAccessTokenResponse, *dto.ErrorResponse)
+
+	Revoke(ctx context.Context, accountID int64, id int64) (*dto.RevokePersonalAccessTokenResponse, *dto.ErrorResponse)
+}
+
+type personalAccessTokenService struct {
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository
+	revocationList                pkg.RevocationList
+	auditLogger                   pkg.AuditLogger
+}
+
+func NewPersonalAccessTokenService(personalAccessTokenRepository repository.PersonalAccessTokenRepository,
+	revocationList pkg.RevocationList,
+	auditLogger pkg.AuditLogger) PersonalAccessTokenService {
+	return &personalAccessTokenService{
+		personalAccessTokenRepository: personalAccessTokenRepository,
+		revocationList:                revocationList,
+		auditLogger:                   auditLogger,
+	}
+}
+
+// Create implements PersonalAccessTokenService.
+func (p *personalAccessTokenService) Create(ctx context.Context, accountID int64, payload *dto.CreatePersonalAccessTokenRequest) (*dto.CreatePersonalAccessTokenResponse, *dto.ErrorResponse) {
+	tokens, err := p.personalAccessTokenRepository.GetTokens(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if len(tokens) >= maxPersonalAccessTokens {
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Too many personal access tokens, revoke unused ones first"}
+	}
+
+	token, prefix, err := utils.GeneratePersonalAccessToken()
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	personalAccessToken := &entities.PersonalAccessToken{
+		AccountID:   accountID,
+		Name:        payload.Name,
+		TokenPrefix: prefix,
+		TokenHash:   utils.HashToken(token),
+		Scopes:      uniqueScopes(payload.Scopes),
+		CreatedAt:   time.Now(),
+	}
+
+	if payload.ExpiresInDays > 0 {
+		personalAccessToken.ExpiresAt = sql.NullTime{Time: time.Now().AddDate(0, 0, payload.ExpiresInDays), Valid: true}
+	}
+
+	if personalAccessToken.ID, err = p.personalAccessTokenRepository.Create(ctx, personalAccessToken); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, p.auditLogger, "personal_access_token_created", accountID, pkg.AuditTargetPersonalAccessToken, personalAccessToken.ID, "")
+
+	return &dto.CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(personalAccessToken),
+		Token:                       token,
+	}, nil
+}
+
+// List implements PersonalAccessTokenService.
+func (p *personalAccessTokenService) List(ctx context.Context, accountID int64) ([]dto.PersonalAccessTokenResponse, *dto.ErrorResponse) {
+	tokens, err := p.personalAccessTokenRepository.GetTokens(ctx, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]dto.PersonalAccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		res = append(res, toPersonalAccessTokenResponse(token))
+	}
+
+	return res, nil
+}
+
+// Revoke implements PersonalAccessTokenService.
+func (p *personalAccessTokenService) Revoke(ctx context.Context, accountID int64, id int64) (*dto.RevokePersonalAccessTokenResponse, *dto.ErrorResponse) {
+	if err := p.personalAccessTokenRepository.Revoke(ctx, accountID, id); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Personal access token is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// websocket otps already handed out for the token must not open a connection anymore
+	revokeSessions(ctx, p.revocationList, []*entities.RefreshToken{{FamilyID: utils.PersonalAccessTokenSessionID(id)}}, "")
+
+	logAudit(ctx, p.auditLogger, "personal_access_token_revoked", accountID, pkg.AuditTargetPersonalAccessToken, id, "")
+
+	return &dto.RevokePersonalAccessTokenResponse{}, nil
+}
+
+func uniqueScopes(scopes []string) []string {
+	unique := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique
+}
+
+func toPersonalAccessTokenResponse(token *entities.PersonalAccessToken) dto.PersonalAccessTokenResponse {
+	res := dto.PersonalAccessTokenResponse{
+		ID:        token.ID,
+		Name:      token.Name,
+		Prefix:    token.TokenPrefix,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt,
+	}
+
+	if token.ExpiresAt.Valid {
+		res.ExpiresAt = &token.ExpiresAt.Time
+	}
+
+	if token.LastUsedAt.Valid {
+		res.LastUsedAt = &token.LastUsedAt.Time
+	}
+
+	return res
+}
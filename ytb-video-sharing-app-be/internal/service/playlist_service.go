@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// maxPlaylistVideos is how many videos a playlist can hold, reordering rewrites positions of up to this many rows.
+const maxPlaylistVideos = 500
+
+const playlistNotFound = "Playlist is not found"
+
+type PlaylistService interface {
+	Create(ctx context.Context, accountID int64, payload *dto.CreatePlaylistRequest) (*dto.PlaylistResponse, *dto.ErrorResponse)
+
+	// GetPlaylist returns playlist, private ones only to their owner viewerID (0 for anonymous viewers).
+	GetPlaylist(ctx context.Context, viewerID int64, playlistID int64) (*dto.PlaylistResponse, *dto.ErrorResponse)
+
+	// GetPlaylistsByAccount lists playlists of account newest first, only public ones unless viewerID is the account.
+	GetPlaylistsByAccount(ctx context.Context, accountID int64, viewerID int64, limit int, page int) ([]*dto.PlaylistResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// Update renames playlist of account or changes its visibility.
+	Update(ctx context.Context, accountID int64, playlistID int64, payload *dto.UpdatePlaylistRequest) (*dto.PlaylistResponse, *dto.ErrorResponse)
+
+	Delete(ctx context.Context, accountID int64, playlistID int64) (*dto.DeletePlaylistResponse, *dto.ErrorResponse)
+
+	// GetVideos lists videos of playlist in order. Videos viewerID can not see are left out of the page.
+	GetVideos(ctx context.Context, viewerID int64, playlistID int64, limit int, page int) ([]*dto.PlaylistVideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// AddVideo appends video to playlist of account.
+	AddVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64) (*dto.PlaylistItemResponse, *dto.ErrorResponse)
+
+	RemoveVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64) (*dto.PlaylistItemResponse, *dto.ErrorResponse)
+
+	// MoveVideo moves video of playlist of account to position, shifting the videos in between.
+	MoveVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64, position int) (*dto.PlaylistItemResponse, *dto.ErrorResponse)
+}
+
+type playlistService struct {
+	playlistRepository repository.PlaylistRepository
+	videoRepository    repository.VideoRepository
+	tagRepository      repository.TagRepository
+}
+
+func NewPlaylistService(playlistRepository repository.PlaylistRepository,
+	videoRepository repository.VideoRepository,
+	tagRepository repository.TagRepository) PlaylistService {
+	return &playlistService{
+		playlistRepository: playlistRepository,
+		videoRepository:    videoRepository,
+		tagRepository:      tagRepository,
+	}
+}
+
+// Create implements PlaylistService.
+func (s *playlistService) Create(ctx context.Context, accountID int64, payload *dto.CreatePlaylistRequest) (*dto.PlaylistResponse, *dto.ErrorResponse) {
+	name := strings.TrimSpace(payload.Name)
+
+	if name == "" {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Playlist name is required"}
+	}
+
+	visibility := entities.PlaylistVisibility(payload.Visibility)
+	if visibility == "" {
+		visibility = entities.PlaylistPrivate
+	}
+
+	id, err := s.playlistRepository.Create(ctx, &entities.Playlist{
+		AccountID:  accountID,
+		Name:       name,
+		Visibility: visibility,
+	})
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	playlist := s.playlistRepository.GetPlaylist(ctx, id)
+
+	if playlist == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return toPlaylistResponse(playlist), nil
+}
+
+// GetPlaylist implements PlaylistService.
+func (s *playlistService) GetPlaylist(ctx context.Context, viewerID int64, playlistID int64) (*dto.PlaylistResponse, *dto.ErrorResponse) {
+	playlist := s.playlistRepository.GetPlaylist(ctx, playlistID)
+
+	if playlist == nil || !playlist.VisibleTo(viewerID) {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: playlistNotFound}
+	}
+
+	return toPlaylistResponse(playlist), nil
+}
+
+// GetPlaylistsByAccount implements PlaylistService.
+func (s *playlistService) GetPlaylistsByAccount(ctx context.Context, accountID int64, viewerID int64, limit int, page int) ([]*dto.PlaylistResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	playlists, totalItems, err := s.playlistRepository.GetPlaylistsByAccount(ctx, accountID, accountID == viewerID, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]*dto.PlaylistResponse, 0, len(playlists))
+	for _, playlist := range playlists {
+		res = append(res, toPlaylistResponse(playlist))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	return res, totalItems, totalPages, page < totalPages, page > 1, nil
+}
+
+// Update implements PlaylistService.
+func (s *playlistService) Update(ctx context.Context, accountID int64, playlistID int64, payload *dto.UpdatePlaylistRequest) (*dto.PlaylistResponse, *dto.ErrorResponse) {
+	playlist, errRe := s.getOwnPlaylist(ctx, accountID, playlistID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	name := playlist.Name
+	if payload.Name != nil {
+		name = strings.TrimSpace(*payload.Name)
+	}
+
+	if name == "" {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Playlist name is required"}
+	}
+
+	visibility := playlist.Visibility
+	if payload.Visibility != nil {
+		visibility = entities.PlaylistVisibility(*payload.Visibility)
+	}
+
+	// nothing changed is fine, the playlist is returned as it is
+	if err := s.playlistRepository.Update(ctx, playlistID, name, visibility); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	updated := s.playlistRepository.GetPlaylist(ctx, playlistID)
+
+	if updated == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: playlistNotFound}
+	}
+
+	return toPlaylistResponse(updated), nil
+}
+
+// Delete implements PlaylistService.
+func (s *playlistService) Delete(ctx context.Context, accountID int64, playlistID int64) (*dto.DeletePlaylistResponse, *dto.ErrorResponse) {
+	if _, errRe := s.getOwnPlaylist(ctx, accountID, playlistID); errRe != nil {
+		return nil, errRe
+	}
+
+	if err := s.playlistRepository.Delete(ctx, playlistID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: playlistNotFound}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.DeletePlaylistResponse{PlaylistID: playlistID}, nil
+}
+
+// GetVideos implements PlaylistService.
+func (s *playlistService) GetVideos(ctx context.Context, viewerID int64, playlistID int64, limit int, page int) ([]*dto.PlaylistVideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	playlist := s.playlistRepository.GetPlaylist(ctx, playlistID)
+
+	if playlist == nil || !playlist.VisibleTo(viewerID) {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusNotFound, Message: playlistNotFound}
+	}
+
+	items, totalItems, err := s.playlistRepository.GetItems(ctx, playlistID, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	videoIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		videoIDs = append(videoIDs, item.VideoID)
+	}
+
+	videos, err := s.videoRepository.GetVideosByIDs(ctx, videoIDs, viewerID)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = attachTags(ctx, s.tagRepository, videos); err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	byID := make(map[int64]*entities.Video, len(videos))
+	for _, video := range videos {
+		byID[video.ID] = video
+	}
+
+	// hidden and soft deleted videos keep their position, they are only skipped
+	res := make([]*dto.PlaylistVideoResponse, 0, len(items))
+	for _, item := range items {
+		if video, ok := byID[item.VideoID]; ok {
+			res = append(res, &dto.PlaylistVideoResponse{
+				Position: item.Position,
+				AddedAt:  item.AddedAt,
+				Video:    *toVideoResponse(video),
+			})
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	return res, totalItems, totalPages, page < totalPages, page > 1, nil
+}
+
+// AddVideo implements PlaylistService.
+func (s *playlistService) AddVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64) (*dto.PlaylistItemResponse, *dto.ErrorResponse) {
+	videos, err := s.videoRepository.GetVideosByIDs(ctx, []int64{videoID}, accountID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if len(videos) == 0 {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	// start transaction
+	tx, playlist, errRe := s.lockOwnPlaylist(ctx, accountID, playlistID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = s.playlistRepository.GetVideoPosition(ctx, tx, playlistID, videoID); err == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Video is already in the playlist"}
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if playlist.VideoCount >= maxPlaylistVideos {
+		return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Playlist is full"}
+	}
+
+	position := playlist.VideoCount + 1
+
+	if err = s.playlistRepository.AddVideo(ctx, tx, playlistID, videoID, position); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if errRe = s.touchAndCommit(ctx, tx, playlistID); errRe != nil {
+		return nil, errRe
+	}
+
+	return &dto.PlaylistItemResponse{PlaylistID: playlistID, VideoID: videoID, Position: position}, nil
+}
+
+// RemoveVideo implements PlaylistService.
+func (s *playlistService) RemoveVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64) (*dto.PlaylistItemResponse, *dto.ErrorResponse) {
+	// start transaction
+	tx, _, errRe := s.lockOwnPlaylist(ctx, accountID, playlistID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+	defer tx.Rollback(ctx)
+
+	position, errRe := s.getVideoPosition(ctx, tx, playlistID, videoID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	if err := s.playlistRepository.RemoveVideo(ctx, tx, playlistID, videoID, position); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if errRe = s.touchAndCommit(ctx, tx, playlistID); errRe != nil {
+		return nil, errRe
+	}
+
+	return &dto.PlaylistItemResponse{PlaylistID: playlistID, VideoID: videoID}, nil
+}
+
+// MoveVideo implements PlaylistService.
+func (s *playlistService) MoveVideo(ctx context.Context, accountID int64, playlistID int64, videoID int64, position int) (*dto.PlaylistItemResponse, *dto.ErrorResponse) {
+	// start transaction
+	tx, playlist, errRe := s.lockOwnPlaylist(ctx, accountID, playlistID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+	defer tx.Rollback(ctx)
+
+	from, errRe := s.getVideoPosition(ctx, tx, playlistID, videoID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	to := min(position, playlist.VideoCount)
+
+	if err := s.playlistRepository.MoveVideo(ctx, tx, playlistID, videoID, from, to); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if errRe = s.touchAndCommit(ctx, tx, playlistID); errRe != nil {
+		return nil, errRe
+	}
+
+	return &dto.PlaylistItemResponse{PlaylistID: playlistID, VideoID: videoID, Position: to}, nil
+}
+
+// getOwnPlaylist returns playlist when accountID owns it. Private playlists of others are reported missing, not forbidden.
+func (s *playlistService) getOwnPlaylist(ctx context.Context, accountID int64, playlistID int64) (*entities.Playlist, *dto.ErrorResponse) {
+	playlist := s.playlistRepository.GetPlaylist(ctx, playlistID)
+
+	return playlist, checkPlaylistOwner(playlist, accountID)
+}
+
+// lockOwnPlaylist begins a transaction holding the lock of playlist of accountID, the caller rolls it back.
+func (s *playlistService) lockOwnPlaylist(ctx context.Context, accountID int64, playlistID int64) (pkg.Tx, *entities.Playlist, *dto.ErrorResponse) {
+	tx, err := s.playlistRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	playlist := s.playlistRepository.GetPlaylistForUpdate(ctx, tx, playlistID)
+
+	if errRe := checkPlaylistOwner(playlist, accountID); errRe != nil {
+		tx.Rollback(ctx)
+		return nil, nil, errRe
+	}
+
+	return tx, playlist, nil
+}
+
+func (s *playlistService) getVideoPosition(ctx context.Context, tx pkg.Tx, playlistID int64, videoID int64) (int, *dto.ErrorResponse) {
+	position, err := s.playlistRepository.GetVideoPosition(ctx, tx, playlistID, videoID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not in the playlist"}
+	}
+
+	if err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return position, nil
+}
+
+func (s *playlistService) touchAndCommit(ctx context.Context, tx pkg.Tx, playlistID int64) *dto.ErrorResponse {
+	if err := s.playlistRepository.Touch(ctx, tx, playlistID); err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err := tx.Commit(ctx); err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return nil
+}
+
+func checkPlaylistOwner(playlist *entities.Playlist, accountID int64) *dto.ErrorResponse {
+	if playlist == nil || !playlist.VisibleTo(accountID) {
+		return &dto.ErrorResponse{Code: http.StatusNotFound, Message: playlistNotFound}
+	}
+
+	if playlist.AccountID != accountID {
+		return &dto.ErrorResponse{Code: http.StatusForbidden, Message: "You can only change your own playlists"}
+	}
+
+	return nil
+}
+
+func toPlaylistResponse(playlist *entities.Playlist) *dto.PlaylistResponse {
+	return &dto.PlaylistResponse{
+		ID:         playlist.ID,
+		AccountID:  playlist.AccountID,
+		Name:       playlist.Name,
+		Visibility: string(playlist.Visibility),
+		VideoCount: playlist.VideoCount,
+		CreatedAt:  playlist.CreatedAt,
+		UpdatedAt:  playlist.UpdatedAt,
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+)
+
+type PurgeService interface {
+	// Purge deletes for good accounts and videos soft deleted longer than SOFT_DELETE_RETENTION ago.
+	Purge(ctx context.Context) error
+
+	// Run purges every interval until ctx is done.
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type purgeService struct {
+	accountRepository repository.AccountRepository
+	videoRepository   repository.VideoRepository
+}
+
+func NewPurgeService(accountRepository repository.AccountRepository, videoRepository repository.VideoRepository) PurgeService {
+	return &purgeService{
+		accountRepository: accountRepository,
+		videoRepository:   videoRepository,
+	}
+}
+
+// Purge implements PurgeService.
+func (p *purgeService) Purge(ctx context.Context) error {
+	before := time.Now().Add(-softDeleteRetention())
+
+	videos, err := p.videoRepository.PurgeDeletedVideos(ctx, before)
+
+	if err != nil {
+		return err
+	}
+
+	// videos of purged accounts go by cascade
+	accounts, err := p.accountRepository.PurgeDeletedAccounts(ctx, before)
+
+	if err != nil {
+		return err
+	}
+
+	if videos > 0 || accounts > 0 {
+		log.Printf("purged %d soft deleted videos and %d soft deleted accounts", videos, accounts)
+	}
+
+	return nil
+}
+
+// Run implements PurgeService.
+func (p *purgeService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Purge(ctx); err != nil {
+			log.Println("error when purging soft deleted rows: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// softDeleteRetention is how long soft deleted accounts and videos can be restored before they are purged.
+func softDeleteRetention() time.Duration {
+	return durationEnv("SOFT_DELETE_RETENTION", 30*24*time.Hour)
+}
+
+// durationEnv reads a positive duration like 720h from envVar, fallback when it is not set or invalid.
+func durationEnv(envVar string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(envVar))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// revokeSessions puts sessions on revocation list, so access tokens already issued for them stop working
+// before they expire. Failures are only logged, refresh tokens of the sessions are already gone.
+func revokeSessions(ctx context.Context, revocationList pkg.RevocationList, sessions []*entities.RefreshToken, keepSessionID string) {
+	expiresAt := time.Now().Add(time.Duration(getExpireTime("EXPIRE_TIME_ACCESS_TOKEN")) * time.Minute)
+
+	for _, session := range sessions {
+		if session.FamilyID == keepSessionID {
+			continue
+		}
+
+		if err := revocationList.Revoke(ctx, utils.SessionRevocationKey(session.FamilyID), expiresAt); err != nil {
+			log.Println("error when revoking session: ", err)
+		}
+	}
+}
+
+// personalAccessTokenSessions lists the sessions websocket otps of the tokens are issued for.
+func personalAccessTokenSessions(tokens []*entities.PersonalAccessToken) []*entities.RefreshToken {
+	sessions := make([]*entities.RefreshToken, 0, len(tokens))
+
+	for _, token := range tokens {
+		sessions = append(sessions, &entities.RefreshToken{FamilyID: utils.PersonalAccessTokenSessionID(token.ID)})
+	}
+
+	return sessions
+}
+
+// logoutEverywhere deletes every refresh token of account, revokes its personal access tokens and its sessions,
+// returning how many sessions were active.
+func logoutEverywhere(ctx context.Context, accountRepository repository.AccountRepository, refreshTokenRepository repository.RefreshTokenRepository,
+	personalAccessTokenRepository repository.PersonalAccessTokenRepository, revocationList pkg.RevocationList, accountID int64) (int, *dto.ErrorResponse) {
+	sessions, err := refreshTokenRepository.GetActiveSessions(ctx, accountID)
+
+	if err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	tokens, err := personalAccessTokenRepository.GetTokens(ctx, accountID)
+
+	if err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// start transaction
+	tx, err := accountRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	if err = refreshTokenRepository.DeleteAllRefreshTokens(ctx, tx, accountID); err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = personalAccessTokenRepository.RevokeAll(ctx, tx, accountID); err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return 0, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	revokeSessions(ctx, revocationList, append(sessions, personalAccessTokenSessions(tokens)...), "")
+
+	return len(sessions), nil
+}
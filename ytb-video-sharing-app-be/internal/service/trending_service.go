@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg/worker"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type TrendingService interface {
+	// Recompute scores videos shared within window again and replaces the saved scores of window.
+	Recompute(ctx context.Context, window entities.TrendingWindow) error
+
+	// Run recomputes scores of every window on a worker pool every interval until ctx is done.
+	Run(ctx context.Context, interval time.Duration)
+
+	// GetTrendingVideos lists videos by their score in window, TRENDING_DEFAULT_WINDOW when window is empty.
+	GetTrendingVideos(ctx context.Context, window entities.TrendingWindow, viewerID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+}
+
+type trendingService struct {
+	videoScoreRepository repository.VideoScoreRepository
+	tagRepository        repository.TagRepository
+}
+
+func NewTrendingService(videoScoreRepository repository.VideoScoreRepository, tagRepository repository.TagRepository) TrendingService {
+	return &trendingService{
+		videoScoreRepository: videoScoreRepository,
+		tagRepository:        tagRepository,
+	}
+}
+
+// Recompute implements TrendingService.
+func (t *trendingService) Recompute(ctx context.Context, window entities.TrendingWindow) error {
+	now := time.Now()
+
+	stats, err := t.videoScoreRepository.GetVideoStats(ctx, window.Since(now), window.ViewsSince(now))
+
+	if err != nil {
+		return err
+	}
+
+	scores := make([]*entities.VideoScore, 0, len(stats))
+	for _, stat := range stats {
+		// a video whose share time is unknown ranks as the oldest one
+		age := time.Duration(math.MaxInt64)
+		if stat.CreatedAt.Valid {
+			age = now.Sub(stat.CreatedAt.Time)
+		}
+
+		scores = append(scores, &entities.VideoScore{
+			VideoID: stat.VideoID,
+			Score:   utils.TrendingScore(stat.UpVote, stat.DownVote, stat.CommentCount, stat.ViewCount, age),
+		})
+	}
+
+	tx, err := t.videoScoreRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = t.videoScoreRepository.DeleteScores(ctx, tx, window); err != nil {
+		return err
+	}
+
+	if err = t.videoScoreRepository.SaveScores(ctx, tx, window, scores, now); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Run implements TrendingService.
+func (t *trendingService) Run(ctx context.Context, interval time.Duration) {
+	windows := entities.TrendingWindows
+
+	// windows queued or being recomputed, each is in the pool at most once so pushing never blocks
+	var inFlight sync.Map
+
+	// one worker per window, so a slow window does not hold back the others
+	pool := worker.NewWorkerPool(len(windows), len(windows), func(message interface{}) error {
+		window, ok := message.(entities.TrendingWindow)
+		if !ok {
+			return fmt.Errorf("invalid trending window %v", message)
+		}
+		defer inFlight.Delete(window)
+
+		return t.Recompute(ctx, window)
+	})
+
+	pool.Start()
+	defer pool.GracefulStop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, window := range windows {
+			if _, running := inFlight.LoadOrStore(window, struct{}{}); running {
+				log.Printf("skipping trending window %s, its previous run is still in flight", window)
+				continue
+			}
+
+			pool.PushMessage(window)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetTrendingVideos implements TrendingService.
+func (t *trendingService) GetTrendingVideos(ctx context.Context, window entities.TrendingWindow, viewerID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	if window == "" {
+		window = defaultTrendingWindow()
+	}
+
+	videos, totalItems, err := t.videoScoreRepository.GetTrendingVideos(ctx, window, viewerID, page, limit)
+	if err != nil {
+		log.Println("error when listing trending videos: ", err)
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err := attachTags(ctx, t.tagRepository, videos); err != nil {
+		log.Println("error when loading tags of trending videos: ", err)
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	videoResponses := make([]*dto.VideoResponse, 0)
+	for _, video := range videos {
+		videoResponses = append(videoResponses, toVideoResponse(video))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	isNext := page < totalPages
+	isPrevious := page > 1
+
+	return videoResponses, totalItems, totalPages, isNext, isPrevious, nil
+}
+
+// defaultTrendingWindow is the window trending videos are listed for when none is asked for,
+// a day when TRENDING_DEFAULT_WINDOW is not set or invalid.
+func defaultTrendingWindow() entities.TrendingWindow {
+	window := entities.TrendingWindow(os.Getenv("TRENDING_DEFAULT_WINDOW"))
+	if !window.Valid() {
+		return entities.TrendingDay
+	}
+	return window
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+type VideoCommentService interface {
+	// Comment adds comment of account to video, comments count towards trending.
+	Comment(ctx context.Context, accountID int64, videoID int64, content string) (*dto.VideoCommentResponse, *dto.ErrorResponse)
+
+	// GetComments lists comments of video the oldest first, a hidden video only for its owner viewerID.
+	GetComments(ctx context.Context, videoID int64, viewerID int64, limit int, page int) ([]*dto.VideoCommentResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// DeleteComment deletes comment written by account.
+	DeleteComment(ctx context.Context, accountID int64, commentID int64) (*dto.DeleteVideoCommentResponse, *dto.ErrorResponse)
+}
+
+type videoCommentService struct {
+	videoCommentRepository repository.VideoCommentRepository
+	videoRepository        repository.VideoRepository
+	accountRepository      repository.AccountRepository
+}
+
+func NewVideoCommentService(videoCommentRepository repository.VideoCommentRepository, videoRepository repository.VideoRepository, accountRepository repository.AccountRepository) VideoCommentService {
+	return &videoCommentService{
+		videoCommentRepository: videoCommentRepository,
+		videoRepository:        videoRepository,
+		accountRepository:      accountRepository,
+	}
+}
+
+// Comment implements VideoCommentService.
+func (v *videoCommentService) Comment(ctx context.Context, accountID int64, videoID int64, content string) (*dto.VideoCommentResponse, *dto.ErrorResponse) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "Comment is empty"}
+	}
+
+	if errRes := v.checkVideo(ctx, videoID, accountID); errRes != nil {
+		return nil, errRes
+	}
+
+	comment := &entities.VideoComment{
+		VideoID:   videoID,
+		AccountID: accountID,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+
+	id, err := v.videoCommentRepository.Create(ctx, comment)
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	comment.ID = id
+
+	if account := v.accountRepository.GetAccountByID(ctx, accountID); account != nil {
+		comment.FullName = account.FullName
+	}
+
+	return toVideoCommentResponse(comment), nil
+}
+
+// GetComments implements VideoCommentService.
+func (v *videoCommentService) GetComments(ctx context.Context, videoID int64, viewerID int64, limit int, page int) ([]*dto.VideoCommentResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	if errRes := v.checkVideo(ctx, videoID, viewerID); errRes != nil {
+		return nil, 0, 0, false, false, errRes
+	}
+
+	comments, totalItems, err := v.videoCommentRepository.GetComments(ctx, videoID, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]*dto.VideoCommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		res = append(res, toVideoCommentResponse(comment))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	isNext := page < totalPages
+	isPrevious := page > 1
+
+	return res, totalItems, totalPages, isNext, isPrevious, nil
+}
+
+// DeleteComment implements VideoCommentService.
+func (v *videoCommentService) DeleteComment(ctx context.Context, accountID int64, commentID int64) (*dto.DeleteVideoCommentResponse, *dto.ErrorResponse) {
+	if err := v.videoCommentRepository.Delete(ctx, accountID, commentID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Comment is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.DeleteVideoCommentResponse{}, nil
+}
+
+// checkVideo makes sure video exists and viewerID may see it.
+func (v *videoCommentService) checkVideo(ctx context.Context, videoID int64, viewerID int64) *dto.ErrorResponse {
+	videos, err := v.videoRepository.GetVideosByIDs(ctx, []int64{videoID}, viewerID)
+	if err != nil {
+		return &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if len(videos) == 0 {
+		return &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	return nil
+}
+
+func toVideoCommentResponse(comment *entities.VideoComment) *dto.VideoCommentResponse {
+	return &dto.VideoCommentResponse{
+		ID:        comment.ID,
+		VideoID:   comment.VideoID,
+		AccountID: comment.AccountID,
+		FullName:  comment.FullName,
+		Content:   comment.Content,
+		CreatedAt: comment.CreatedAt,
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+	"ytb-video-sharing-app-be/internal/dto"
+	"ytb-video-sharing-app-be/internal/entities"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
+)
+
+// reportRateWindow is the window VIDEO_REPORT_RATE_LIMIT counts reports of a reporter in.
+const reportRateWindow = time.Hour
+
+type VideoReportService interface {
+	// Report files a report of reporter against video, the video is hidden once enough distinct accounts reported it.
+	Report(ctx context.Context, reporterID int64, videoID int64, payload *dto.ReportVideoRequest) (*dto.VideoReportResponse, *dto.ErrorResponse)
+
+	// GetQueue lists videos with open reports, the most reported first.
+	GetQueue(ctx context.Context, limit int, page int) ([]*dto.ReportedVideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	DismissReport(ctx context.Context, moderatorID int64, reportID int64) (*dto.VideoReportResponse, *dto.ErrorResponse)
+
+	// HideVideo hides video and resolves its open reports.
+	HideVideo(ctx context.Context, moderatorID int64, videoID int64) (*dto.HideVideoResponse, *dto.ErrorResponse)
+
+	UnhideVideo(ctx context.Context, moderatorID int64, videoID int64) (*dto.HideVideoResponse, *dto.ErrorResponse)
+}
+
+type videoReportService struct {
+	videoReportRepository repository.VideoReportRepository
+	videoRepository       repository.VideoRepository
+	tagRepository         repository.TagRepository
+	auditLogger           pkg.AuditLogger
+}
+
+func NewVideoReportService(videoReportRepository repository.VideoReportRepository,
+	videoRepository repository.VideoRepository,
+	tagRepository repository.TagRepository,
+	auditLogger pkg.AuditLogger) VideoReportService {
+	return &videoReportService{
+		videoReportRepository: videoReportRepository,
+		videoRepository:       videoRepository,
+		tagRepository:         tagRepository,
+		auditLogger:           auditLogger,
+	}
+}
+
+// Report implements VideoReportService.
+func (s *videoReportService) Report(ctx context.Context, reporterID int64, videoID int64, payload *dto.ReportVideoRequest) (*dto.VideoReportResponse, *dto.ErrorResponse) {
+	video, errRe := s.getVideo(ctx, videoID)
+
+	if errRe != nil {
+		return nil, errRe
+	}
+
+	// only the owner sees a hidden video, to everyone else it does not exist
+	if video.HiddenAt.Valid && video.AccountID != reporterID {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	if video.AccountID == reporterID {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: "You can not report your own video"}
+	}
+
+	reported, err := s.videoReportRepository.CountByReporterSince(ctx, reporterID, time.Now().Add(-reportRateWindow))
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if reported >= intEnv("VIDEO_REPORT_RATE_LIMIT", 10) {
+		return nil, &dto.ErrorResponse{Code: http.StatusTooManyRequests, Message: "Too many reports, try again later"}
+	}
+
+	report := &entities.VideoReport{
+		VideoID:    videoID,
+		ReporterID: reporterID,
+		Reason:     entities.ReportReason(payload.Reason),
+		Note:       payload.Note,
+		Status:     entities.ReportStatusOpen,
+		CreatedAt:  time.Now(),
+	}
+
+	if report.ID, err = s.videoReportRepository.Create(ctx, report); err != nil {
+		if errors.Is(err, pkg.ErrDuplicate) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "You have already reported this video"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	s.autoHide(ctx, videoID)
+
+	return toVideoReportResponse(report), nil
+}
+
+// autoHide hides video once VIDEO_REPORT_AUTO_HIDE_THRESHOLD accounts reported it, the report is saved even when this fails.
+func (s *videoReportService) autoHide(ctx context.Context, videoID int64) {
+	reports, err := s.videoReportRepository.CountOpenByVideo(ctx, videoID)
+
+	if err != nil {
+		log.Println("error when counting reports of video: ", err)
+		return
+	}
+
+	if reports < intEnv("VIDEO_REPORT_AUTO_HIDE_THRESHOLD", 5) {
+		return
+	}
+
+	if err = s.videoRepository.HideVideo(ctx, videoID, time.Now()); err != nil {
+		if !errors.Is(err, pkg.ErrNoRowsAffected) {
+			log.Println("error when hiding reported video: ", err)
+		}
+		return
+	}
+
+	logAudit(ctx, s.auditLogger, "video_auto_hidden", 0, pkg.AuditTargetVideo, videoID, fmt.Sprintf("after %d reports", reports))
+}
+
+// GetQueue implements VideoReportService.
+func (s *videoReportService) GetQueue(ctx context.Context, limit int, page int) ([]*dto.ReportedVideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	videos, totalItems, err := s.videoReportRepository.GetReportedVideos(ctx, page, limit)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	videoIDs := make([]int64, 0, len(videos))
+	for _, video := range videos {
+		videoIDs = append(videoIDs, video.ID)
+	}
+
+	reports, err := s.videoReportRepository.GetOpenReportsByVideos(ctx, videoIDs)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	tags, err := s.tagRepository.GetTagsByVideos(ctx, videoIDs)
+
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]*dto.ReportedVideoResponse, 0, len(videos))
+	byVideo := make(map[int64]*dto.ReportedVideoResponse, len(videos))
+
+	for _, video := range videos {
+		video.Tags = tags[video.ID]
+
+		item := &dto.ReportedVideoResponse{
+			Video:          *toVideoResponse(&video.Video),
+			ReportCount:    video.ReportCount,
+			LastReportedAt: video.LastReportedAt,
+			Reasons:        map[string]int{},
+			Reports:        []*dto.VideoReportResponse{},
+		}
+
+		res = append(res, item)
+		byVideo[video.ID] = item
+	}
+
+	for _, report := range reports {
+		if item, ok := byVideo[report.VideoID]; ok {
+			item.Reasons[string(report.Reason)]++
+			item.Reports = append(item.Reports, toVideoReportResponse(report))
+		}
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	return res, totalItems, totalPages, page < totalPages, page > 1, nil
+}
+
+// DismissReport implements VideoReportService.
+func (s *videoReportService) DismissReport(ctx context.Context, moderatorID int64, reportID int64) (*dto.VideoReportResponse, *dto.ErrorResponse) {
+	report := s.videoReportRepository.GetReport(ctx, reportID)
+
+	if report == nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Report is not found"}
+	}
+
+	if err := s.videoReportRepository.Dismiss(ctx, reportID, moderatorID, time.Now()); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Report is already resolved"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "video_report_dismissed", moderatorID, pkg.AuditTargetVideoReport, reportID, fmt.Sprintf("video %d", report.VideoID))
+
+	report.Status = entities.ReportStatusDismissed
+
+	return toVideoReportResponse(report), nil
+}
+
+// HideVideo implements VideoReportService.
+func (s *videoReportService) HideVideo(ctx context.Context, moderatorID int64, videoID int64) (*dto.HideVideoResponse, *dto.ErrorResponse) {
+	if _, errRe := s.getVideo(ctx, videoID); errRe != nil {
+		return nil, errRe
+	}
+
+	now := time.Now()
+
+	// a video hidden by reports already is confirmed by resolving them
+	if err := s.videoRepository.HideVideo(ctx, videoID, now); err != nil && !errors.Is(err, pkg.ErrNoRowsAffected) {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err := s.videoReportRepository.ResolveByVideo(ctx, videoID, moderatorID, entities.ReportStatusActioned, now); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "video_hidden", moderatorID, pkg.AuditTargetVideo, videoID, "")
+
+	return &dto.HideVideoResponse{VideoID: videoID, Hidden: true}, nil
+}
+
+// UnhideVideo implements VideoReportService.
+func (s *videoReportService) UnhideVideo(ctx context.Context, moderatorID int64, videoID int64) (*dto.HideVideoResponse, *dto.ErrorResponse) {
+	if _, errRe := s.getVideo(ctx, videoID); errRe != nil {
+		return nil, errRe
+	}
+
+	if err := s.videoRepository.UnhideVideo(ctx, videoID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Video is not hidden"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// the reports that hid the video are overruled, otherwise the next report would hide it again right away
+	if err := s.videoReportRepository.ResolveByVideo(ctx, videoID, moderatorID, entities.ReportStatusDismissed, time.Now()); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, s.auditLogger, "video_unhidden", moderatorID, pkg.AuditTargetVideo, videoID, "")
+
+	return &dto.HideVideoResponse{VideoID: videoID, Hidden: false}, nil
+}
+
+func (s *videoReportService) getVideo(ctx context.Context, videoID int64) (*entities.Video, *dto.ErrorResponse) {
+	video, err := s.videoRepository.GetVideo(ctx, videoID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return video, nil
+}
+
+func toVideoReportResponse(report *entities.VideoReport) *dto.VideoReportResponse {
+	return &dto.VideoReportResponse{
+		ID:         report.ID,
+		VideoID:    report.VideoID,
+		ReporterID: report.ReporterID,
+		Reason:     string(report.Reason),
+		Note:       report.Note,
+		Status:     string(report.Status),
+		CreatedAt:  report.CreatedAt,
+	}
+}
+
+// intEnv reads a positive number from envVar, fallback when it is not set or invalid.
+func intEnv(envVar string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -2,36 +2,100 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"math"
 	"net/http"
+	"time"
 	"ytb-video-sharing-app-be/internal/dto"
 	"ytb-video-sharing-app-be/internal/entities"
 	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
 )
 
 type VideoService interface {
+	// ShareVideoYTB saves video, its tags are normalized first with utils.NormalizeTags.
 	ShareVideoYTB(ctx context.Context, payload *entities.Video) (*dto.ShareVideoResponse, *dto.ErrorResponse)
 
-	GetListVideos(ctx context.Context, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+	// GetListVideos lists videos, hidden ones only for their owner viewerID (0 for anonymous viewers).
+	// Only videos tagged with tag are listed unless it is empty.
+	GetListVideos(ctx context.Context, viewerID int64, tag string, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// ListTags lists tags starting with prefix for autocomplete, the most used first.
+	ListTags(ctx context.Context, prefix string, limit int) ([]*dto.TagResponse, *dto.ErrorResponse)
+
+	GetFeed(ctx context.Context, accountID int64, cursor int64, limit int) ([]*dto.VideoResponse, int64, bool, *dto.ErrorResponse)
+
+	GetListVideosByAccount(ctx context.Context, accountID int64, viewerID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse)
+
+	// DeleteVideo soft deletes video of actor, or any video when role is granted PermissionDeleteAnyVideo.
+	DeleteVideo(ctx context.Context, actorID int64, role entities.Role, videoID int64) (*dto.DeleteVideoResponse, *dto.ErrorResponse)
+
+	// RestoreVideo restores video its owner deleted, or any soft deleted video when role is granted PermissionRestoreVideos.
+	RestoreVideo(ctx context.Context, actorID int64, role entities.Role, videoID int64) (*dto.RestoreVideoResponse, *dto.ErrorResponse)
+
+	// RecordView counts a view of video towards trending, at most once per viewer every videoViewInterval.
+	RecordView(ctx context.Context, viewerID int64, videoID int64) (*dto.RecordViewResponse, *dto.ErrorResponse)
 }
 
+// videoViewInterval is how long a viewer has to wait before viewing the same video counts again.
+const videoViewInterval = 30 * time.Minute
+
 type videoServie struct {
-	videoRepository repository.VideoRepository
+	videoRepository     repository.VideoRepository
+	tagRepository       repository.TagRepository
+	videoViewRepository repository.VideoViewRepository
+	auditLogger         pkg.AuditLogger
 }
 
-func NewVideoService(videoRepository repository.VideoRepository) VideoService {
+func NewVideoService(videoRepository repository.VideoRepository, tagRepository repository.TagRepository, videoViewRepository repository.VideoViewRepository, auditLogger pkg.AuditLogger) VideoService {
 	return &videoServie{
-		videoRepository: videoRepository,
+		videoRepository:     videoRepository,
+		tagRepository:       tagRepository,
+		videoViewRepository: videoViewRepository,
+		auditLogger:         auditLogger,
 	}
 }
 
 func (v videoServie) ShareVideoYTB(ctx context.Context, payload *entities.Video) (*dto.ShareVideoResponse, *dto.ErrorResponse) {
-	res, err := v.videoRepository.CreateVideo(ctx, payload)
+	tags, err := utils.NormalizeTags(payload.Tags)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+
+	// start transaction
+	tx, err := v.videoRepository.BeginTransaction(ctx)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+	defer tx.Rollback(ctx)
+
+	videoID, err := v.videoRepository.CreateVideo(ctx, tx, payload)
 
 	if err != nil {
-		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
 	}
 
+	if err = v.tagRepository.AddVideoTags(ctx, tx, videoID, tags); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	// end transaction
+	if err = tx.Commit(ctx); err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res, err := v.videoRepository.GetVideo(ctx, videoID)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, v.auditLogger, "video_shared", payload.AccountID, pkg.AuditTargetVideo, res.ID, "")
+
 	return &dto.ShareVideoResponse{
 		ID:          res.ID,
 		Title:       res.Title,
@@ -40,27 +104,77 @@ func (v videoServie) ShareVideoYTB(ctx context.Context, payload *entities.Video)
 		DownVote:    res.DownVote,
 		Thumbnail:   res.Thumbnail,
 		VideoUrl:    res.VideoUrl,
+		Tags:        tags,
 	}, nil
 }
 
-func (v *videoServie) GetListVideos(ctx context.Context, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
-	videos, totalItems, err := v.videoRepository.GetListVideos(ctx, page, limit)
+func (v *videoServie) GetListVideos(ctx context.Context, viewerID int64, tag string, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	videos, totalItems, err := v.videoRepository.GetListVideos(ctx, viewerID, tag, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = attachTags(ctx, v.tagRepository, videos); err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	videoResponses := make([]*dto.VideoResponse, 0)
+	for _, video := range videos {
+		videoResponses = append(videoResponses, toVideoResponse(video))
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
+
+	isNext := page < totalPages
+	isPrevious := page > 1
+
+	return videoResponses, totalItems, totalPages, isNext, isPrevious, nil
+}
+
+// GetFeed returns videos shared by accounts the caller follows, along with the
+// cursor of the next page and whether there is one.
+func (v *videoServie) GetFeed(ctx context.Context, accountID int64, cursor int64, limit int) ([]*dto.VideoResponse, int64, bool, *dto.ErrorResponse) {
+	// fetch one more record to know whether there is a next page
+	videos, err := v.videoRepository.GetFeedVideos(ctx, accountID, cursor, limit+1)
 	if err != nil {
-		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: err.Error()}
+		return nil, 0, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	hasMore := len(videos) > limit
+	if hasMore {
+		videos = videos[:limit]
+	}
+
+	if err = attachTags(ctx, v.tagRepository, videos); err != nil {
+		return nil, 0, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
 	}
 
 	videoResponses := make([]*dto.VideoResponse, 0)
 	for _, video := range videos {
-		videoResponses = append(videoResponses, &dto.VideoResponse{
-			ID:          video.ID,
-			Title:       video.Title,
-			Description: video.Description,
-			UpVote:      video.UpVote,
-			DownVote:    video.DownVote,
-			Thumbnail:   video.Thumbnail,
-			VideoUrl:    video.VideoUrl,
-			SharedBy:    video.FullName,
-		})
+		videoResponses = append(videoResponses, toVideoResponse(video))
+	}
+
+	var nextCursor int64
+	if hasMore {
+		nextCursor = videos[len(videos)-1].ID
+	}
+
+	return videoResponses, nextCursor, hasMore, nil
+}
+
+func (v *videoServie) GetListVideosByAccount(ctx context.Context, accountID int64, viewerID int64, limit int, page int) ([]*dto.VideoResponse, int, int, bool, bool, *dto.ErrorResponse) {
+	videos, totalItems, err := v.videoRepository.GetListVideosByAccount(ctx, accountID, viewerID, page, limit)
+	if err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if err = attachTags(ctx, v.tagRepository, videos); err != nil {
+		return nil, 0, 0, false, false, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	videoResponses := make([]*dto.VideoResponse, 0)
+	for _, video := range videos {
+		videoResponses = append(videoResponses, toVideoResponse(video))
 	}
 
 	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
@@ -70,3 +184,152 @@ func (v *videoServie) GetListVideos(ctx context.Context, limit int, page int) ([
 
 	return videoResponses, totalItems, totalPages, isNext, isPrevious, nil
 }
+
+// DeleteVideo implements VideoService.
+func (v *videoServie) DeleteVideo(ctx context.Context, actorID int64, role entities.Role, videoID int64) (*dto.DeleteVideoResponse, *dto.ErrorResponse) {
+	video, err := v.videoRepository.GetVideo(ctx, videoID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if video.AccountID != actorID && !utils.HasPermission(role, utils.PermissionDeleteAnyVideo) {
+		// only the owner sees a hidden video, to everyone else it does not exist
+		if video.HiddenAt.Valid {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusForbidden, Message: "You can only delete your own videos"}
+	}
+
+	now := time.Now()
+
+	if err = v.videoRepository.DeleteVideo(ctx, videoID, actorID, now); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, v.auditLogger, "video_deleted", actorID, pkg.AuditTargetVideo, videoID, "")
+
+	return &dto.DeleteVideoResponse{VideoID: videoID, RestorableUntil: now.Add(softDeleteRetention())}, nil
+}
+
+// RestoreVideo implements VideoService.
+func (v *videoServie) RestoreVideo(ctx context.Context, actorID int64, role entities.Role, videoID int64) (*dto.RestoreVideoResponse, *dto.ErrorResponse) {
+	video, err := v.videoRepository.GetDeletedVideo(ctx, videoID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Deleted video is not found"}
+	}
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if !utils.HasPermission(role, utils.PermissionRestoreVideos) {
+		if video.AccountID != actorID {
+			return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Deleted video is not found"}
+		}
+
+		// a video taken down by a moderator stays down until a moderator restores it
+		if video.DeletedBy.Int64 != actorID {
+			return nil, &dto.ErrorResponse{Code: http.StatusForbidden, Message: "Video was deleted by a moderator"}
+		}
+	}
+
+	if err = v.videoRepository.RestoreVideo(ctx, videoID); err != nil {
+		if errors.Is(err, pkg.ErrNoRowsAffected) {
+			return nil, &dto.ErrorResponse{Code: http.StatusConflict, Message: "Video is not deleted"}
+		}
+
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	logAudit(ctx, v.auditLogger, "video_restored", actorID, pkg.AuditTargetVideo, videoID, "")
+
+	return &dto.RestoreVideoResponse{VideoID: videoID}, nil
+}
+
+// ListTags implements VideoService.
+func (v *videoServie) ListTags(ctx context.Context, prefix string, limit int) ([]*dto.TagResponse, *dto.ErrorResponse) {
+	tags, err := v.tagRepository.ListTags(ctx, prefix, limit)
+
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	res := make([]*dto.TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		res = append(res, &dto.TagResponse{Name: tag.Name, VideoCount: tag.VideoCount})
+	}
+
+	return res, nil
+}
+
+// RecordView implements VideoService.
+func (v *videoServie) RecordView(ctx context.Context, viewerID int64, videoID int64) (*dto.RecordViewResponse, *dto.ErrorResponse) {
+	videos, err := v.videoRepository.GetVideosByIDs(ctx, []int64{videoID}, viewerID)
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	if len(videos) == 0 {
+		return nil, &dto.ErrorResponse{Code: http.StatusNotFound, Message: "Video is not found"}
+	}
+
+	counted, err := v.videoViewRepository.Record(ctx, videoID, viewerID, time.Now().Add(-videoViewInterval))
+	if err != nil {
+		return nil, &dto.ErrorResponse{Code: http.StatusInternalServerError, Message: utils.INTERNAL_SERVER_ERROR}
+	}
+
+	return &dto.RecordViewResponse{VideoID: videoID, Counted: counted}, nil
+}
+
+// attachTags loads tags of a page of videos with a single query.
+func attachTags(ctx context.Context, tagRepository repository.TagRepository, videos []*entities.Video) error {
+	videoIDs := make([]int64, 0, len(videos))
+	for _, video := range videos {
+		videoIDs = append(videoIDs, video.ID)
+	}
+
+	tags, err := tagRepository.GetTagsByVideos(ctx, videoIDs)
+
+	if err != nil {
+		return err
+	}
+
+	for _, video := range videos {
+		video.Tags = tags[video.ID]
+	}
+
+	return nil
+}
+
+func toVideoResponse(video *entities.Video) *dto.VideoResponse {
+	tags := video.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &dto.VideoResponse{
+		ID:            video.ID,
+		Title:         video.Title,
+		Description:   video.Description,
+		UpVote:        video.UpVote,
+		DownVote:      video.DownVote,
+		Thumbnail:     video.Thumbnail,
+		VideoUrl:      video.VideoUrl,
+		SharedBy:      video.FullName,
+		Tags:          tags,
+		Hidden:        video.HiddenAt.Valid,
+		BookmarkCount: video.BookmarkCount,
+		IsBookmarked:  video.IsBookmarked,
+	}
+}
@@ -15,6 +15,8 @@ var (
 	// Because that can make decimals, so instead *9 / 10 to get 90%
 	// The reason why it has to be less than PingRequency is becuase otherwise it will send a new Ping before getting response
 	pingInterval = (pongWait * 9) / 10
+	// egressBufferSize is how many events can wait for a client to write them before it is dropped as too slow or gone
+	egressBufferSize = 16
 )
 
 type ClientList map[string]*Client
@@ -24,18 +26,26 @@ type Client struct {
 
 	manager *Manager
 
-	// egress is used to avoid concurrent writes on the WebSocket
+	// egress is used to avoid concurrent writes on the WebSocket, it is buffered so senders never wait on a client
 	egress chan Event
 
 	connID string
+
+	// accountID is the account that owns this connection
+	accountID int64
+
+	// sessionID is the login session (refresh token family) this connection was opened from
+	sessionID string
 }
 
-func NewClient(conn *websocket.Conn, manager *Manager, connID string) *Client {
+func NewClient(conn *websocket.Conn, manager *Manager, connID string, accountID int64, sessionID string) *Client {
 	return &Client{
 		connection: conn,
 		manager:    manager,
-		egress:     make(chan Event),
+		egress:     make(chan Event, egressBufferSize),
 		connID:     connID,
+		accountID:  accountID,
+		sessionID:  sessionID,
 	}
 }
 
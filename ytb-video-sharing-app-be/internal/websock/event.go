@@ -14,10 +14,32 @@ type EventHandler func(event Event, c *Client) error
 const (
 	EventSendMessage = "send_message"
 	EventNotif       = "event_notif"
+	EventForceLogout = "force_logout"
+	EventSecurity    = "security_alert"
+	EventPlaylist    = "playlist_changed"
 )
 
 type EventNotificationMessage struct {
-	Title     string `json:"title"`
-	SharedBy  string `json:"shared_by"`
-	Thumbnail string `json:"thumbnail"`
+	Title     string   `json:"title"`
+	SharedBy  string   `json:"shared_by"`
+	Thumbnail string   `json:"thumbnail"`
+	Tags      []string `json:"tags"`
+}
+
+type EventForceLogoutMessage struct {
+	Reason string `json:"reason"`
+}
+
+type EventSecurityMessage struct {
+	Reason string `json:"reason"`
+}
+
+// EventPlaylistMessage tells watchers of a playlist what changed, watchers reload the playlist to see the result.
+type EventPlaylistMessage struct {
+	PlaylistID int64 `json:"playlist_id"`
+	// Change is one of updated, deleted, video_added, video_removed and video_moved
+	Change  string `json:"change"`
+	VideoID int64  `json:"video_id,omitempty"`
+	// Position is where the video was added or moved to
+	Position int `json:"position,omitempty"`
 }
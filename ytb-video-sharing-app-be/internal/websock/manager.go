@@ -6,6 +6,8 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"ytb-video-sharing-app-be/pkg"
+	"ytb-video-sharing-app-be/utils"
 
 	"github.com/gorilla/websocket"
 )
@@ -20,12 +22,17 @@ type Manager struct {
 
 	clients ClientList
 
+	// watchers holds the connections watching each topic, like a playlist
+	watchers map[string]map[string]*Client
+
 	mux      sync.Mutex
 	handlers map[string]EventHandler
 	otps     RetentionMap
+
+	revocationList pkg.RevocationList
 }
 
-func NewManager(rentation RetentionMap) (*Manager, *http.ServeMux) {
+func NewManager(rentation RetentionMap, revocationList pkg.RevocationList) (*Manager, *http.ServeMux) {
 	m := &Manager{
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
@@ -34,8 +41,10 @@ func NewManager(rentation RetentionMap) (*Manager, *http.ServeMux) {
 				return true
 			},
 		},
-		clients: make(ClientList),
-		otps:    rentation,
+		clients:        make(ClientList),
+		watchers:       make(map[string]map[string]*Client),
+		otps:           rentation,
+		revocationList: revocationList,
 	}
 
 	m.setupEventHandlers()
@@ -50,7 +59,7 @@ func NewManager(rentation RetentionMap) (*Manager, *http.ServeMux) {
 func (m *Manager) setupEventHandlers() {
 	m.handlers = make(map[string]EventHandler)
 	m.handlers[EventSendMessage] = func(e Event, c *Client) error {
-		c.egress <- e
+		m.sendToClients(e, []*Client{c})
 		return nil
 	}
 }
@@ -73,7 +82,21 @@ func (m *Manager) ServeWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Verify OTP is existing
-	if !m.otps.VerifyOTP(otp) {
+	verifiedOTP, ok := m.otps.VerifyOTP(otp)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// the token or session the otp was issued for may have been revoked in the meantime
+	revoked, err := m.revocationList.IsRevoked(r.Context(), revocationKeys(verifiedOTP)...)
+	if err != nil {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if revoked {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
@@ -87,7 +110,7 @@ func (m *Manager) ServeWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := NewClient(conn, m, connID)
+	client := NewClient(conn, m, connID, verifiedOTP.AccountID, verifiedOTP.SessionID)
 	m.addClient(client, connID)
 
 	// Start read/write process
@@ -116,6 +139,7 @@ func (m *Manager) removeClient(client *Client, connID string) {
 
 		// remove
 		delete(m.clients, connID)
+		m.unwatchAll(connID)
 	}
 }
 
@@ -133,11 +157,164 @@ func (m *Manager) routeEvent(event Event, c *Client) error {
 	}
 }
 
-func (m *Manager) SendBroadCast(event Event, connIDExclusive string) {
+// SendToAccounts sends the event to every connection owned by one of the accounts
+func (m *Manager) SendToAccounts(event Event, accountIDs []int64, connIDExclusive string) {
+	receivers := make(map[int64]struct{}, len(accountIDs))
+	for _, accountID := range accountIDs {
+		receivers[accountID] = struct{}{}
+	}
+
+	m.mux.Lock()
+	targets := make([]*Client, 0)
 	for connID, client := range m.clients {
+		if _, ok := receivers[client.accountID]; ok && connID != connIDExclusive {
+			targets = append(targets, client)
+		}
+	}
+	m.mux.Unlock()
+
+	m.sendToClients(event, targets)
+}
+
+// SendToSession sends the event to every connection opened from the session of the account
+func (m *Manager) SendToSession(event Event, accountID int64, sessionID string) {
+	m.mux.Lock()
+	targets := make([]*Client, 0)
+	for _, client := range m.clients {
+		if client.accountID == accountID && client.sessionID == sessionID {
+			targets = append(targets, client)
+		}
+	}
+	m.mux.Unlock()
+
+	m.sendToClients(event, targets)
+}
+
+// sendToClients queues the event on each client without waiting, a client whose egress is full has stopped
+// writing or fell too far behind, so it is dropped instead of blocking the caller.
+// The lock must not be held, dropping a client takes it.
+func (m *Manager) sendToClients(event Event, clients []*Client) {
+	for _, client := range clients {
+		select {
+		case client.egress <- event:
+			log.Printf("Sending to client %s", client.connID)
+		default:
+			log.Printf("Dropping client %s, it is not reading its events", client.connID)
+			m.removeClient(client, client.connID)
+		}
+	}
+}
+
+// Watch subscribes connection connID of account to events of topic, false when the account has no such connection.
+func (m *Manager) Watch(topic string, connID string, accountID int64) bool {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	client, ok := m.clients[connID]
+	if !ok || client.accountID != accountID {
+		return false
+	}
+
+	if m.watchers[topic] == nil {
+		m.watchers[topic] = make(map[string]*Client)
+	}
+	m.watchers[topic][connID] = client
+
+	return true
+}
+
+// Unwatch unsubscribes connection connID of account from topic.
+func (m *Manager) Unwatch(topic string, connID string, accountID int64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if client, ok := m.watchers[topic][connID]; ok && client.accountID == accountID {
+		m.removeWatcher(topic, connID)
+	}
+}
+
+// UnwatchExcept unsubscribes every connection from topic except the ones of accountID, 0 unsubscribes all.
+func (m *Manager) UnwatchExcept(topic string, accountID int64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for connID, client := range m.watchers[topic] {
+		if client.accountID != accountID || accountID == 0 {
+			m.removeWatcher(topic, connID)
+		}
+	}
+}
+
+// SendToWatchers sends the event to every connection watching topic
+func (m *Manager) SendToWatchers(event Event, topic string, connIDExclusive string) {
+	m.mux.Lock()
+	targets := make([]*Client, 0, len(m.watchers[topic]))
+	for connID, client := range m.watchers[topic] {
 		if connID != connIDExclusive {
-			log.Printf("Sending to client %s", connID)
-			client.egress <- event
+			targets = append(targets, client)
 		}
 	}
+	m.mux.Unlock()
+
+	m.sendToClients(event, targets)
+}
+
+// unwatchAll drops connID from every topic, the lock must be held.
+func (m *Manager) unwatchAll(connID string) {
+	for topic := range m.watchers {
+		m.removeWatcher(topic, connID)
+	}
+}
+
+// removeWatcher drops connID from topic, the lock must be held.
+func (m *Manager) removeWatcher(topic string, connID string) {
+	delete(m.watchers[topic], connID)
+
+	if len(m.watchers[topic]) == 0 {
+		delete(m.watchers, topic)
+	}
+}
+
+// CloseSession closes every connection opened from the session of the account
+func (m *Manager) CloseSession(accountID int64, sessionID string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for connID, client := range m.clients {
+		if client.accountID == accountID && client.sessionID == sessionID {
+			log.Printf("Closing client %s of revoked session", connID)
+			client.connection.Close()
+			delete(m.clients, connID)
+			m.unwatchAll(connID)
+		}
+	}
+}
+
+// CloseAccount closes every connection of the account
+func (m *Manager) CloseAccount(accountID int64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for connID, client := range m.clients {
+		if client.accountID == accountID {
+			log.Printf("Closing client %s of logged out account", connID)
+			client.connection.Close()
+			delete(m.clients, connID)
+			m.unwatchAll(connID)
+		}
+	}
+}
+
+func revocationKeys(otp OTP) []string {
+	keys := make([]string, 0, 2)
+
+	if otp.TokenID != "" {
+		keys = append(keys, utils.TokenRevocationKey(otp.TokenID))
+	}
+
+	if otp.SessionID != "" {
+		keys = append(keys, utils.SessionRevocationKey(otp.SessionID))
+	}
+
+	return keys
 }
@@ -8,8 +8,11 @@ import (
 )
 
 type OTP struct {
-	Key     string
-	Created time.Time
+	Key       string
+	AccountID int64
+	SessionID string
+	TokenID   string // jti of access token the otp was issued for, empty when issued at login
+	Created   time.Time
 }
 
 type RetentionMap map[string]OTP
@@ -23,11 +26,14 @@ func NewRetentionMap(ctx context.Context, retentionPeriod time.Duration) Retenti
 	return rm
 }
 
-// NewOTP creates and adds a new otp to the map
-func (rm RetentionMap) NewOTP() OTP {
+// NewOTP creates and adds a new otp for the account session to the map
+func (rm RetentionMap) NewOTP(accountID int64, sessionID string, tokenID string) OTP {
 	o := OTP{
-		Key:     uuid.NewString(),
-		Created: time.Now(),
+		Key:       uuid.NewString(),
+		AccountID: accountID,
+		SessionID: sessionID,
+		TokenID:   tokenID,
+		Created:   time.Now(),
 	}
 
 	rm[o.Key] = o
@@ -35,16 +41,17 @@ func (rm RetentionMap) NewOTP() OTP {
 }
 
 // VerifyOTP will make sure a OTP exists
-// and return true if so
+// and return it with true if so
 // It will also delete the key so it cant be reused
-func (rm RetentionMap) VerifyOTP(otp string) bool {
+func (rm RetentionMap) VerifyOTP(otp string) (OTP, bool) {
 	// Verify OTP is existing
-	if _, ok := rm[otp]; !ok {
+	o, ok := rm[otp]
+	if !ok {
 		// otp does not exist
-		return false
+		return OTP{}, false
 	}
 	delete(rm, otp)
-	return true
+	return o, true
 }
 
 // Retention will make sure old OTPs are removed
@@ -0,0 +1,45 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/pkg/errors"
+)
+
+// logMailer writes mails into a file, or stdout when no file is given, instead of sending them.
+// It is meant for development only.
+type logMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewLogMailer(path string) (pkg.Mailer, error) {
+	if path == "" {
+		return &logMailer{out: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "os.OpenFile")
+	}
+
+	return &logMailer{out: file}, nil
+}
+
+// Send implements pkg.Mailer.
+func (l *logMailer) Send(ctx context.Context, mail *pkg.Mail) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, err := fmt.Fprintf(l.out, "==== %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), mail.To, mail.Subject, mail.Body)
+
+	return err
+}
@@ -0,0 +1,16 @@
+package mail
+
+import (
+	"os"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// NewMailer returns the mailer selected by MAILER_DRIVER, "smtp" or "log" (default).
+func NewMailer() (pkg.Mailer, error) {
+	switch os.Getenv("MAILER_DRIVER") {
+	case "smtp":
+		return NewSMTPMailer(), nil
+	default:
+		return NewLogMailer(os.Getenv("MAIL_LOG_FILE"))
+	}
+}
@@ -0,0 +1,65 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/pkg/errors"
+)
+
+type smtpMailer struct {
+	address  string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer() pkg.Mailer {
+	host := os.Getenv("SMTP_HOST")
+
+	return &smtpMailer{
+		address:  net.JoinHostPort(host, os.Getenv("SMTP_PORT")),
+		host:     host,
+		username: os.Getenv("SMTP_USERNAME"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+// Send implements pkg.Mailer.
+func (s *smtpMailer) Send(ctx context.Context, mail *pkg.Mail) error {
+	var auth smtp.Auth
+
+	// local sinks like mailpit accept mails without authentication
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	if err := smtp.SendMail(s.address, auth, s.from, []string{mail.To}, buildMessage(s.from, mail)); err != nil {
+		return errors.Wrap(err, "smtp.SendMail")
+	}
+
+	return nil
+}
+
+func buildMessage(from string, mail *pkg.Mail) []byte {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "From: %s\r\n", from)
+	fmt.Fprintf(&sb, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&sb, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(sb.String())
+}
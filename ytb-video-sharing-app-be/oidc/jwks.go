@@ -0,0 +1,122 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"log"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// publicKeys returns signing keys by kid, keys that can not be used are skipped.
+func (s jwkSet) publicKeys() map[string]crypto.PublicKey {
+	keys := make(map[string]crypto.PublicKey, len(s.Keys))
+
+	for _, k := range s.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+
+		if err != nil {
+			log.Println("error when decoding jwk ", k.Kid, ": ", err)
+			continue
+		}
+
+		keys[k.Kid] = key
+	}
+
+	return keys
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "decode n")
+		}
+
+		e, err := decode(k.E)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "decode e")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var (
+			curve     elliptic.Curve
+			ecdhCurve ecdh.Curve
+		)
+
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := decode(k.X)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "decode x")
+		}
+
+		y, err := decode(k.Y)
+
+		if err != nil {
+			return nil, errors.Wrap(err, "decode y")
+		}
+
+		// ecdh rejects points that are not on the curve
+		if _, err = ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, errors.Wrap(err, "invalid point")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, errors.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := decode(k.X)
+
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+
+		return ed25519.PublicKey(x), nil
+	}
+
+	return nil, errors.Errorf("unsupported key type %s", k.Kty)
+}
@@ -0,0 +1,37 @@
+package oidc
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// NewIdentityProvider returns the provider configured by OIDC_* variables, nil when OIDC_ISSUER is not set.
+func NewIdentityProvider() pkg.IdentityProvider {
+	issuer := os.Getenv("OIDC_ISSUER")
+
+	if issuer == "" {
+		return nil
+	}
+
+	name := os.Getenv("OIDC_PROVIDER_NAME")
+	if name == "" {
+		name = "google"
+	}
+
+	scopes := strings.Fields(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return NewProvider(Config{
+		Name:         name,
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       scopes,
+	}, &http.Client{Timeout: 10 * time.Second})
+}
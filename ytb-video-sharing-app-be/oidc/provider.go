@@ -0,0 +1,314 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// keysRefreshInterval limits how often keys are fetched again for an unknown kid, e.g. after the provider rotated keys.
+const keysRefreshInterval = time.Minute
+
+// Config configures an OpenID Connect provider.
+type Config struct {
+	// Name is stored in account identities, it must not change once users logged in
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// provider logs users in with the authorization code flow and PKCE, endpoints and keys are discovered from the issuer.
+type provider struct {
+	config Config
+	client *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(config Config, client *http.Client) pkg.IdentityProvider {
+	return &provider{
+		config: config,
+		client: client,
+	}
+}
+
+// Name implements pkg.IdentityProvider.
+func (p *provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL implements pkg.IdentityProvider.
+func (p *provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	doc, err := p.discover(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+
+	if err != nil {
+		return "", errors.Wrap(err, "url.Parse")
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange implements pkg.IdentityProvider.
+func (p *provider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*pkg.ExternalIdentity, error) {
+	doc, err := p.discover(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+
+	if err != nil {
+		return nil, errors.Wrap(err, "http.NewRequestWithContext")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err = p.do(req, &token); err != nil {
+		return nil, errors.Wrap(err, "exchange code")
+	}
+
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, doc, token.IDToken, nonce)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkg.ExternalIdentity{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+// emailVerified accepts "true" as well, some providers send the claim as string.
+type emailVerified bool
+
+func (e *emailVerified) UnmarshalJSON(data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*e = emailVerified(v)
+	case string:
+		*e = emailVerified(v == "true")
+	default:
+		*e = false
+	}
+
+	return nil
+}
+
+type idTokenClaims struct {
+	Nonce         string        `json:"nonce"`
+	Email         string        `json:"email"`
+	EmailVerified emailVerified `json:"email_verified"`
+	Name          string        `json:"name"`
+	Picture       string        `json:"picture"`
+	jwt.RegisteredClaims
+}
+
+func (p *provider) verifyIDToken(ctx context.Context, doc *discoveryDocument, idToken string, nonce string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		return p.publicKey(ctx, doc, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(doc.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "verify id_token")
+	}
+
+	// nonce ties the ID token to the login started by this client, so a token of another login can not be replayed
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("id_token has no subject")
+	}
+
+	return claims, nil
+}
+
+func (p *provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "http.NewRequestWithContext")
+	}
+
+	doc := &discoveryDocument{}
+	if err = p.do(req, doc); err != nil {
+		return nil, errors.Wrap(err, "discover provider")
+	}
+
+	// a document served for another issuer must not be trusted
+	if doc.Issuer != p.config.Issuer {
+		return nil, errors.Errorf("discovered issuer %q does not match %q", doc.Issuer, p.config.Issuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("discovery document misses endpoints")
+	}
+
+	p.discovery = doc
+
+	return doc, nil
+}
+
+// publicKey returns key that signed the ID token, keys are fetched again when kid is unknown.
+func (p *provider) publicKey(ctx context.Context, doc *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, errors.Errorf("unknown signing key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, doc.JWKSURI, nil)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "http.NewRequestWithContext")
+	}
+
+	var set jwkSet
+	if err = p.do(req, &set); err != nil {
+		return nil, errors.Wrap(err, "fetch jwks")
+	}
+
+	p.keys = set.publicKeys()
+	p.keysFetchedAt = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, errors.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds key by kid, a token without kid is only accepted when the provider has a single key.
+func (p *provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" {
+		if len(p.keys) != 1 {
+			return nil, false
+		}
+
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+
+	return key, ok
+}
+
+// do sends request and decodes JSON response into out.
+func (p *provider) do(req *http.Request, out any) error {
+	res, err := p.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,321 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID    = "client-id"
+	testRedirectURL = "http://localhost:5173/oidc/callback"
+)
+
+type authorization struct {
+	codeChallenge string
+	nonce         string
+}
+
+// fakeProvider is an in-process OpenID Connect provider, the user behind every login is user.
+type fakeProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]authorization
+	user   map[string]any
+	issuer string
+	// audience of issued ID tokens, the client id when empty
+	audience string
+	// nonce of issued ID tokens, the one of the authorization request when empty
+	nonce string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	f := &fakeProvider{
+		key:   key,
+		codes: make(map[string]authorization),
+		user: map[string]any{
+			"sub":            "user-1",
+			"email":          "test@example.com",
+			"email_verified": true,
+			"name":           "Test User",
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", f.discovery)
+	mux.HandleFunc("/jwks", f.jwks)
+	mux.HandleFunc("/authorize", f.authorize)
+	mux.HandleFunc("/token", f.token)
+
+	f.server = httptest.NewServer(mux)
+	f.issuer = f.server.URL
+	t.Cleanup(f.server.Close)
+
+	return f
+}
+
+func (f *fakeProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 f.issuer,
+		"authorization_endpoint": f.server.URL + "/authorize",
+		"token_endpoint":         f.server.URL + "/token",
+		"jwks_uri":               f.server.URL + "/jwks",
+	})
+}
+
+func (f *fakeProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   encode(f.key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(f.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize logs the user in right away and redirects back with a code.
+func (f *fakeProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("client_id") != testClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	code := base64.RawURLEncoding.EncodeToString(buf)
+
+	f.mu.Lock()
+	f.codes[code] = authorization{codeChallenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	f.mu.Unlock()
+
+	http.Redirect(w, r, query.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {query.Get("state")}}.Encode(), http.StatusFound)
+}
+
+func (f *fakeProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	auth, ok := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code"))
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge || r.PostForm.Get("redirect_uri") != testRedirectURL {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   f.issuer,
+		"aud":   testClientID,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"iat":   time.Now().Unix(),
+		"nonce": auth.nonce,
+	}
+	if f.audience != "" {
+		claims["aud"] = f.audience
+	}
+	if f.nonce != "" {
+		claims["nonce"] = f.nonce
+	}
+	for k, v := range f.user {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+
+	idToken, err := token.SignedString(f.key)
+
+	if err != nil {
+		http.Error(w, "server_error", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func newTestProvider(f *fakeProvider) *provider {
+	return NewProvider(Config{
+		Name:        "fake",
+		Issuer:      f.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+		Scopes:      []string{"openid", "email", "profile"},
+	}, f.server.Client()).(*provider)
+}
+
+// login follows the authorization URL like a browser would and returns the code sent back to the redirect URL.
+func login(t *testing.T, f *fakeProvider, p *provider, state, nonce, codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+
+	authURL, err := p.AuthCodeURL(context.Background(), state, nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	require.NoError(t, err)
+
+	client := f.server.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }
+
+	res, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusFound, res.StatusCode)
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, state, location.Query().Get("state"))
+
+	return location.Query().Get("code")
+}
+
+func TestExchange(t *testing.T) {
+	t.Run("Should return identity of logged in user", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+		identity, err := p.Exchange(context.Background(), code, "verifier-verifier-verifier-verifier-verifier", "nonce")
+
+		require.NoError(t, err)
+		assert.Equal(t, "fake", identity.Provider)
+		assert.Equal(t, "user-1", identity.Subject)
+		assert.Equal(t, "test@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "Test User", identity.Name)
+	})
+
+	t.Run("Should accept email_verified sent as string", func(t *testing.T) {
+		f := newFakeProvider(t)
+		f.user["email_verified"] = "true"
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier")
+		identity, err := p.Exchange(context.Background(), code, "verifier", "nonce")
+
+		require.NoError(t, err)
+		assert.True(t, identity.EmailVerified)
+	})
+
+	t.Run("Should reject wrong code verifier", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier")
+		identity, err := p.Exchange(context.Background(), code, "another-verifier", "nonce")
+
+		assert.Nil(t, identity)
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reject code used twice", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier")
+		_, err := p.Exchange(context.Background(), code, "verifier", "nonce")
+		require.NoError(t, err)
+
+		_, err = p.Exchange(context.Background(), code, "verifier", "nonce")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reject ID token of another login", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier")
+		_, err := p.Exchange(context.Background(), code, "verifier", "another-nonce")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reject ID token issued for another client", func(t *testing.T) {
+		f := newFakeProvider(t)
+		f.audience = "another-client"
+		p := newTestProvider(f)
+
+		code := login(t, f, p, "state", "nonce", "verifier")
+		_, err := p.Exchange(context.Background(), code, "verifier", "nonce")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reject ID token signed by unknown key", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+		code := login(t, f, p, "state", "nonce", "verifier")
+
+		// keys were fetched by a previous login, the provider is then compromised with a new key
+		p.keys = map[string]crypto.PublicKey{}
+		p.keysFetchedAt = time.Now()
+
+		_, err := p.Exchange(context.Background(), code, "verifier", "nonce")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDiscovery(t *testing.T) {
+	t.Run("Should reject provider serving another issuer", func(t *testing.T) {
+		f := newFakeProvider(t)
+		f.issuer = "https://evil.example.com"
+		p := newTestProvider(f)
+
+		_, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should send PKCE challenge in authorization URL", func(t *testing.T) {
+		f := newFakeProvider(t)
+		p := newTestProvider(f)
+
+		authURL, err := p.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(authURL)
+		require.NoError(t, err)
+
+		query := parsed.Query()
+		assert.Equal(t, f.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+		assert.Equal(t, "challenge", query.Get("code_challenge"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.Equal(t, "openid email profile", query.Get("scope"))
+		assert.Equal(t, testRedirectURL, query.Get("redirect_uri"))
+	})
+}
@@ -0,0 +1,47 @@
+package pkg
+
+import "context"
+
+type AuditLogger interface {
+	// Records the event, returning an error if the operation fails.
+	Log(ctx context.Context, event *AuditEvent) error
+}
+
+// Kinds of resources audit events are about.
+const (
+	AuditTargetAccount             = "account"
+	AuditTargetVideo               = "video"
+	AuditTargetVideoReport         = "video_report"
+	AuditTargetPersonalAccessToken = "personal_access_token"
+)
+
+type AuditEvent struct {
+	Action string
+	// ActorID is the account performing the action, 0 for anonymous callers and the system
+	ActorID    int64
+	TargetType string
+	TargetID   int64
+	Detail     string
+	IPAddress  string
+	UserAgent  string
+	RequestID  string
+}
+
+// RequestMetadataKey is the key RequestMetadata of a request is stored under in its context.
+const RequestMetadataKey = "request_metadata"
+
+// RequestMetadata describes the request an audit event happened in.
+type RequestMetadata struct {
+	RequestID string
+	IPAddress string
+	UserAgent string
+}
+
+// RequestMetadataFromContext returns metadata of the request ctx belongs to, empty outside of requests.
+func RequestMetadataFromContext(ctx context.Context) *RequestMetadata {
+	if meta, ok := ctx.Value(RequestMetadataKey).(*RequestMetadata); ok {
+		return meta
+	}
+
+	return &RequestMetadata{}
+}
@@ -40,4 +40,7 @@ type Rows interface {
 	Scan(dst ...any) error
 	Close()
 	Next() bool
+
+	// Returns the error, if any, that was encountered during iteration.
+	Err() error
 }
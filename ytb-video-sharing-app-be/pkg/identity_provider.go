@@ -0,0 +1,24 @@
+package pkg
+
+import "context"
+
+// ExternalIdentity is the user as told by an identity provider.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+type IdentityProvider interface {
+	// Returns name the provider is stored with in account identities.
+	Name() string
+
+	// Returns URL the user is redirected to, to log in at the provider.
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+
+	// Exchanges authorization code for the user, after verifying the ID token the provider issued for nonce.
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*ExternalIdentity, error)
+}
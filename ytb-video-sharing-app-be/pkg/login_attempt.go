@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttemptState is what is known about failed logins of a key, an account or an IP.
+type LoginAttemptState struct {
+	// Failures counts failed attempts since the time asked for
+	Failures int
+	// LastFailureAt is zero when there is no failure
+	LastFailureAt time.Time
+	// LockedUntil is zero when the key was never locked
+	LockedUntil time.Time
+}
+
+type LoginAttemptStore interface {
+	// Records a failed attempt of key at time at.
+	AddFailure(ctx context.Context, key string, at time.Time) error
+
+	// Returns failed attempts of key since since and until when key is locked.
+	GetState(ctx context.Context, key string, since time.Time) (*LoginAttemptState, error)
+
+	// Locks key until lockedUntil, a later lock wins when key is already locked.
+	Lock(ctx context.Context, key string, lockedUntil time.Time) error
+
+	// Forgets failed attempts and lock of key.
+	Reset(ctx context.Context, key string) error
+}
+
+type LoginThrottle interface {
+	// Returns how long the client must wait before trying to log in to the account again, zero when it may try now.
+	Check(ctx context.Context, email string, ip string) (time.Duration, error)
+
+	// Records a failed login and returns how long the client must wait before the next attempt.
+	Fail(ctx context.Context, email string, ip string) (time.Duration, error)
+
+	// Forgets failed logins of the account once it logged in.
+	Succeed(ctx context.Context, email string) error
+}
@@ -0,0 +1,14 @@
+package pkg
+
+import "context"
+
+type Mailer interface {
+	// Sends the mail, returning an error if the operation fails.
+	Send(ctx context.Context, mail *Mail) error
+}
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
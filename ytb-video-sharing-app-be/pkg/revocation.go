@@ -0,0 +1,14 @@
+package pkg
+
+import (
+	"context"
+	"time"
+)
+
+type RevocationList interface {
+	// Revokes the id until expiresAt, once every token carrying it is expired anyway.
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+
+	// Reports whether one of the ids is revoked.
+	IsRevoked(ctx context.Context, ids ...string) (bool, error)
+}
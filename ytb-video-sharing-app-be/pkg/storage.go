@@ -0,0 +1,14 @@
+package pkg
+
+import (
+	"context"
+	"io"
+)
+
+type BlobStore interface {
+	// Stores the content under the key, returning the public URL of the stored blob or an error.
+	Put(ctx context.Context, key string, content io.Reader) (string, error)
+
+	// Deletes the blob stored under the key, returning an error if the operation fails.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,39 @@
+package revocation
+
+import (
+	"context"
+	"log"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// databaseRevocationList keeps revoked ids in revoked_tokens table, shared by every instance.
+type databaseRevocationList struct {
+	revokedTokenRepository repository.RevokedTokenRepository
+}
+
+func NewDatabaseRevocationList(revokedTokenRepository repository.RevokedTokenRepository) pkg.RevocationList {
+	return &databaseRevocationList{
+		revokedTokenRepository: revokedTokenRepository,
+	}
+}
+
+// Revoke implements pkg.RevocationList.
+func (d *databaseRevocationList) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	if err := d.revokedTokenRepository.Save(ctx, id, expiresAt); err != nil {
+		return err
+	}
+
+	// revoking is rare enough to clean up expired rows along the way
+	if err := d.revokedTokenRepository.DeleteExpired(ctx); err != nil {
+		log.Println("error when deleting expired revoked tokens: ", err)
+	}
+
+	return nil
+}
+
+// IsRevoked implements pkg.RevocationList.
+func (d *databaseRevocationList) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	return d.revokedTokenRepository.Exists(ctx, ids)
+}
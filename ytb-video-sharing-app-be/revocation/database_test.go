@@ -0,0 +1,123 @@
+package revocation
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type databaseRevocationConfig struct {
+	db   *mocks.MockDatabase
+	row  *mocks.MockRow
+	list *databaseRevocationList
+}
+
+func setupDatabaseRevocationList(t *testing.T) *databaseRevocationConfig {
+	ctrl := gomock.NewController(t)
+	db := mocks.NewMockDatabase(ctrl)
+
+	return &databaseRevocationConfig{
+		db:   db,
+		row:  mocks.NewMockRow(ctrl),
+		list: NewDatabaseRevocationList(repository.NewRevokedTokenRepository(db)).(*databaseRevocationList),
+	}
+}
+
+func TestDatabaseRevocationListRevoke(t *testing.T) {
+	t.Run("Should save id and delete expired ids", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+		expiresAt := time.Now().Add(time.Hour)
+
+		gomock.InOrder(
+			cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO revoked_tokens"), "session:1", expiresAt).Return(driver.RowsAffected(1), nil),
+			cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("DELETE FROM revoked_tokens")).Return(driver.RowsAffected(0), nil),
+		)
+
+		assert.NoError(t, cfg.list.Revoke(ctx, "session:1", expiresAt))
+	})
+
+	t.Run("Should return error if save fails", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO revoked_tokens"), gomock.Any(), gomock.Any()).Return(nil, expectedErr)
+
+		assert.Equal(t, expectedErr, cfg.list.Revoke(ctx, "session:1", time.Now().Add(time.Hour)))
+	})
+
+	t.Run("Should not fail if deleting expired ids fails", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("INSERT INTO revoked_tokens"), gomock.Any(), gomock.Any()).Return(driver.RowsAffected(1), nil)
+		cfg.db.EXPECT().ExecWithResult(ctx, gomock.Regex("DELETE FROM revoked_tokens")).Return(nil, errors.New("db error"))
+
+		assert.NoError(t, cfg.list.Revoke(ctx, "session:1", time.Now().Add(time.Hour)))
+	})
+}
+
+func TestDatabaseRevocationListIsRevoked(t *testing.T) {
+	t.Run("Should only count ids that are not expired", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Regex("expires_at > CURRENT_TIMESTAMP"), "jti:1", "session:1").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*bool) = true
+			return nil
+		})
+
+		revoked, err := cfg.list.IsRevoked(ctx, "jti:1", "session:1")
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Should not report unknown id", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "jti:2").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(args ...interface{}) error {
+			*args[0].(*bool) = false
+			return nil
+		})
+
+		revoked, err := cfg.list.IsRevoked(ctx, "jti:2")
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Should not query without ids", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+
+		revoked, err := cfg.list.IsRevoked(context.Background())
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Should return error if query fails", func(t *testing.T) {
+		cfg := setupDatabaseRevocationList(t)
+		ctx := context.Background()
+
+		expectedErr := errors.New("db error")
+		cfg.db.EXPECT().QueryRow(ctx, gomock.Any(), "jti:1").Return(cfg.row)
+		cfg.row.EXPECT().Scan(gomock.Any()).Return(expectedErr)
+
+		revoked, err := cfg.list.IsRevoked(ctx, "jti:1")
+
+		assert.Equal(t, expectedErr, err)
+		assert.False(t, revoked)
+	})
+}
@@ -0,0 +1,74 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// memoryRevocationList keeps revoked ids in memory until they expire.
+type memoryRevocationList struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+// NewMemoryRevocationList creates the list and removes expired ids every cleanupInterval until ctx is done.
+func NewMemoryRevocationList(ctx context.Context, cleanupInterval time.Duration) pkg.RevocationList {
+	m := &memoryRevocationList{
+		revoked: make(map[string]time.Time),
+	}
+
+	go m.cleanup(ctx, cleanupInterval)
+
+	return m
+}
+
+// Revoke implements pkg.RevocationList.
+func (m *memoryRevocationList) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.revoked[id]; !ok || expiresAt.After(current) {
+		m.revoked[id] = expiresAt
+	}
+
+	return nil
+}
+
+// IsRevoked implements pkg.RevocationList.
+func (m *memoryRevocationList) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	for _, id := range ids {
+		if expiresAt, ok := m.revoked[id]; ok && expiresAt.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (m *memoryRevocationList) cleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+
+			m.mu.Lock()
+			for id, expiresAt := range m.revoked {
+				if !expiresAt.After(now) {
+					delete(m.revoked, id)
+				}
+			}
+			m.mu.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMemoryRevocationList(t *testing.T, cleanupInterval time.Duration) *memoryRevocationList {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return NewMemoryRevocationList(ctx, cleanupInterval).(*memoryRevocationList)
+}
+
+func TestMemoryRevocationList(t *testing.T) {
+	t.Run("Should report revoked id until it expires", func(t *testing.T) {
+		m := setupMemoryRevocationList(t, time.Hour)
+		ctx := context.Background()
+
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(time.Hour)))
+
+		revoked, err := m.IsRevoked(ctx, "jti:1", "session:1")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Should not report unknown id", func(t *testing.T) {
+		m := setupMemoryRevocationList(t, time.Hour)
+		ctx := context.Background()
+
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(time.Hour)))
+
+		revoked, err := m.IsRevoked(ctx, "jti:1", "session:2")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		revoked, err = m.IsRevoked(ctx)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Should not report expired id", func(t *testing.T) {
+		m := setupMemoryRevocationList(t, time.Hour)
+		ctx := context.Background()
+
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(-time.Second)))
+
+		revoked, err := m.IsRevoked(ctx, "session:1")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+
+	t.Run("Should keep later expiry when id is revoked twice", func(t *testing.T) {
+		m := setupMemoryRevocationList(t, time.Hour)
+		ctx := context.Background()
+
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(time.Hour)))
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(-time.Second)))
+
+		revoked, err := m.IsRevoked(ctx, "session:1")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("Should remove expired ids on cleanup", func(t *testing.T) {
+		m := setupMemoryRevocationList(t, 10*time.Millisecond)
+		ctx := context.Background()
+
+		assert.NoError(t, m.Revoke(ctx, "session:1", time.Now().Add(-time.Second)))
+		assert.NoError(t, m.Revoke(ctx, "session:2", time.Now().Add(time.Hour)))
+
+		assert.Eventually(t, func() bool {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+
+			_, expiredLeft := m.revoked["session:1"]
+			_, activeLeft := m.revoked["session:2"]
+			return !expiredLeft && activeLeft
+		}, time.Second, 10*time.Millisecond)
+	})
+}
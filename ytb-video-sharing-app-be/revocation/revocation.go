@@ -0,0 +1,20 @@
+package revocation
+
+import (
+	"context"
+	"os"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// NewRevocationList returns the revocation list selected by REVOCATION_LIST_DRIVER, "database" or "memory" (default).
+// The memory one is lost on restart and not shared between instances.
+func NewRevocationList(revokedTokenRepository repository.RevokedTokenRepository) pkg.RevocationList {
+	switch os.Getenv("REVOCATION_LIST_DRIVER") {
+	case "database":
+		return NewDatabaseRevocationList(revokedTokenRepository)
+	default:
+		return NewMemoryRevocationList(context.Background(), time.Minute)
+	}
+}
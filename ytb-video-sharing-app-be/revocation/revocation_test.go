@@ -0,0 +1,32 @@
+package revocation
+
+import (
+	"testing"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewRevocationList(t *testing.T) {
+	revokedTokenRepository := repository.NewRevokedTokenRepository(mocks.NewMockDatabase(gomock.NewController(t)))
+
+	t.Run("Should use database driver when configured", func(t *testing.T) {
+		t.Setenv("REVOCATION_LIST_DRIVER", "database")
+
+		assert.IsType(t, &databaseRevocationList{}, NewRevocationList(revokedTokenRepository))
+	})
+
+	t.Run("Should use memory driver when configured", func(t *testing.T) {
+		t.Setenv("REVOCATION_LIST_DRIVER", "memory")
+
+		assert.IsType(t, &memoryRevocationList{}, NewRevocationList(revokedTokenRepository))
+	})
+
+	t.Run("Should default to memory driver", func(t *testing.T) {
+		t.Setenv("REVOCATION_LIST_DRIVER", "")
+
+		assert.IsType(t, &memoryRevocationList{}, NewRevocationList(revokedTokenRepository))
+	})
+}
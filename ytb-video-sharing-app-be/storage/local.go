@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"ytb-video-sharing-app-be/pkg"
+
+	"github.com/pkg/errors"
+)
+
+type localBlobStore struct {
+	baseDir   string
+	publicURL string
+}
+
+// NewLocalBlobStore stores blobs on local filesystem under BLOB_STORAGE_DIR,
+// they are expected to be served at BLOB_PUBLIC_URL.
+func NewLocalBlobStore() (pkg.BlobStore, error) {
+	baseDir := os.Getenv("BLOB_STORAGE_DIR")
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "os.MkdirAll")
+	}
+
+	return &localBlobStore{
+		baseDir:   baseDir,
+		publicURL: strings.TrimRight(os.Getenv("BLOB_PUBLIC_URL"), "/"),
+	}, nil
+}
+
+// Put implements pkg.BlobStore.
+func (l *localBlobStore) Put(ctx context.Context, key string, content io.Reader) (string, error) {
+	path, err := l.path(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", errors.Wrap(err, "os.MkdirAll")
+	}
+
+	// write into temp file then rename, so a half written blob is never served
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+
+	if err != nil {
+		return "", errors.Wrap(err, "os.CreateTemp")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return "", errors.Wrap(err, "io.Copy")
+	}
+
+	if err = tmp.Close(); err != nil {
+		return "", errors.Wrap(err, "tmp.Close")
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", errors.Wrap(err, "os.Rename")
+	}
+
+	return l.publicURL + "/" + filepath.ToSlash(key), nil
+}
+
+// Delete implements pkg.BlobStore.
+func (l *localBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "os.Remove")
+	}
+
+	return nil
+}
+
+// path resolves the key inside base dir and rejects keys escaping it.
+func (l *localBlobStore) path(key string) (string, error) {
+	path := filepath.Join(l.baseDir, filepath.FromSlash(key))
+
+	rel, err := filepath.Rel(l.baseDir, path)
+
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", errors.New("invalid blob key")
+	}
+
+	return path, nil
+}
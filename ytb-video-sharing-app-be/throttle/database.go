@@ -0,0 +1,61 @@
+package throttle
+
+import (
+	"context"
+	"log"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// databaseLoginAttemptStore keeps login attempts in login_attempts and login_lockouts tables, shared by every instance.
+type databaseLoginAttemptStore struct {
+	loginAttemptRepository repository.LoginAttemptRepository
+}
+
+// NewDatabaseLoginAttemptStore creates the store and deletes attempts older than retention every cleanupInterval until ctx is done.
+func NewDatabaseLoginAttemptStore(ctx context.Context, loginAttemptRepository repository.LoginAttemptRepository, cleanupInterval, retention time.Duration) pkg.LoginAttemptStore {
+	d := &databaseLoginAttemptStore{
+		loginAttemptRepository: loginAttemptRepository,
+	}
+
+	go d.cleanup(ctx, cleanupInterval, retention)
+
+	return d
+}
+
+// AddFailure implements pkg.LoginAttemptStore.
+func (d *databaseLoginAttemptStore) AddFailure(ctx context.Context, key string, at time.Time) error {
+	return d.loginAttemptRepository.SaveFailure(ctx, key, at)
+}
+
+// GetState implements pkg.LoginAttemptStore.
+func (d *databaseLoginAttemptStore) GetState(ctx context.Context, key string, since time.Time) (*pkg.LoginAttemptState, error) {
+	return d.loginAttemptRepository.GetState(ctx, key, since)
+}
+
+// Lock implements pkg.LoginAttemptStore.
+func (d *databaseLoginAttemptStore) Lock(ctx context.Context, key string, lockedUntil time.Time) error {
+	return d.loginAttemptRepository.SaveLockout(ctx, key, lockedUntil)
+}
+
+// Reset implements pkg.LoginAttemptStore.
+func (d *databaseLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	return d.loginAttemptRepository.Delete(ctx, key)
+}
+
+func (d *databaseLoginAttemptStore) cleanup(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.loginAttemptRepository.DeleteExpired(ctx, time.Now().Add(-retention)); err != nil {
+				log.Println("error when deleting expired login attempts: ", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package throttle
+
+import (
+	"context"
+	"strings"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// LoginThrottleConfig tunes LoginThrottle, every limit counts failures within Window only.
+type LoginThrottleConfig struct {
+	Window time.Duration
+	// BaseDelay is the first backoff, doubled after each further failure
+	BaseDelay    time.Duration
+	LockDuration time.Duration
+	// AccountLockThreshold and IPLockThreshold are failures that lock the key, backoff starts after a third of them
+	AccountLockThreshold int
+	IPLockThreshold      int
+}
+
+type limit struct {
+	freeAttempts  int
+	lockThreshold int
+}
+
+// loginThrottle slows down password guessing of an account and from an IP.
+//
+// Every failure within the window past the free attempts doubles the wait before the next attempt,
+// reaching the threshold locks the key for LockDuration. An IP gets a higher threshold, so users
+// behind the same NAT do not lock each other out.
+type loginThrottle struct {
+	store  pkg.LoginAttemptStore
+	config LoginThrottleConfig
+	now    func() time.Time
+}
+
+func NewLoginThrottle(store pkg.LoginAttemptStore, config LoginThrottleConfig) pkg.LoginThrottle {
+	return &loginThrottle{
+		store:  store,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Check implements pkg.LoginThrottle.
+func (l *loginThrottle) Check(ctx context.Context, email string, ip string) (time.Duration, error) {
+	now := l.now()
+
+	var wait time.Duration
+	for key, lim := range l.keys(email, ip) {
+		state, err := l.store.GetState(ctx, key, now.Add(-l.config.Window))
+
+		if err != nil {
+			return 0, err
+		}
+
+		wait = max(wait, l.waitFor(state, lim, now))
+	}
+
+	return wait, nil
+}
+
+// Fail implements pkg.LoginThrottle.
+func (l *loginThrottle) Fail(ctx context.Context, email string, ip string) (time.Duration, error) {
+	now := l.now()
+
+	var wait time.Duration
+	for key, lim := range l.keys(email, ip) {
+		if err := l.store.AddFailure(ctx, key, now); err != nil {
+			return 0, err
+		}
+
+		state, err := l.store.GetState(ctx, key, now.Add(-l.config.Window))
+
+		if err != nil {
+			return 0, err
+		}
+
+		if state.Failures >= lim.lockThreshold {
+			state.LockedUntil = now.Add(l.config.LockDuration)
+
+			if err = l.store.Lock(ctx, key, state.LockedUntil); err != nil {
+				return 0, err
+			}
+		}
+
+		wait = max(wait, l.waitFor(state, lim, now))
+	}
+
+	return wait, nil
+}
+
+// Succeed implements pkg.LoginThrottle.
+//
+// Failures of the IP are kept, otherwise logging in to an owned account would reset guessing of others.
+func (l *loginThrottle) Succeed(ctx context.Context, email string) error {
+	return l.store.Reset(ctx, accountKey(email))
+}
+
+func (l *loginThrottle) keys(email string, ip string) map[string]limit {
+	keys := map[string]limit{
+		accountKey(email): {freeAttempts: l.config.AccountLockThreshold / 3, lockThreshold: l.config.AccountLockThreshold},
+	}
+
+	if ip != "" {
+		keys["ip:"+ip] = limit{freeAttempts: l.config.IPLockThreshold / 3, lockThreshold: l.config.IPLockThreshold}
+	}
+
+	return keys
+}
+
+func (l *loginThrottle) waitFor(state *pkg.LoginAttemptState, lim limit, now time.Time) time.Duration {
+	wait := state.LockedUntil.Sub(now)
+
+	if state.Failures > lim.freeAttempts {
+		backoff := l.config.BaseDelay
+		for i := lim.freeAttempts + 1; i < state.Failures && backoff < l.config.LockDuration; i++ {
+			backoff *= 2
+		}
+
+		wait = max(wait, state.LastFailureAt.Add(min(backoff, l.config.LockDuration)).Sub(now))
+	}
+
+	return max(wait, 0)
+}
+
+// accountKey does not need the account to exist, so unknown emails are throttled the same way.
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
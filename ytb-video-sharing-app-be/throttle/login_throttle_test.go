@@ -0,0 +1,140 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func setupLoginThrottle(t *testing.T) (*loginThrottle, *fakeClock) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	return &loginThrottle{
+		store: NewMemoryLoginAttemptStore(ctx, time.Hour, time.Hour),
+		config: LoginThrottleConfig{
+			Window:               15 * time.Minute,
+			BaseDelay:            time.Second,
+			LockDuration:         10 * time.Minute,
+			AccountLockThreshold: 9,
+			IPLockThreshold:      30,
+		},
+		now: clock.Now,
+	}, clock
+}
+
+func failTimes(t *testing.T, l *loginThrottle, email, ip string, n int) time.Duration {
+	var wait time.Duration
+	for range n {
+		var err error
+		wait, err = l.Fail(context.Background(), email, ip)
+		require.NoError(t, err)
+	}
+
+	return wait
+}
+
+func TestLoginThrottleBackoff(t *testing.T) {
+	t.Run("Should not slow down free attempts", func(t *testing.T) {
+		l, _ := setupLoginThrottle(t)
+
+		wait := failTimes(t, l, "test@example.com", "1.1.1.1", 3)
+		assert.Zero(t, wait)
+
+		wait, err := l.Check(context.Background(), "test@example.com", "1.1.1.1")
+		assert.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("Should double wait after each further failure", func(t *testing.T) {
+		l, _ := setupLoginThrottle(t)
+		failTimes(t, l, "test@example.com", "1.1.1.1", 3)
+
+		assert.Equal(t, time.Second, failTimes(t, l, "test@example.com", "1.1.1.1", 1))
+		assert.Equal(t, 2*time.Second, failTimes(t, l, "test@example.com", "1.1.1.1", 1))
+		assert.Equal(t, 4*time.Second, failTimes(t, l, "test@example.com", "1.1.1.1", 1))
+	})
+
+	t.Run("Should allow attempt once backoff is over", func(t *testing.T) {
+		l, clock := setupLoginThrottle(t)
+		failTimes(t, l, "test@example.com", "1.1.1.1", 5)
+
+		clock.now = clock.now.Add(time.Second)
+		wait, err := l.Check(context.Background(), "test@example.com", "1.1.1.1")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, wait)
+
+		clock.now = clock.now.Add(time.Second)
+		wait, err = l.Check(context.Background(), "test@example.com", "1.1.1.1")
+		assert.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("Should forget failures out of the sliding window", func(t *testing.T) {
+		l, clock := setupLoginThrottle(t)
+		failTimes(t, l, "test@example.com", "1.1.1.1", 3)
+
+		clock.now = clock.now.Add(16 * time.Minute)
+
+		assert.Zero(t, failTimes(t, l, "test@example.com", "1.1.1.1", 1))
+	})
+}
+
+func TestLoginThrottleLock(t *testing.T) {
+	t.Run("Should lock account after threshold", func(t *testing.T) {
+		l, clock := setupLoginThrottle(t)
+
+		wait := failTimes(t, l, "test@example.com", "1.1.1.1", 9)
+		assert.Equal(t, 10*time.Minute, wait)
+
+		clock.now = clock.now.Add(4 * time.Minute)
+		wait, err := l.Check(context.Background(), "Test@Example.com ", "2.2.2.2")
+		assert.NoError(t, err)
+		assert.Equal(t, 6*time.Minute, wait)
+	})
+
+	t.Run("Should lock IP guessing many accounts", func(t *testing.T) {
+		l, _ := setupLoginThrottle(t)
+
+		for i := range 30 {
+			_, err := l.Fail(context.Background(), string(rune('a'+i))+"@example.com", "1.1.1.1")
+			require.NoError(t, err)
+		}
+
+		wait, err := l.Check(context.Background(), "another@example.com", "1.1.1.1")
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, wait)
+
+		wait, err = l.Check(context.Background(), "another@example.com", "2.2.2.2")
+		assert.NoError(t, err)
+		assert.Zero(t, wait)
+	})
+
+	t.Run("Should reset account but not IP on success", func(t *testing.T) {
+		l, _ := setupLoginThrottle(t)
+		failTimes(t, l, "test@example.com", "1.1.1.1", 5)
+
+		assert.NoError(t, l.Succeed(context.Background(), "test@example.com"))
+
+		state, err := l.store.GetState(context.Background(), accountKey("test@example.com"), time.Time{})
+		assert.NoError(t, err)
+		assert.Zero(t, state.Failures)
+
+		state, err = l.store.GetState(context.Background(), "ip:1.1.1.1", time.Time{})
+		assert.NoError(t, err)
+		assert.Equal(t, 5, state.Failures)
+	})
+}
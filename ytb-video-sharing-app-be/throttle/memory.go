@@ -0,0 +1,133 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+type memoryEntry struct {
+	failures    []time.Time
+	lockedUntil time.Time
+}
+
+// memoryLoginAttemptStore keeps login attempts in memory, lost on restart and not shared between instances.
+type memoryLoginAttemptStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryLoginAttemptStore creates the store and forgets attempts older than retention every cleanupInterval until ctx is done.
+func NewMemoryLoginAttemptStore(ctx context.Context, cleanupInterval, retention time.Duration) pkg.LoginAttemptStore {
+	m := &memoryLoginAttemptStore{
+		entries: make(map[string]*memoryEntry),
+	}
+
+	go m.cleanup(ctx, cleanupInterval, retention)
+
+	return m
+}
+
+// AddFailure implements pkg.LoginAttemptStore.
+func (m *memoryLoginAttemptStore) AddFailure(ctx context.Context, key string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+
+	entry.failures = append(entry.failures, at)
+
+	return nil
+}
+
+// GetState implements pkg.LoginAttemptStore.
+func (m *memoryLoginAttemptStore) GetState(ctx context.Context, key string, since time.Time) (*pkg.LoginAttemptState, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := &pkg.LoginAttemptState{}
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return state, nil
+	}
+
+	for _, at := range entry.failures {
+		if !at.After(since) {
+			continue
+		}
+
+		state.Failures++
+		if at.After(state.LastFailureAt) {
+			state.LastFailureAt = at
+		}
+	}
+
+	state.LockedUntil = entry.lockedUntil
+
+	return state, nil
+}
+
+// Lock implements pkg.LoginAttemptStore.
+func (m *memoryLoginAttemptStore) Lock(ctx context.Context, key string, lockedUntil time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoryEntry{}
+		m.entries[key] = entry
+	}
+
+	if lockedUntil.After(entry.lockedUntil) {
+		entry.lockedUntil = lockedUntil
+	}
+
+	return nil
+}
+
+// Reset implements pkg.LoginAttemptStore.
+func (m *memoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+
+	return nil
+}
+
+func (m *memoryLoginAttemptStore) cleanup(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			before := now.Add(-retention)
+
+			m.mu.Lock()
+			for key, entry := range m.entries {
+				failures := entry.failures[:0]
+				for _, at := range entry.failures {
+					if at.After(before) {
+						failures = append(failures, at)
+					}
+				}
+				entry.failures = failures
+
+				if len(entry.failures) == 0 && !entry.lockedUntil.After(now) {
+					delete(m.entries, key)
+				}
+			}
+			m.mu.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package throttle
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+	"ytb-video-sharing-app-be/internal/repository"
+	"ytb-video-sharing-app-be/pkg"
+)
+
+// NewLoginAttemptStore returns the store selected by LOGIN_ATTEMPT_STORE_DRIVER, "database" or "memory" (default).
+// The memory one is lost on restart and not shared between instances.
+func NewLoginAttemptStore(loginAttemptRepository repository.LoginAttemptRepository) pkg.LoginAttemptStore {
+	retention := max(durationEnv("LOGIN_ATTEMPT_WINDOW", 15*time.Minute), durationEnv("LOGIN_LOCK_DURATION", 15*time.Minute))
+
+	switch os.Getenv("LOGIN_ATTEMPT_STORE_DRIVER") {
+	case "database":
+		return NewDatabaseLoginAttemptStore(context.Background(), loginAttemptRepository, time.Minute, retention)
+	default:
+		return NewMemoryLoginAttemptStore(context.Background(), time.Minute, retention)
+	}
+}
+
+// NewLoginThrottleFromEnv returns LoginThrottle configured by LOGIN_* variables.
+func NewLoginThrottleFromEnv(store pkg.LoginAttemptStore) pkg.LoginThrottle {
+	return NewLoginThrottle(store, LoginThrottleConfig{
+		Window:               durationEnv("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+		BaseDelay:            durationEnv("LOGIN_BACKOFF_BASE_DELAY", time.Second),
+		LockDuration:         durationEnv("LOGIN_LOCK_DURATION", 15*time.Minute),
+		AccountLockThreshold: intEnv("LOGIN_ACCOUNT_LOCK_THRESHOLD", 10),
+		IPLockThreshold:      intEnv("LOGIN_IP_LOCK_THRESHOLD", 100),
+	})
+}
+
+func durationEnv(envVar string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(envVar))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func intEnv(envVar string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(envVar))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -3,4 +3,12 @@ package utils
 const (
 	INTERNAL_SERVER_ERROR = "Internal server error!"
 	LOGIN_FAIL            = "Wrong email or password, please try again!"
+	REFRESH_TOKEN_REUSED  = "Refresh token has already been used, the session is revoked"
+	TOO_MANY_LOGIN_FAILS  = "Too many failed login attempts, please try again later!"
+	ACCOUNT_SUSPENDED     = "Account is suspended"
+
+	// size in pixels of the stored square avatar
+	AVATAR_SIZE = 256
+	// max bytes accepted for an uploaded avatar
+	MAX_AVATAR_UPLOAD_SIZE = 5 << 20
 )
@@ -0,0 +1,45 @@
+package utils
+
+import "strings"
+
+// DeviceLabel builds a readable label like "Chrome on Windows" from user agent.
+func DeviceLabel(userAgent string) string {
+	browser := matchFirst(userAgent, [][2]string{
+		// order matters, Edge and Opera also contain "Chrome", Chrome also contains "Safari"
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	})
+
+	os := matchFirst(userAgent, [][2]string{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	default:
+		return "Unknown device"
+	}
+}
+
+func matchFirst(s string, patterns [][2]string) string {
+	for _, p := range patterns {
+		if strings.Contains(s, p[0]) {
+			return p[1]
+		}
+	}
+
+	return ""
+}
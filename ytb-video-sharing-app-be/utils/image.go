@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image type")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// maxImagePixels guards against decoding images that would take too much memory
+const maxImagePixels = 40_000_000
+
+// allowedImageTypes maps detected content type to extension of the re-encoded image
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "png",
+}
+
+// ResizeImage crops the image to a centered square and scales it down to size x size.
+// It returns the encoded image along with its file extension.
+func ResizeImage(data []byte, size int) ([]byte, string, error) {
+	ext, ok := allowedImageTypes[http.DetectContentType(data)]
+
+	if !ok {
+		return nil, "", ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, "", errors.Wrap(err, "image.DecodeConfig")
+	}
+
+	if config.Width*config.Height > maxImagePixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, "", errors.Wrap(err, "image.Decode")
+	}
+
+	dst := scaleSquare(src, cropSquare(src), size)
+
+	buf := new(bytes.Buffer)
+
+	if ext == "jpg" {
+		err = jpeg.Encode(buf, dst, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(buf, dst)
+	}
+
+	if err != nil {
+		return nil, "", errors.Wrap(err, "image.Encode")
+	}
+
+	return buf.Bytes(), ext, nil
+}
+
+func cropSquare(src image.Image) image.Rectangle {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	return image.Rect(x0, y0, x0+side, y0+side)
+}
+
+// scaleSquare scales the square area of src down to size x size by averaging
+// source pixels covered by each destination pixel, smaller images are kept as is.
+func scaleSquare(src image.Image, area image.Rectangle, size int) *image.RGBA {
+	side := area.Dx()
+	if side < size {
+		size = side
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		sy0 := area.Min.Y + y*side/size
+		sy1 := max(area.Min.Y+(y+1)*side/size, sy0+1)
+
+		for x := 0; x < size; x++ {
+			sx0 := area.Min.X + x*side/size
+			sx1 := max(area.Min.X+(x+1)*side/size, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
@@ -1,8 +1,6 @@
 package utils
 
 import (
-	"crypto/rsa"
-	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -15,19 +13,68 @@ import (
 	"github.com/google/uuid"
 )
 
-// KeyManager manages private key and public key
-type KeyManager struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-}
+// TokenUse tells what a token may be used for, so a refresh token is never accepted as access token and vice versa.
+type TokenUse string
+
+const (
+	TokenUseAccess  TokenUse = "access"
+	TokenUseRefresh TokenUse = "refresh"
+	// TokenUseMFAChallenge proves the password was checked, it is only exchanged for tokens with a second factor
+	TokenUseMFAChallenge TokenUse = "mfa_challenge"
+	// TokenUsePersonalAccess marks claims built from a personal access token, they are never signed
+	TokenUsePersonalAccess TokenUse = "personal_access"
+)
 
 type UserClaims struct {
-	AccountID int64  `json:"id"`
-	Email     string `json:"email"`
+	AccountID int64    `json:"id"`
+	Email     string   `json:"email"`
+	TokenUse  TokenUse `json:"token_use"`
+	// SessionID is the refresh token family the token was issued for
+	SessionID string `json:"sid,omitempty"`
+	// Role is the role of account when the token was issued, permission checks read the current one
+	Role entities.Role `json:"role,omitempty"`
+	// Scopes limits what a personal access token may do
+	Scopes []string `json:"scopes,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func newUserClaims(id int64, email string, duration time.Duration) (*UserClaims, *dto.ErrorResponse) {
+// HasScope tells whether claims allow scope, only personal access tokens are limited.
+func (c *UserClaims) HasScope(scope string) bool {
+	if c.TokenUse != TokenUsePersonalAccess {
+		return true
+	}
+
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RevocationKeys returns ids checked against revocation list, the token itself and its session.
+func (c *UserClaims) RevocationKeys() []string {
+	keys := []string{TokenRevocationKey(c.ID)}
+
+	if c.SessionID != "" {
+		keys = append(keys, SessionRevocationKey(c.SessionID))
+	}
+
+	return keys
+}
+
+// TokenRevocationKey is the revocation list id of a single token.
+func TokenRevocationKey(tokenID string) string {
+	return "jti:" + tokenID
+}
+
+// SessionRevocationKey is the revocation list id of every token issued for a session.
+func SessionRevocationKey(sessionID string) string {
+	return "sid:" + sessionID
+}
+
+func newUserClaims(id int64, email string, sessionID string, use TokenUse, duration time.Duration) (*UserClaims, *dto.ErrorResponse) {
 	tokenID, err := uuid.NewRandom()
 
 	if err != nil {
@@ -37,8 +84,12 @@ func newUserClaims(id int64, email string, duration time.Duration) (*UserClaims,
 	return &UserClaims{
 		AccountID: id,
 		Email:     email,
+		TokenUse:  use,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        tokenID.String(),
+			Issuer:    tokenIssuer(),
+			Audience:  jwt.ClaimStrings{tokenAudience()},
 			Subject:   email,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -46,91 +97,170 @@ func newUserClaims(id int64, email string, duration time.Duration) (*UserClaims,
 	}, nil
 }
 
-// LoadKeys read jwtRSA256.key file and jwtRSA256.key.pub file
-func LoadKeys() (*KeyManager, error) {
-	privateKeyData, err := os.ReadFile(os.Getenv("PRIVATE_KEY_PATH"))
+func GenerateToken(payload *entities.Account, sessionID string, k *KeyManager, expireAccessToken, expireRefreshToken int) (string, string, *dto.ErrorResponse) {
+	claimsAccessToken, errClaims := newUserClaims(payload.ID, payload.Email, sessionID, TokenUseAccess, time.Duration(expireAccessToken)*time.Minute)
 
-	if err != nil {
-		return nil, errors.Wrap(err, "os.ReadFile")
+	if errClaims != nil {
+		return "", "", errClaims
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyData)
+	claimsAccessToken.Role = payload.Role
 
-	if err != nil {
-		return nil, errors.Wrap(err, "jwt.ParseRSAPrivateKeyFromPEM")
+	claimsRefreshToken, errClaims := newUserClaims(payload.ID, payload.Email, sessionID, TokenUseRefresh, time.Duration(expireRefreshToken)*24*time.Hour)
+
+	if errClaims != nil {
+		return "", "", errClaims
 	}
 
-	publicKeyData, err := os.ReadFile(os.Getenv("PUBLIC_KEY_PATH"))
+	accessToken, err := k.sign(claimsAccessToken)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "os.ReadFile")
+		return "", "", &dto.ErrorResponse{Message: INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
 
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyData)
+	refreshToken, err := k.sign(claimsRefreshToken)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "jwt.ParseRSAPublicKeyFromPEM")
+		return "", "", &dto.ErrorResponse{Message: INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
 
-	fmt.Println("✅ Keys loaded successfully!")
-	return &KeyManager{
-		privateKey: privateKey,
-		publicKey:  publicKey,
-	}, nil
+	return accessToken, refreshToken, nil
 }
 
-func GenerateToken(payload *entities.Account, k *KeyManager, expireAccessToken, expireRefreshToken int) (string, string, *dto.ErrorResponse) {
-	claimsAccessToken, errClaims := newUserClaims(payload.ID, payload.Email, time.Duration(expireAccessToken)*time.Minute)
+// GenerateMFAChallengeToken returns token exchanged for real tokens once the second factor is checked.
+func GenerateMFAChallengeToken(payload *entities.Account, k *KeyManager, duration time.Duration) (string, *dto.ErrorResponse) {
+	claims, errClaims := newUserClaims(payload.ID, payload.Email, "", TokenUseMFAChallenge, duration)
 
 	if errClaims != nil {
-		return "", "", errClaims
+		return "", errClaims
 	}
 
-	claimsRefreshToken, errClaims := newUserClaims(payload.ID, payload.Email, time.Duration(expireRefreshToken)*24*time.Hour)
+	token, err := k.sign(claims)
 
-	if errClaims != nil {
-		return "", "", errClaims
+	if err != nil {
+		return "", &dto.ErrorResponse{Message: INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
 	}
 
-	accessTokenJWT := jwt.NewWithClaims(jwt.SigningMethodRS256, claimsAccessToken)
-	refreshTokenJWT := jwt.NewWithClaims(jwt.SigningMethodRS256, claimsRefreshToken)
+	return token, nil
+}
 
-	accessToken, err := accessTokenJWT.SignedString(k.privateKey)
+// ValidateToken checks signature, expiry, issuer, audience and that the token is meant for use.
+func ValidateToken(tokenStr string, k *KeyManager, use TokenUse) (*UserClaims, *dto.ErrorResponse) {
+	token, err := jwt.ParseWithClaims(tokenStr, &UserClaims{}, k.keyFunc,
+		jwt.WithIssuer(tokenIssuer()),
+		jwt.WithAudience(tokenAudience()),
+		jwt.WithLeeway(tokenLeeway()),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
-		return "", "", &dto.ErrorResponse{Message: INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
+		return nil, &dto.ErrorResponse{Message: err.Error(), Code: http.StatusUnauthorized}
 	}
 
-	refreshToken, err := refreshTokenJWT.SignedString(k.privateKey)
+	if !token.Valid {
+		return nil, &dto.ErrorResponse{Message: "invalid token", Code: http.StatusUnauthorized}
+	}
+
+	claims, _ := token.Claims.(*UserClaims)
+
+	if claims.TokenUse != use {
+		return nil, &dto.ErrorResponse{Message: "token can not be used as " + string(use) + " token", Code: http.StatusUnauthorized}
+	}
+
+	return claims, nil
+}
+
+func tokenIssuer() string {
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "ytb-video-sharing-app-be"
+}
+
+func tokenAudience() string {
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		return audience
+	}
+	return "ytb-video-sharing-app"
+}
+
+// tokenLeeway tolerates clock skew between the issuer and verifiers when checking exp, nbf and iat.
+func tokenLeeway() time.Duration {
+	leeway, err := time.ParseDuration(os.Getenv("JWT_LEEWAY"))
+	if err != nil || leeway < 0 {
+		return 30 * time.Second // default to 30 seconds if not set or invalid
+	}
+	return leeway
+}
+
+// emailVerificationPurpose marks tokens that may only be used to verify an email.
+const emailVerificationPurpose = "email_verification"
+
+// EmailVerificationClaims are signed with HMAC secret instead of the signing keys, so they are never accepted by ValidateToken.
+type EmailVerificationClaims struct {
+	AccountID int64  `json:"id"`
+	Email     string `json:"email"`
+	Purpose   string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+func emailVerificationSecret() ([]byte, error) {
+	secret := os.Getenv("EMAIL_VERIFICATION_SECRET")
+
+	if secret == "" {
+		return nil, errors.New("EMAIL_VERIFICATION_SECRET is not set")
+	}
+
+	return []byte(secret), nil
+}
+
+// GenerateEmailVerificationToken returns signed token used in the email verification link.
+func GenerateEmailVerificationToken(payload *entities.Account, duration time.Duration) (string, error) {
+	secret, err := emailVerificationSecret()
 
 	if err != nil {
-		return "", "", &dto.ErrorResponse{Message: INTERNAL_SERVER_ERROR, Code: http.StatusInternalServerError}
+		return "", err
 	}
 
-	return accessToken, refreshToken, nil
+	claims := &EmailVerificationClaims{
+		AccountID: payload.ID,
+		Email:     payload.Email,
+		Purpose:   emailVerificationPurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   payload.Email,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
 }
 
-func ValidateToken(tokenStr string, k *KeyManager) (*UserClaims, *dto.ErrorResponse) {
-	token, err := jwt.ParseWithClaims(tokenStr, &UserClaims{}, func(t *jwt.Token) (interface{}, error) {
-		// Verify the signing method
-		_, ok := t.Method.(*jwt.SigningMethodRSA)
+// ValidateEmailVerificationToken checks signature, expiry and purpose of email verification token.
+func ValidateEmailVerificationToken(tokenStr string) (*EmailVerificationClaims, error) {
+	secret, err := emailVerificationSecret()
 
-		if !ok {
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.ParseWithClaims(tokenStr, &EmailVerificationClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("invalid signing method")
 		}
 
-		return k.publicKey, nil
+		return secret, nil
 	})
 
 	if err != nil {
-		return nil, &dto.ErrorResponse{Message: err.Error(), Code: http.StatusUnauthorized}
+		return nil, err
 	}
 
-	if !token.Valid {
-		return nil, &dto.ErrorResponse{Message: "invalid token", Code: http.StatusUnauthorized}
-	}
+	claims, ok := token.Claims.(*EmailVerificationClaims)
 
-	claims, _ := token.Claims.(*UserClaims)
+	if !ok || !token.Valid || claims.Purpose != emailVerificationPurpose {
+		return nil, errors.New("invalid token")
+	}
 
 	return claims, nil
 }
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupKeyManager(t *testing.T) *KeyManager {
+	dir := t.TempDir()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, "test.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	t.Setenv("JWT_KEYS_DIR", dir)
+
+	k, err := LoadKeys()
+	require.NoError(t, err)
+
+	return k
+}
+
+func signClaims(t *testing.T, k *KeyManager, claims *UserClaims) string {
+	signed, err := k.sign(claims)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestValidateTokenRejectsCrossUse(t *testing.T) {
+	k := setupKeyManager(t)
+	accessToken, refreshToken, errRe := GenerateToken(&entities.Account{ID: 1, Email: "test@example.com", Role: entities.RoleModerator}, "session", k, 5, 1)
+	require.Nil(t, errRe)
+
+	t.Run("Should accept access token as access token", func(t *testing.T) {
+		claims, errRe := ValidateToken(accessToken, k, TokenUseAccess)
+
+		assert.Nil(t, errRe)
+		assert.Equal(t, int64(1), claims.AccountID)
+		assert.Equal(t, TokenUseAccess, claims.TokenUse)
+		assert.Equal(t, entities.RoleModerator, claims.Role)
+	})
+
+	t.Run("Should accept refresh token as refresh token", func(t *testing.T) {
+		claims, errRe := ValidateToken(refreshToken, k, TokenUseRefresh)
+
+		assert.Nil(t, errRe)
+		assert.Equal(t, TokenUseRefresh, claims.TokenUse)
+	})
+
+	t.Run("Should reject refresh token as access token", func(t *testing.T) {
+		claims, errRe := ValidateToken(refreshToken, k, TokenUseAccess)
+
+		assert.Nil(t, claims)
+		assert.NotNil(t, errRe)
+	})
+
+	t.Run("Should reject access token as refresh token", func(t *testing.T) {
+		claims, errRe := ValidateToken(accessToken, k, TokenUseRefresh)
+
+		assert.Nil(t, claims)
+		assert.NotNil(t, errRe)
+	})
+
+	t.Run("Should reject mfa challenge token as access token", func(t *testing.T) {
+		challengeToken, errRe := GenerateMFAChallengeToken(&entities.Account{ID: 1, Email: "test@example.com"}, k, time.Minute)
+		require.Nil(t, errRe)
+
+		claims, errRe := ValidateToken(challengeToken, k, TokenUseAccess)
+
+		assert.Nil(t, claims)
+		assert.NotNil(t, errRe)
+	})
+
+	t.Run("Should reject token without token_use", func(t *testing.T) {
+		claims, errClaims := newUserClaims(1, "test@example.com", "session", "", time.Minute)
+		require.Nil(t, errClaims)
+
+		_, errRe := ValidateToken(signClaims(t, k, claims), k, TokenUseAccess)
+
+		assert.NotNil(t, errRe)
+	})
+}
+
+func TestValidateTokenRejectsForeignIssuerAndAudience(t *testing.T) {
+	k := setupKeyManager(t)
+
+	t.Run("Should reject token of another audience", func(t *testing.T) {
+		claims, errClaims := newUserClaims(1, "test@example.com", "session", TokenUseAccess, time.Minute)
+		require.Nil(t, errClaims)
+		claims.Audience = jwt.ClaimStrings{"another-service"}
+
+		_, errRe := ValidateToken(signClaims(t, k, claims), k, TokenUseAccess)
+
+		assert.NotNil(t, errRe)
+	})
+
+	t.Run("Should reject token of another issuer", func(t *testing.T) {
+		claims, errClaims := newUserClaims(1, "test@example.com", "session", TokenUseAccess, time.Minute)
+		require.Nil(t, errClaims)
+		claims.Issuer = "another-issuer"
+
+		_, errRe := ValidateToken(signClaims(t, k, claims), k, TokenUseAccess)
+
+		assert.NotNil(t, errRe)
+	})
+}
+
+func TestValidateTokenLeeway(t *testing.T) {
+	k := setupKeyManager(t)
+
+	claims, errClaims := newUserClaims(1, "test@example.com", "session", TokenUseAccess, -10*time.Second)
+	require.Nil(t, errClaims)
+	expiredToken := signClaims(t, k, claims)
+
+	t.Run("Should accept token expired within leeway", func(t *testing.T) {
+		t.Setenv("JWT_LEEWAY", "30s")
+
+		_, errRe := ValidateToken(expiredToken, k, TokenUseAccess)
+
+		assert.Nil(t, errRe)
+	})
+
+	t.Run("Should reject token expired beyond leeway", func(t *testing.T) {
+		t.Setenv("JWT_LEEWAY", "0s")
+
+		_, errRe := ValidateToken(expiredToken, k, TokenUseAccess)
+
+		assert.NotNil(t, errRe)
+	})
+}
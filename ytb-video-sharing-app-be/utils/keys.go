@@ -0,0 +1,472 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+)
+
+// activeKeyFile names the file in JWT_KEYS_DIR holding kid of the key used for signing.
+const activeKeyFile = "active"
+
+// signingKey is one key of the key set, private is nil for keys kept only to verify tokens already issued.
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// keySet is immutable, reloading swaps the whole set.
+type keySet struct {
+	keys      map[string]*signingKey
+	activeKID string
+}
+
+// KeyManager manages keys used to sign and verify tokens.
+//
+// Keys are loaded from JWT_KEYS_DIR:
+//   - <kid>.key is a PEM private key (RSA, ECDSA or Ed25519), used to sign and verify
+//   - <kid>.pub is a PEM public key of a retired key, only used to verify tokens it signed
+//   - active optionally holds kid of the signing key, otherwise the greatest kid with a private key signs
+//
+// When JWT_KEYS_DIR is not set, the single RSA key pair of PRIVATE_KEY_PATH and PUBLIC_KEY_PATH is used.
+type KeyManager struct {
+	mu  sync.RWMutex
+	set *keySet
+	dir string
+}
+
+// LoadKeys read keys of JWT_KEYS_DIR, or jwtRSA256.key file and jwtRSA256.key.pub file
+func LoadKeys() (*KeyManager, error) {
+	dir := os.Getenv("JWT_KEYS_DIR")
+
+	var (
+		set *keySet
+		err error
+	)
+
+	if dir != "" {
+		set, err = loadKeySet(dir)
+	} else {
+		set, err = loadLegacyKeySet(os.Getenv("PRIVATE_KEY_PATH"), os.Getenv("PUBLIC_KEY_PATH"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("✅ Keys loaded successfully!")
+	return &KeyManager{
+		set: set,
+		dir: dir,
+	}, nil
+}
+
+// Watch reloads keys every interval until ctx is done, a broken key directory keeps the previous keys.
+// Blocking, so run as a Goroutine.
+func (k *KeyManager) Watch(ctx context.Context, interval time.Duration) {
+	if k.dir == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			set, err := loadKeySet(k.dir)
+
+			if err != nil {
+				log.Println("error when reloading jwt keys: ", err)
+				continue
+			}
+
+			k.mu.Lock()
+			if set.activeKID != k.set.activeKID {
+				log.Println("jwt signing key rotated to ", set.activeKID)
+			}
+			k.set = set
+			k.mu.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (k *KeyManager) current() *keySet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.set
+}
+
+// activeKey returns key used to sign new tokens.
+func (k *KeyManager) activeKey() *signingKey {
+	set := k.current()
+
+	return set.keys[set.activeKID]
+}
+
+// sign signs claims with the active key, kid header tells verifiers which key to use.
+func (k *KeyManager) sign(claims jwt.Claims) (string, error) {
+	key := k.activeKey()
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	return token.SignedString(key.private)
+}
+
+// verificationKeys returns keys a token may be verified with, tokens issued before key rotation have no kid.
+func (k *KeyManager) verificationKeys(kid string) []*signingKey {
+	set := k.current()
+
+	if kid != "" {
+		if key, ok := set.keys[kid]; ok {
+			return []*signingKey{key}
+		}
+
+		return nil
+	}
+
+	keys := make([]*signingKey, 0, len(set.keys))
+	for _, key := range set.keys {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// keyFunc resolves keys verifying the token from its kid header.
+func (k *KeyManager) keyFunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	keys := k.verificationKeys(kid)
+
+	if len(keys) == 0 {
+		return nil, errors.New("unknown signing key")
+	}
+
+	// Verify the signing method matches the key, never trust alg of the token alone
+	verifyKeys := jwt.VerificationKeySet{}
+	for _, key := range keys {
+		if key.method.Alg() == t.Method.Alg() {
+			verifyKeys.Keys = append(verifyKeys.Keys, key.public)
+		}
+	}
+
+	if len(verifyKeys.Keys) == 0 {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return verifyKeys, nil
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC and OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKSet is the body of jwks.json.
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every public key that verifies tokens, sorted by kid.
+func (k *KeyManager) JWKS() JWKSet {
+	set := k.current()
+
+	jwks := JWKSet{Keys: make([]JWK, 0, len(set.keys))}
+	for _, key := range set.keys {
+		jwk, err := toJWK(key)
+
+		if err != nil {
+			log.Println("error when encoding jwk: ", err)
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].Kid < jwks.Keys[j].Kid })
+
+	return jwks
+}
+
+func toJWK(key *signingKey) (JWK, error) {
+	jwk := JWK{Kid: key.kid, Use: "sig", Alg: key.method.Alg()}
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		ecdhKey, err := pub.ECDH()
+
+		if err != nil {
+			return JWK{}, err
+		}
+
+		// uncompressed point is 0x04 || X || Y, coordinates padded to curve size
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encode(point[:size])
+		jwk.Y = encode(point[size:])
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encode(pub)
+	default:
+		return JWK{}, errors.Errorf("unsupported key type %T", key.public)
+	}
+
+	return jwk, nil
+}
+
+func loadKeySet(dir string) (*keySet, error) {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "os.ReadDir")
+	}
+
+	set := &keySet{keys: make(map[string]*signingKey)}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+
+		if entry.IsDir() || (ext != ".key" && ext != ".pub") {
+			continue
+		}
+
+		kid := strings.TrimSuffix(name, ext)
+
+		// private key wins over public key of the same kid
+		if existing, ok := set.keys[kid]; ok && existing.private != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+
+		if err != nil {
+			return nil, errors.Wrap(err, "os.ReadFile")
+		}
+
+		var key *signingKey
+		if ext == ".key" {
+			key, err = parsePrivateKey(kid, data)
+		} else {
+			key, err = parsePublicKey(kid, data)
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse key %s", name)
+		}
+
+		set.keys[kid] = key
+	}
+
+	set.activeKID, err = resolveActiveKID(dir, set)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func resolveActiveKID(dir string, set *keySet) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, activeKeyFile))
+
+	if err == nil {
+		kid := strings.TrimSpace(string(data))
+
+		if key, ok := set.keys[kid]; !ok || key.private == nil {
+			return "", errors.Errorf("active key %q has no private key", kid)
+		}
+
+		return kid, nil
+	}
+
+	if !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "os.ReadFile")
+	}
+
+	activeKID := ""
+	for kid, key := range set.keys {
+		if key.private != nil && kid > activeKID {
+			activeKID = kid
+		}
+	}
+
+	if activeKID == "" {
+		return "", errors.New("no private key found in " + dir)
+	}
+
+	return activeKID, nil
+}
+
+func loadLegacyKeySet(privateKeyPath, publicKeyPath string) (*keySet, error) {
+	privateKeyData, err := os.ReadFile(privateKeyPath)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "os.ReadFile")
+	}
+
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privateKeyData)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "jwt.ParseRSAPrivateKeyFromPEM")
+	}
+
+	publicKeyData, err := os.ReadFile(publicKeyPath)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "os.ReadFile")
+	}
+
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicKeyData)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "jwt.ParseRSAPublicKeyFromPEM")
+	}
+
+	const kid = "default"
+
+	return &keySet{
+		keys: map[string]*signingKey{
+			kid: {kid: kid, method: jwt.SigningMethodRS256, private: privateKey, public: publicKey},
+		},
+		activeKID: kid,
+	}, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+
+	if !ok {
+		return nil, errors.Errorf("unsupported key type %T", key)
+	}
+
+	method, err := signingMethod(signer.Public())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{kid: kid, method: method, private: signer, public: signer.Public()}, nil
+}
+
+func parsePublicKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	if block.Type == "RSA PUBLIC KEY" {
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := signingMethod(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &signingKey{kid: kid, method: method, public: key}, nil
+}
+
+// signingMethod picks the algorithm from key type, so a key is never used with another algorithm.
+func signingMethod(public crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+
+		return nil, errors.Errorf("unsupported curve %s", pub.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, errors.Errorf("unsupported key type %T", public)
+}
@@ -0,0 +1,425 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateRSAKey(t *testing.T) crypto.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return key
+}
+
+func generateECKey(t *testing.T, curve elliptic.Curve) crypto.Signer {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func generateEd25519Key(t *testing.T) crypto.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func writePrivateKey(t *testing.T, dir string, kid string, key crypto.Signer) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, kid+".key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	require.NoError(t, err)
+}
+
+func writePublicKey(t *testing.T, dir string, kid string, key crypto.PublicKey) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, kid+".pub"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o644)
+	require.NoError(t, err)
+}
+
+func writeActiveKID(t *testing.T, dir string, kid string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, activeKeyFile), []byte(kid+"\n"), 0o644))
+}
+
+func loadKeysFromDir(t *testing.T, dir string) *KeyManager {
+	t.Setenv("JWT_KEYS_DIR", dir)
+
+	k, err := LoadKeys()
+	require.NoError(t, err)
+
+	return k
+}
+
+func issueAccessToken(t *testing.T, k *KeyManager) string {
+	accessToken, _, errRe := GenerateToken(&entities.Account{ID: 1, Email: "test@example.com", Role: entities.RoleUser}, "session", k, 5, 1)
+	require.Nil(t, errRe)
+
+	return accessToken
+}
+
+func tokenKID(t *testing.T, tokenStr string) string {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenStr, &UserClaims{})
+	require.NoError(t, err)
+
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestLoadKeySet(t *testing.T) {
+	t.Run("Should load every key of directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "2024-01", generateRSAKey(t))
+		writePrivateKey(t, dir, "2024-02", generateECKey(t, elliptic.P256()))
+		writePrivateKey(t, dir, "2024-03", generateEd25519Key(t))
+		writePublicKey(t, dir, "2023-12", generateECKey(t, elliptic.P384()).Public())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a key"), 0o644))
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "archive.key"), 0o755))
+
+		set, err := loadKeySet(dir)
+
+		require.NoError(t, err)
+		assert.Len(t, set.keys, 4)
+		assert.Equal(t, jwt.SigningMethodRS256, set.keys["2024-01"].method)
+		assert.Equal(t, jwt.SigningMethodES256, set.keys["2024-02"].method)
+		assert.Equal(t, jwt.SigningMethodEdDSA, set.keys["2024-03"].method)
+		assert.Equal(t, jwt.SigningMethodES384, set.keys["2023-12"].method)
+		assert.Nil(t, set.keys["2023-12"].private)
+	})
+
+	t.Run("Should prefer private key over public key of the same kid", func(t *testing.T) {
+		dir := t.TempDir()
+		key := generateEd25519Key(t)
+		writePrivateKey(t, dir, "a", key)
+		writePublicKey(t, dir, "a", key.Public())
+
+		set, err := loadKeySet(dir)
+
+		require.NoError(t, err)
+		assert.NotNil(t, set.keys["a"].private)
+	})
+
+	t.Run("Should return error if a key is broken", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "a", generateEd25519Key(t))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.key"), []byte("garbage"), 0o600))
+
+		_, err := loadKeySet(dir)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error if directory does not exist", func(t *testing.T) {
+		_, err := loadKeySet(filepath.Join(t.TempDir(), "missing"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveActiveKID(t *testing.T) {
+	t.Run("Should pick greatest kid with a private key", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "2024-01", generateEd25519Key(t))
+		writePrivateKey(t, dir, "2024-02", generateEd25519Key(t))
+		writePublicKey(t, dir, "2024-03", generateEd25519Key(t).Public())
+
+		set, err := loadKeySet(dir)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2024-02", set.activeKID)
+	})
+
+	t.Run("Should pick kid of active file", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "2024-01", generateEd25519Key(t))
+		writePrivateKey(t, dir, "2024-02", generateEd25519Key(t))
+		writeActiveKID(t, dir, "2024-01")
+
+		set, err := loadKeySet(dir)
+
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01", set.activeKID)
+	})
+
+	t.Run("Should return error if active key has no private key", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "2024-01", generateEd25519Key(t))
+		writePublicKey(t, dir, "2024-02", generateEd25519Key(t).Public())
+		writeActiveKID(t, dir, "2024-02")
+
+		_, err := loadKeySet(dir)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error if active key is unknown", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "2024-01", generateEd25519Key(t))
+		writeActiveKID(t, dir, "2024-09")
+
+		_, err := loadKeySet(dir)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error without any private key", func(t *testing.T) {
+		dir := t.TempDir()
+		writePublicKey(t, dir, "2024-01", generateEd25519Key(t).Public())
+
+		_, err := loadKeySet(dir)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadLegacyKeys(t *testing.T) {
+	dir := t.TempDir()
+	privateKey := generateRSAKey(t).(*rsa.PrivateKey)
+
+	privateKeyPath := filepath.Join(dir, "jwtRSA256.key")
+	publicKeyPath := filepath.Join(dir, "jwtRSA256.key.pub")
+	require.NoError(t, os.WriteFile(privateKeyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}), 0o600))
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644))
+
+	t.Setenv("JWT_KEYS_DIR", "")
+
+	t.Run("Should fall back to PRIVATE_KEY_PATH and PUBLIC_KEY_PATH", func(t *testing.T) {
+		t.Setenv("PRIVATE_KEY_PATH", privateKeyPath)
+		t.Setenv("PUBLIC_KEY_PATH", publicKeyPath)
+
+		k, err := LoadKeys()
+		require.NoError(t, err)
+
+		assert.Equal(t, "default", k.activeKey().kid)
+		assert.Equal(t, jwt.SigningMethodRS256, k.activeKey().method)
+
+		_, errRe := ValidateToken(issueAccessToken(t, k), k, TokenUseAccess)
+		assert.Nil(t, errRe)
+	})
+
+	t.Run("Should verify token issued without kid", func(t *testing.T) {
+		t.Setenv("PRIVATE_KEY_PATH", privateKeyPath)
+		t.Setenv("PUBLIC_KEY_PATH", publicKeyPath)
+
+		k, err := LoadKeys()
+		require.NoError(t, err)
+
+		claims, errClaims := newUserClaims(1, "test@example.com", "session", TokenUseAccess, time.Minute)
+		require.Nil(t, errClaims)
+
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+		require.NoError(t, err)
+
+		_, errRe := ValidateToken(signed, k, TokenUseAccess)
+		assert.Nil(t, errRe)
+	})
+
+	t.Run("Should return error if private key is missing", func(t *testing.T) {
+		t.Setenv("PRIVATE_KEY_PATH", filepath.Join(dir, "missing.key"))
+		t.Setenv("PUBLIC_KEY_PATH", publicKeyPath)
+
+		_, err := LoadKeys()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Run("Should sign with new key and keep verifying tokens of old key", func(t *testing.T) {
+		dir := t.TempDir()
+		keyA := generateECKey(t, elliptic.P256())
+		writePrivateKey(t, dir, "a", keyA)
+
+		k := loadKeysFromDir(t, dir)
+		oldToken := issueAccessToken(t, k)
+		assert.Equal(t, "a", tokenKID(t, oldToken))
+
+		// rotate: b signs from now on, a is retired to its public key
+		writePrivateKey(t, dir, "b", generateEd25519Key(t))
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.key")))
+		writePublicKey(t, dir, "a", keyA.Public())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go k.Watch(ctx, 10*time.Millisecond)
+
+		assert.Eventually(t, func() bool { return k.activeKey().kid == "b" }, time.Second, 10*time.Millisecond)
+
+		newToken := issueAccessToken(t, k)
+		assert.Equal(t, "b", tokenKID(t, newToken))
+
+		_, errRe := ValidateToken(oldToken, k, TokenUseAccess)
+		assert.Nil(t, errRe)
+
+		_, errRe = ValidateToken(newToken, k, TokenUseAccess)
+		assert.Nil(t, errRe)
+	})
+
+	t.Run("Should reject tokens of a key removed from directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "a", generateEd25519Key(t))
+
+		k := loadKeysFromDir(t, dir)
+		oldToken := issueAccessToken(t, k)
+
+		writePrivateKey(t, dir, "b", generateEd25519Key(t))
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.key")))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go k.Watch(ctx, 10*time.Millisecond)
+
+		assert.Eventually(t, func() bool { return k.activeKey().kid == "b" }, time.Second, 10*time.Millisecond)
+
+		_, errRe := ValidateToken(oldToken, k, TokenUseAccess)
+		assert.NotNil(t, errRe)
+	})
+
+	t.Run("Should keep previous keys if directory breaks", func(t *testing.T) {
+		dir := t.TempDir()
+		writePrivateKey(t, dir, "a", generateEd25519Key(t))
+
+		k := loadKeysFromDir(t, dir)
+		previous := k.current()
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "b.key"), []byte("garbage"), 0o600))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go k.Watch(ctx, 10*time.Millisecond)
+
+		assert.Never(t, func() bool { return k.current() != previous }, 100*time.Millisecond, 10*time.Millisecond)
+
+		_, errRe := ValidateToken(issueAccessToken(t, k), k, TokenUseAccess)
+		assert.Nil(t, errRe)
+	})
+}
+
+// publicKeyFromJWK decodes jwk back to a public key, as a verifier reading jwks.json would.
+func publicKeyFromJWK(t *testing.T, jwk JWK) crypto.PublicKey {
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		require.NoError(t, err)
+		return b
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(decode(jwk.N)), E: int(new(big.Int).SetBytes(decode(jwk.E)).Int64())}
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		require.Contains(t, curves, jwk.Crv)
+
+		size := (curves[jwk.Crv].Params().BitSize + 7) / 8
+		x, y := decode(jwk.X), decode(jwk.Y)
+		require.Len(t, x, size)
+		require.Len(t, y, size)
+
+		return &ecdsa.PublicKey{Curve: curves[jwk.Crv], X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	case "OKP":
+		require.Equal(t, "Ed25519", jwk.Crv)
+		return ed25519.PublicKey(decode(jwk.X))
+	}
+
+	t.Fatalf("unexpected kty %s", jwk.Kty)
+	return nil
+}
+
+func TestJWKS(t *testing.T) {
+	keys := map[string]crypto.Signer{
+		"rsa":   generateRSAKey(t),
+		"p256":  generateECKey(t, elliptic.P256()),
+		"p384":  generateECKey(t, elliptic.P384()),
+		"p521":  generateECKey(t, elliptic.P521()),
+		"ed255": generateEd25519Key(t),
+	}
+
+	dir := t.TempDir()
+	for kid, key := range keys {
+		writePrivateKey(t, dir, kid, key)
+	}
+	writePublicKey(t, dir, "retired", generateEd25519Key(t).Public())
+
+	k := loadKeysFromDir(t, dir)
+
+	t.Run("Should publish every key sorted by kid", func(t *testing.T) {
+		jwks := k.JWKS()
+
+		kids := make([]string, 0, len(jwks.Keys))
+		for _, jwk := range jwks.Keys {
+			kids = append(kids, jwk.Kid)
+			assert.Equal(t, "sig", jwk.Use)
+		}
+
+		assert.Equal(t, []string{"ed255", "p256", "p384", "p521", "retired", "rsa"}, kids)
+	})
+
+	t.Run("Should encode key type and curve of each algorithm", func(t *testing.T) {
+		expected := map[string][3]string{
+			"rsa":   {"RSA", "RS256", ""},
+			"p256":  {"EC", "ES256", "P-256"},
+			"p384":  {"EC", "ES384", "P-384"},
+			"p521":  {"EC", "ES512", "P-521"},
+			"ed255": {"OKP", "EdDSA", "Ed25519"},
+		}
+
+		for _, jwk := range k.JWKS().Keys {
+			want, ok := expected[jwk.Kid]
+			if !ok {
+				continue
+			}
+
+			assert.Equal(t, want, [3]string{jwk.Kty, jwk.Alg, jwk.Crv}, jwk.Kid)
+		}
+	})
+
+	t.Run("Should round trip every algorithm", func(t *testing.T) {
+		for _, jwk := range k.JWKS().Keys {
+			key, ok := keys[jwk.Kid]
+			if !ok {
+				continue
+			}
+
+			t.Run(jwk.Kid, func(t *testing.T) {
+				public := publicKeyFromJWK(t, jwk)
+
+				assert.True(t, public.(interface{ Equal(crypto.PublicKey) bool }).Equal(key.Public()))
+
+				// a token signed with the key verifies with the published key alone
+				method, err := signingMethod(key.Public())
+				require.NoError(t, err)
+
+				signed, err := jwt.NewWithClaims(method, jwt.RegisteredClaims{Subject: "1"}).SignedString(key)
+				require.NoError(t, err)
+
+				_, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return public, nil }, jwt.WithValidMethods([]string{jwk.Alg}))
+				assert.NoError(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package utils
+
+import "ytb-video-sharing-app-be/internal/entities"
+
+// Permission is an action beyond managing own account and content.
+type Permission string
+
+const (
+	PermissionDeleteAnyVideo  Permission = "videos:delete_any"
+	PermissionModerateVideos  Permission = "videos:moderate"
+	PermissionRestoreVideos   Permission = "videos:restore"
+	PermissionSuspendAccounts Permission = "accounts:suspend"
+	PermissionManageAccounts  Permission = "accounts:manage"
+	PermissionRestoreAccounts Permission = "accounts:restore"
+	PermissionManageRoles     Permission = "accounts:manage_roles"
+	PermissionViewAuditLogs   Permission = "audit_logs:view"
+)
+
+// rolePermissions is the permission matrix, users have none of them.
+var rolePermissions = map[entities.Role][]Permission{
+	entities.RoleModerator: {
+		PermissionDeleteAnyVideo,
+		PermissionModerateVideos,
+		PermissionRestoreVideos,
+	},
+	entities.RoleAdmin: {
+		PermissionDeleteAnyVideo,
+		PermissionModerateVideos,
+		PermissionRestoreVideos,
+		PermissionSuspendAccounts,
+		PermissionManageAccounts,
+		PermissionRestoreAccounts,
+		PermissionManageRoles,
+		PermissionViewAuditLogs,
+	},
+}
+
+// HasPermission tells whether role is granted permission.
+func HasPermission(role entities.Role, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
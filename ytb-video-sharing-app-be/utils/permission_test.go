@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+	"ytb-video-sharing-app-be/internal/entities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasPermission(t *testing.T) {
+	t.Run("Should grant nothing to users", func(t *testing.T) {
+		assert.False(t, HasPermission(entities.RoleUser, PermissionDeleteAnyVideo))
+		assert.False(t, HasPermission(entities.RoleUser, PermissionViewAuditLogs))
+	})
+
+	t.Run("Should let moderators moderate videos only", func(t *testing.T) {
+		assert.True(t, HasPermission(entities.RoleModerator, PermissionDeleteAnyVideo))
+		assert.True(t, HasPermission(entities.RoleModerator, PermissionModerateVideos))
+		assert.True(t, HasPermission(entities.RoleModerator, PermissionRestoreVideos))
+		assert.False(t, HasPermission(entities.RoleModerator, PermissionSuspendAccounts))
+		assert.False(t, HasPermission(entities.RoleModerator, PermissionRestoreAccounts))
+		assert.False(t, HasPermission(entities.RoleModerator, PermissionViewAuditLogs))
+	})
+
+	t.Run("Should grant everything to admins", func(t *testing.T) {
+		for _, permission := range []Permission{PermissionDeleteAnyVideo, PermissionModerateVideos, PermissionRestoreVideos,
+			PermissionSuspendAccounts, PermissionManageAccounts, PermissionRestoreAccounts, PermissionManageRoles, PermissionViewAuditLogs} {
+			assert.True(t, HasPermission(entities.RoleAdmin, permission), permission)
+		}
+	})
+
+	t.Run("Should grant nothing to unknown roles", func(t *testing.T) {
+		assert.False(t, HasPermission(entities.Role("root"), PermissionDeleteAnyVideo))
+	})
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, entities.RoleAdmin.AtLeast(entities.RoleModerator))
+	assert.True(t, entities.RoleModerator.AtLeast(entities.RoleModerator))
+	assert.False(t, entities.RoleUser.AtLeast(entities.RoleModerator))
+	assert.False(t, entities.Role("").AtLeast(entities.RoleUser))
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, so it is told apart from JWTs and found by secret scanners.
+const PersonalAccessTokenPrefix = "ytbpat_"
+
+// personalAccessTokenVisibleLength is the length of token start kept in plain text.
+const personalAccessTokenVisibleLength = len(PersonalAccessTokenPrefix) + 8
+
+// Scopes a personal access token may be granted, access tokens of a login session have all of them.
+const (
+	ScopeVideosWrite       = "videos:write"
+	ScopeVideosRead        = "videos:read"
+	ScopeNotificationsRead = "notifications:read"
+)
+
+// PersonalAccessTokenScopes lists every valid scope.
+var PersonalAccessTokenScopes = []string{ScopeVideosWrite, ScopeVideosRead, ScopeNotificationsRead}
+
+// GeneratePersonalAccessToken returns a new token together with its visible prefix.
+func GeneratePersonalAccessToken() (string, string, error) {
+	random, err := GenerateRandomToken()
+
+	if err != nil {
+		return "", "", err
+	}
+
+	token := PersonalAccessTokenPrefix + random
+
+	return token, token[:personalAccessTokenVisibleLength], nil
+}
+
+// IsPersonalAccessToken tells whether bearer token is a personal access token rather than a JWT.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// PersonalAccessTokenSessionID is the session id put in claims of personal access token, websocket connections opened with it are closed on revoke.
+func PersonalAccessTokenSessionID(id int64) string {
+	return "pat:" + strconv.FormatInt(id, 10)
+}
@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 	"ytb-video-sharing-app-be/internal/dto"
 
 	"github.com/gin-gonic/gin"
@@ -33,6 +36,20 @@ func PaginatedResponse[T any](ctx *gin.Context, data T, currentPage, limit, tota
 	})
 }
 
+func CursorResponse[T any](ctx *gin.Context, data T, limit int, nextCursor int64, hasMore bool) {
+	ctx.JSON(http.StatusOK, dto.ResponseSuccessCursor[T]{
+		Data: data,
+		Metadata: dto.MetadataWithCursor{
+			Code: http.StatusOK,
+			Cursor: &dto.Cursor{
+				Limit:      limit,
+				NextCursor: nextCursor,
+				HasMore:    hasMore,
+			},
+		},
+	})
+}
+
 func ErrorResponse(ctx *gin.Context, statusCode int, errDetail interface{}) {
 	ctx.JSON(statusCode, dto.ResponseError{
 		Metadata: dto.Metadata{
@@ -41,3 +58,8 @@ func ErrorResponse(ctx *gin.Context, statusCode int, errDetail interface{}) {
 		Error: errDetail,
 	})
 }
+
+// RetryAfter sets Retry-After header in whole seconds, rounded up so the client never retries too early.
+func RetryAfter(ctx *gin.Context, wait time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
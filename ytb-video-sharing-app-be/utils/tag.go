@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxVideoTags is how many tags a video can carry.
+const MaxVideoTags = 5
+
+// MaxTagLength is the longest tag in characters, it matches tags.name.
+const MaxTagLength = 32
+
+// NormalizeTag lowercases tag and collapses its whitespace, false when the result is empty, too long or has
+// characters other than letters, digits, spaces, '-' and '_'.
+func NormalizeTag(tag string) (string, bool) {
+	tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return "", false
+	}
+
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return "", false
+		}
+	}
+
+	return tag, true
+}
+
+// NormalizeTags normalizes every tag and drops duplicates, keeping the order they were given in.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		name, ok := NormalizeTag(tag)
+
+		if !ok {
+			return nil, fmt.Errorf("tag %q must be 1 to %d letters, digits, spaces, '-' or '_'", tag, MaxTagLength)
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	if len(normalized) > MaxVideoTags {
+		return nil, fmt.Errorf("a video can have at most %d tags", MaxVideoTags)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	t.Run("Should lowercase and collapse whitespace", func(t *testing.T) {
+		tag, ok := NormalizeTag("  Lo-Fi   Hip_Hop ")
+
+		assert.True(t, ok)
+		assert.Equal(t, "lo-fi hip_hop", tag)
+	})
+
+	t.Run("Should keep letters of any language", func(t *testing.T) {
+		tag, ok := NormalizeTag("Âm Nhạc")
+
+		assert.True(t, ok)
+		assert.Equal(t, "âm nhạc", tag)
+	})
+
+	t.Run("Should reject empty, too long and punctuated tags", func(t *testing.T) {
+		for _, tag := range []string{"", "   ", "c++", "#music", strings.Repeat("a", MaxTagLength+1)} {
+			_, ok := NormalizeTag(tag)
+			assert.False(t, ok, tag)
+		}
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Run("Should drop duplicates keeping order", func(t *testing.T) {
+		tags, err := NormalizeTags([]string{"Music", "live", "MUSIC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"music", "live"}, tags)
+	})
+
+	t.Run("Should reject more than MaxVideoTags tags", func(t *testing.T) {
+		_, err := NormalizeTags([]string{"a", "b", "c", "d", "e", "f"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should reject invalid tag", func(t *testing.T) {
+		_, err := NormalizeTags([]string{"music", "c++"})
+
+		assert.Error(t, err)
+	})
+}
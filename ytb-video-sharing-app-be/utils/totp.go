@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod is seconds a TOTP code is valid for, as expected by authenticator apps
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew is steps accepted before and after the current one, to tolerate clock drift of the device
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 encoded 160 bits secret.
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI returns the otpauth URI authenticator apps scan as QR code.
+func TOTPProvisioningURI(issuer, accountName, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+accountName) + "?" + query.Encode()
+}
+
+// ValidateTOTP checks code against steps around now, returns the matching step so it can be marked as used.
+func ValidateTOTP(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode computes the RFC 6238 code of step.
+func totpCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for range totpDigits {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// recoveryCodeAlphabet leaves out characters easily mistaken for each other.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes returns n random codes formatted as xxxxx-xxxxx.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+
+	for range n {
+		code := make([]byte, 0, 11)
+
+		for i := range 10 {
+			if i == 5 {
+				code = append(code, '-')
+			}
+
+			index, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+
+			if err != nil {
+				return nil, err
+			}
+
+			code = append(code, recoveryCodeAlphabet[index.Int64()])
+		}
+
+		codes = append(codes, string(code))
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode makes codes typed with spaces or in upper case match the stored hash.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
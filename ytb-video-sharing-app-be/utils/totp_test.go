@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"encoding/base32"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 secret of RFC 6238 test vectors.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestValidateTOTP(t *testing.T) {
+	t.Run("Should accept code of RFC 6238 test vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:         "287082",
+			1111111109: "081804",
+			1234567890: "005924",
+			2000000000: "279037",
+		}
+
+		for unix, code := range vectors {
+			step, ok := ValidateTOTP(rfc6238Secret, code, time.Unix(unix, 0))
+
+			assert.True(t, ok, "code at %d", unix)
+			assert.Equal(t, unix/totpPeriod, step)
+		}
+	})
+
+	t.Run("Should accept code of previous step", func(t *testing.T) {
+		step, ok := ValidateTOTP(rfc6238Secret, "287082", time.Unix(59+totpPeriod, 0))
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), step)
+	})
+
+	t.Run("Should reject code out of skew window", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfc6238Secret, "287082", time.Unix(59+3*totpPeriod, 0))
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Should reject malformed code", func(t *testing.T) {
+		_, ok := ValidateTOTP(rfc6238Secret, "28708", time.Unix(59, 0))
+
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	step := time.Now().Unix() / totpPeriod
+	key, err := totpEncoding.DecodeString(secret)
+	require.NoError(t, err)
+
+	_, ok := ValidateTOTP(secret, totpCode(key, step), time.Now())
+
+	assert.Len(t, key, 20)
+	assert.True(t, ok)
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("YTB Sharing", "test@example.com", "SECRET")
+
+	assert.Equal(t, "otpauth://totp/YTB%20Sharing:test@example.com?algorithm=SHA1&digits=6&issuer=YTB+Sharing&period=30&secret=SECRET", uri)
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	require.NoError(t, err)
+
+	format := regexp.MustCompile(`^[a-z2-9]{5}-[a-z2-9]{5}$`)
+	unique := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, format, code)
+		assert.Equal(t, code, NormalizeRecoveryCode(" "+code+" "))
+		unique[code] = true
+	}
+
+	assert.Len(t, unique, 10)
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// trendingGravity is how fast scores fall as videos get older, higher sinks old videos sooner.
+	trendingGravity = 1.8
+	// trendingCommentWeight is how many votes a comment is worth.
+	trendingCommentWeight = 2
+	// trendingViewWeight is how many votes a recent view is worth.
+	trendingViewWeight = 0.1
+)
+
+// TrendingScore ranks a video by its net votes, comments and recent views, decayed by its age so that newer
+// videos with the same activity rank higher. Videos with more down votes than the rest of their activity is
+// worth score below zero and rise towards it as they age.
+func TrendingScore(upVote, downVote, comments, views int64, age time.Duration) float64 {
+	hours := max(age.Hours(), 0)
+	points := float64(upVote-downVote) + trendingCommentWeight*float64(comments) + trendingViewWeight*float64(views)
+
+	return points / math.Pow(hours+2, trendingGravity)
+}
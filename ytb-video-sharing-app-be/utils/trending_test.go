@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrendingScore(t *testing.T) {
+	t.Run("Should rank newer video above older one with the same votes", func(t *testing.T) {
+		assert.Greater(t, TrendingScore(10, 2, 0, 0, time.Hour), TrendingScore(10, 2, 0, 0, 24*time.Hour))
+	})
+
+	t.Run("Should rank video with more net votes above one of the same age", func(t *testing.T) {
+		assert.Greater(t, TrendingScore(10, 2, 0, 0, time.Hour), TrendingScore(10, 5, 0, 0, time.Hour))
+	})
+
+	t.Run("Should rank video with more comments or views above one with the same votes", func(t *testing.T) {
+		assert.Greater(t, TrendingScore(10, 2, 1, 0, time.Hour), TrendingScore(10, 2, 0, 0, time.Hour))
+		assert.Greater(t, TrendingScore(10, 2, 0, 1, time.Hour), TrendingScore(10, 2, 0, 0, time.Hour))
+	})
+
+	t.Run("Should score below zero with more down votes", func(t *testing.T) {
+		assert.Less(t, TrendingScore(1, 3, 0, 0, time.Hour), 0.0)
+	})
+
+	t.Run("Should treat future shares as just shared", func(t *testing.T) {
+		assert.Equal(t, TrendingScore(4, 0, 0, 0, 0), TrendingScore(4, 0, 0, 0, -time.Hour))
+	})
+}